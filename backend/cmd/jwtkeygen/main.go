@@ -0,0 +1,104 @@
+// Command jwtkeygen manages the access token keys read from JWT_KEYS_DIR.
+//
+// Rotation without downtime:
+//
+//	go run ./cmd/jwtkeygen -dir /keys -kid 2026-10          # 1. add the new key everywhere (verify only)
+//	JWT_ACTIVE_KID=2026-10                                  # 2. switch signing, restart
+//	go run ./cmd/jwtkeygen -dir /keys -retire 2026-04       # 3. keep only the old public key
+//
+// Delete the retired file once ACCESS_TTL_MIN has passed.
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "key directory (JWT_KEYS_DIR)")
+	kid := flag.String("kid", "", "id of the key to generate")
+	alg := flag.String("alg", "rsa", "rsa (RS256) or ed25519 (EdDSA)")
+	retire := flag.String("retire", "", "id of a key to strip down to its public part")
+	flag.Parse()
+
+	switch {
+	case *retire != "":
+		if err := retireKey(filepath.Join(*dir, *retire+".pem")); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("retired", *retire, "(verify only)")
+	case *kid != "":
+		if err := generate(filepath.Join(*dir, *kid+".pem"), *alg); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("generated", *kid)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func generate(path, alg string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	var priv any
+	switch alg {
+	case "rsa":
+		k, err := rsa.GenerateKey(rand.Reader, 3072)
+		if err != nil {
+			return err
+		}
+		priv = k
+	case "ed25519":
+		_, k, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		priv = k
+	default:
+		return fmt.Errorf("unknown alg %q", alg)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+}
+
+func retireKey(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return fmt.Errorf("%s is not a PKCS#8 private key", path)
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	var pub any
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		pub = &k.PublicKey
+	case ed25519.PrivateKey:
+		pub = k.Public()
+	default:
+		return fmt.Errorf("unsupported key type %T", priv)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644)
+}
@@ -0,0 +1,43 @@
+// Command oidcstub serves the stand-in OpenID Connect provider of package
+// oidcstub for local development and end-to-end tests of the SSO login.
+//
+// Point the API at it with:
+//
+//	OIDC_PROVIDERS=stub
+//	OIDC_STUB_ISSUER=http://localhost:9096
+//	OIDC_STUB_CLIENT_ID=confsite
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"confsite/backend/internal/adapters/oidc/oidcstub"
+)
+
+func main() {
+	addr := envOr("OIDC_STUB_ADDR", ":9096")
+	cfg := oidcstub.Config{
+		Issuer:   envOr("OIDC_STUB_ISSUER", "http://localhost:9096"),
+		ClientID: envOr("OIDC_STUB_CLIENT_ID", "confsite"),
+		Email:    envOr("OIDC_STUB_EMAIL", "sso.user@example.org"),
+		Subject:  envOr("OIDC_STUB_SUBJECT", "stub-user-1"),
+	}
+	p, err := oidcstub.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Println("OIDC stub provider for", cfg.Email, "listening on", addr, "issuer", cfg.Issuer)
+	if err := http.ListenAndServe(addr, p.Handler()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
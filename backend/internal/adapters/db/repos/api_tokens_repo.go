@@ -0,0 +1,98 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APITokensRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAPITokensRepo(db *pgxpool.Pool) *APITokensRepo {
+	return &APITokensRepo{db: db}
+}
+
+const apiTokenColumns = `id, user_id, name, prefix, token_hash, scopes, expires_at, last_used_at, last_used_ip, revoked_at, created_at`
+
+func scanAPIToken(row pgx.Row) (*domain.APIToken, error) {
+	var t domain.APIToken
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Prefix, &t.TokenHash, &t.Scopes,
+		&t.ExpiresAt, &t.LastUsedAt, &t.LastUsedIP, &t.RevokedAt, &t.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *APITokensRepo) Create(ctx context.Context, t domain.APIToken) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, `
+INSERT INTO api_tokens (user_id, name, prefix, token_hash, scopes, expires_at)
+VALUES ($1,$2,$3,$4,$5,$6)
+RETURNING id`, t.UserID, t.Name, t.Prefix, t.TokenHash, t.Scopes, t.ExpiresAt).Scan(&id)
+	return id, err
+}
+
+func (r *APITokensRepo) ByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	return scanAPIToken(r.db.QueryRow(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash=$1`, tokenHash))
+}
+
+func (r *APITokensRepo) Get(ctx context.Context, id uuid.UUID) (*domain.APIToken, error) {
+	return scanAPIToken(r.db.QueryRow(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE id=$1`, id))
+}
+
+func (r *APITokensRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIToken, error) {
+	return r.list(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE user_id=$1 ORDER BY created_at DESC`, userID)
+}
+
+func (r *APITokensRepo) ListAll(ctx context.Context) ([]domain.APIToken, error) {
+	return r.list(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens ORDER BY created_at DESC`)
+}
+
+func (r *APITokensRepo) list(ctx context.Context, query string, args ...any) ([]domain.APIToken, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.APIToken{}
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+func (r *APITokensRepo) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	tag, err := r.db.Exec(ctx, `UPDATE api_tokens SET revoked_at=COALESCE(revoked_at, $2) WHERE id=$1`, id, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// TouchUsage records the last use; writes are coalesced to one per minute per token.
+func (r *APITokensRepo) TouchUsage(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+UPDATE api_tokens SET last_used_at=$3, last_used_ip=$2
+WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < $3 - interval '1 minute' OR last_used_ip IS DISTINCT FROM $2)`,
+		id, ip, at)
+	return err
+}
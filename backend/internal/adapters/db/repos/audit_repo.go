@@ -3,48 +3,82 @@
 import (
 	"context"
 
-	"confsite/backend/internal/adapters/db/sqlc"
 	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AuditRepo struct {
-	q  *sqlc.Queries
 	db *pgxpool.Pool
 }
 
 func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
-	return &AuditRepo{q: sqlc.New(db), db: db}
+	return &AuditRepo{db: db}
 }
 
 func (r *AuditRepo) Insert(ctx context.Context, e domain.AuditLog) error {
-	return r.q.InsertAudit(ctx, sqlc.InsertAuditParams{
-		ActorUserID: uuidParam(e.ActorUserID),
-		Action:      e.Action,
-		Entity:      e.Entity,
-		EntityID:    uuidParam(e.EntityID),
-	})
+	_, err := r.db.Exec(ctx, `
+INSERT INTO audit_logs (actor_user_id, action, entity, entity_id, details)
+VALUES ($1,$2,$3,$4,$5)`, e.ActorUserID, e.Action, e.Entity, e.EntityID, e.Details)
+	return err
 }
 
-func (r *AuditRepo) List(ctx context.Context, limit int32) ([]domain.AuditLog, error) {
-	rows, err := r.q.ListAudit(ctx)
+// auditListSorts are the sort keys of List.
+var auditListSorts = map[string]string{
+	"createdAt": "a.created_at",
+	"action":    "a.action",
+	"entity":    "a.entity",
+}
+
+func (r *AuditRepo) List(ctx context.Context, f ports.AuditListFilter) ([]domain.AuditLog, int, error) {
+	var w whereBuilder
+	if f.Action != nil {
+		w.add(`a.action = ?`, *f.Action)
+	}
+	if f.Entity != nil {
+		w.add(`a.entity = ?`, *f.Entity)
+	}
+	if f.ActorUserID != nil {
+		w.add(`a.actor_user_id = ?`, *f.ActorUserID)
+	}
+	if f.EntityID != nil {
+		w.add(`a.entity_id = ?`, *f.EntityID)
+	}
+	if f.From != nil {
+		w.add(`a.created_at >= ?`, *f.From)
+	}
+	if f.To != nil {
+		w.add(`a.created_at < ?`, *f.To)
+	}
+	if f.Search != "" {
+		q := likePattern(f.Search)
+		w.add(`(a.action ILIKE ? OR a.entity ILIKE ? OR actor.email ILIKE ? OR subject.email ILIKE ?)`, q, q, q, q)
+	}
+	where := w.sql()
+	page, err := w.page(f.ListQuery, auditListSorts, "a.created_at DESC", "a.id")
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(ctx, `
+SELECT a.id, a.actor_user_id, a.action, a.entity, a.entity_id, a.details, a.created_at, count(*) OVER ()
+FROM audit_logs a
+LEFT JOIN users actor ON actor.id = a.actor_user_id
+LEFT JOIN users subject ON a.entity = 'user' AND subject.id = a.entity_id
+`+where+`
+`+page, w.args...)
 	if err != nil {
-		return nil, err
-	}
-	if limit > 0 && int(limit) < len(rows) {
-		rows = rows[:limit]
-	}
-	out := make([]domain.AuditLog, 0, len(rows))
-	for _, a := range rows {
-		out = append(out, domain.AuditLog{
-			ID:          a.ID,
-			ActorUserID: uuidPtr(a.ActorUserID),
-			Action:      a.Action,
-			Entity:      a.Entity,
-			EntityID:    uuidPtr(a.EntityID),
-			CreatedAt:   a.CreatedAt,
-		})
-	}
-	return out, nil
+		return nil, 0, err
+	}
+	defer rows.Close()
+	out := []domain.AuditLog{}
+	total := 0
+	for rows.Next() {
+		var a domain.AuditLog
+		if err := rows.Scan(&a.ID, &a.ActorUserID, &a.Action, &a.Entity, &a.EntityID, &a.Details, &a.CreatedAt, &total); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, a)
+	}
+	return out, total, rows.Err()
 }
@@ -0,0 +1,51 @@
+package repos
+
+import (
+	"context"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BouncesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewBouncesRepo(db *pgxpool.Pool) *BouncesRepo {
+	return &BouncesRepo{db: db}
+}
+
+func (r *BouncesRepo) Record(ctx context.Context, b domain.MailBounce) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO mail_bounces (email, kind, reason, source, user_id, received_at)
+VALUES ($1, $2, $3, $4, (SELECT id FROM users WHERE lower(email) = lower($1)), $5)`,
+			b.Email, string(b.Kind), b.Reason, b.Source, b.ReceivedAt); err != nil {
+			return err
+		}
+		switch b.Kind {
+		case domain.BounceHard:
+			_, err := tx.Exec(ctx, `
+UPDATE users SET email_undeliverable_at=COALESCE(email_undeliverable_at, $2), email_bounce_reason=$3, updated_at=now()
+WHERE lower(email) = lower($1)`, b.Email, b.ReceivedAt, b.Reason)
+			return err
+		case domain.BounceComplaint:
+			_, err := tx.Exec(ctx, `
+INSERT INTO mail_unsubscribes (user_id)
+SELECT id FROM users WHERE lower(email) = lower($1)
+ON CONFLICT DO NOTHING`, b.Email)
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *BouncesRepo) Undeliverable(ctx context.Context, email string) (bool, error) {
+	var undeliverable bool
+	err := r.db.QueryRow(ctx, `
+SELECT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($1) AND email_undeliverable_at IS NOT NULL)`,
+		email).Scan(&undeliverable)
+	return undeliverable, err
+}
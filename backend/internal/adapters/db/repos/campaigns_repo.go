@@ -0,0 +1,303 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CampaignsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCampaignsRepo(db *pgxpool.Pool) *CampaignsRepo {
+	return &CampaignsRepo{db: db}
+}
+
+const campaignSelect = `
+SELECT c.id, c.name, c.segment, c.subject_ru, c.body_ru, c.subject_en, c.body_en, c.status,
+  c.created_by, c.created_at, c.updated_at, c.started_at, c.finished_at,
+  count(r.user_id) FILTER (WHERE r.status='PENDING'),
+  count(r.user_id) FILTER (WHERE r.status='SENT'),
+  count(r.user_id) FILTER (WHERE r.status='FAILED'),
+  count(r.user_id) FILTER (WHERE r.status='SKIPPED')
+FROM campaigns c
+LEFT JOIN campaign_recipients r ON r.campaign_id = c.id
+`
+
+func scanCampaign(row pgx.Row) (*domain.Campaign, error) {
+	var c domain.Campaign
+	var status string
+	err := row.Scan(&c.ID, &c.Name, &c.Segment, &c.SubjectRu, &c.BodyRu, &c.SubjectEn, &c.BodyEn, &status,
+		&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.StartedAt, &c.FinishedAt,
+		&c.Pending, &c.Sent, &c.Failed, &c.Skipped)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Status = domain.CampaignStatus(status)
+	return &c, nil
+}
+
+func (r *CampaignsRepo) Create(ctx context.Context, c domain.Campaign) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, `
+INSERT INTO campaigns (name, segment, subject_ru, body_ru, subject_en, body_en, created_by)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+RETURNING id`, c.Name, c.Segment, c.SubjectRu, c.BodyRu, c.SubjectEn, c.BodyEn, c.CreatedBy).Scan(&id)
+	return id, err
+}
+
+func (r *CampaignsRepo) Update(ctx context.Context, c domain.Campaign) error {
+	tag, err := r.db.Exec(ctx, `
+UPDATE campaigns
+SET name=$2, segment=$3, subject_ru=$4, body_ru=$5, subject_en=$6, body_en=$7, updated_at=now()
+WHERE id=$1 AND status='DRAFT'`, c.ID, c.Name, c.Segment, c.SubjectRu, c.BodyRu, c.SubjectEn, c.BodyEn)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.stateError(ctx, c.ID)
+	}
+	return nil
+}
+
+func (r *CampaignsRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM campaigns WHERE id=$1 AND status='DRAFT'`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.stateError(ctx, id)
+	}
+	return nil
+}
+
+// stateError explains why a conditional update matched nothing.
+func (r *CampaignsRepo) stateError(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM campaigns WHERE id=$1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+	return domain.ErrInvalidState
+}
+
+func (r *CampaignsRepo) Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	return scanCampaign(r.db.QueryRow(ctx, campaignSelect+`WHERE c.id=$1 GROUP BY c.id`, id))
+}
+
+func (r *CampaignsRepo) List(ctx context.Context) ([]domain.Campaign, error) {
+	rows, err := r.db.Query(ctx, campaignSelect+`GROUP BY c.id ORDER BY c.created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.Campaign{}
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// recipientPerson selects the personalization fields of the user u.
+const recipientPerson = `u.id, u.email, COALESCE(u.preferred_lang, 'ru'),
+  COALESCE(trim(p.surname || ' ' || p.name || ' ' || p.patronymic), ''), COALESCE(p.name, ''), u.status`
+
+func (r *CampaignsRepo) SegmentRecipients(ctx context.Context, seg domain.CampaignSegment) ([]domain.CampaignRecipient, error) {
+	var w whereBuilder
+	w.add(`u.disabled_at IS NULL`)
+	w.add(`u.email_undeliverable_at IS NULL`)
+	w.add(`NOT EXISTS (SELECT 1 FROM mail_unsubscribes m WHERE m.user_id=u.id)`)
+	if seg.Status != nil {
+		w.add(`u.status = ?`, string(*seg.Status))
+	}
+	if seg.Role != nil {
+		w.add(`EXISTS (SELECT 1 FROM user_roles ur JOIN roles r ON r.id=ur.role_id WHERE ur.user_id=u.id AND r.code=?)`, string(*seg.Role))
+	}
+	if seg.Speakers || seg.SectionID != nil || seg.Kind != nil || seg.TalkStatus != nil {
+		var kind, talkStatus *string
+		if seg.Kind != nil {
+			k := string(*seg.Kind)
+			kind = &k
+		}
+		if seg.TalkStatus != nil {
+			st := string(*seg.TalkStatus)
+			talkStatus = &st
+		}
+		w.add(`EXISTS (SELECT 1 FROM talks t WHERE t.speaker_user_id=u.id AND t.status<>'WITHDRAWN'
+    AND (?::uuid IS NULL OR t.section_id=?) AND (?::text IS NULL OR t.kind=?) AND (?::text IS NULL OR t.status=?))`,
+			seg.SectionID, seg.SectionID, kind, kind, talkStatus, talkStatus)
+	}
+	if seg.ConsentsUploaded != nil {
+		w.add(consentsUploadedSQL+` = ?`, *seg.ConsentsUploaded)
+	}
+	if seg.EmailVerified != nil {
+		w.add(`u.email_verified = ?`, *seg.EmailVerified)
+	}
+
+	rows, err := r.db.Query(ctx, `
+SELECT `+recipientPerson+`
+FROM users u
+LEFT JOIN profiles p ON p.user_id = u.id
+`+w.sql()+`
+ORDER BY u.created_at`, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.CampaignRecipient{}
+	for rows.Next() {
+		var rc domain.CampaignRecipient
+		var status string
+		if err := rows.Scan(&rc.UserID, &rc.Email, &rc.Lang, &rc.FullName, &rc.FirstName, &status); err != nil {
+			return nil, err
+		}
+		rc.UserStatus = domain.UserStatus(status)
+		out = append(out, rc)
+	}
+	return out, rows.Err()
+}
+
+func (r *CampaignsRepo) Start(ctx context.Context, id uuid.UUID, recipients []domain.CampaignRecipient, at time.Time) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+UPDATE campaigns SET status='SENDING', started_at=$2, updated_at=now() WHERE id=$1 AND status='DRAFT'`, id, at)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errCampaignState
+		}
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"campaign_recipients"},
+			[]string{"campaign_id", "user_id", "email", "lang", "unsubscribe_token"},
+			pgx.CopyFromSlice(len(recipients), func(i int) ([]any, error) {
+				rc := recipients[i]
+				return []any{id, rc.UserID, rc.Email, rc.Lang, rc.UnsubscribeToken}, nil
+			}))
+		return err
+	})
+	if errors.Is(err, errCampaignState) {
+		return r.stateError(ctx, id)
+	}
+	return err
+}
+
+// errCampaignState rolls back Start; it is resolved by stateError afterwards.
+var errCampaignState = errors.New("campaign not in draft")
+
+func (r *CampaignsRepo) Cancel(ctx context.Context, id uuid.UUID, at time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+UPDATE campaigns SET status='CANCELLED', finished_at=$2, updated_at=now() WHERE id=$1 AND status='SENDING'`, id, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.stateError(ctx, id)
+	}
+	return nil
+}
+
+func (r *CampaignsRepo) Recipients(ctx context.Context, id uuid.UUID, status string) ([]domain.CampaignRecipient, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT r.user_id, r.email, r.lang, COALESCE(trim(p.surname || ' ' || p.name || ' ' || p.patronymic), ''),
+  r.status, r.error, r.sent_at
+FROM campaign_recipients r
+LEFT JOIN profiles p ON p.user_id = r.user_id
+WHERE r.campaign_id=$1 AND ($2 = '' OR r.status=$2)
+ORDER BY r.email`, id, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.CampaignRecipient{}
+	for rows.Next() {
+		rc := domain.CampaignRecipient{CampaignID: id}
+		if err := rows.Scan(&rc.UserID, &rc.Email, &rc.Lang, &rc.FullName, &rc.Status, &rc.Error, &rc.SentAt); err != nil {
+			return nil, err
+		}
+		out = append(out, rc)
+	}
+	return out, rows.Err()
+}
+
+// NextPending first skips recipients who unsubscribed, were disabled or
+// hard-bounced since the campaign started.
+func (r *CampaignsRepo) NextPending(ctx context.Context, limit int) ([]domain.CampaignRecipient, error) {
+	if _, err := r.db.Exec(ctx, `
+UPDATE campaign_recipients r SET status='SKIPPED'
+FROM users u
+WHERE u.id = r.user_id AND r.status='PENDING'
+  AND (u.disabled_at IS NOT NULL OR u.email_undeliverable_at IS NOT NULL OR EXISTS (SELECT 1 FROM mail_unsubscribes m WHERE m.user_id=u.id))`); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, `
+SELECT r.campaign_id, `+recipientPerson+`, r.email, r.lang, r.unsubscribe_token
+FROM campaign_recipients r
+JOIN campaigns c ON c.id = r.campaign_id AND c.status = 'SENDING'
+JOIN users u ON u.id = r.user_id
+LEFT JOIN profiles p ON p.user_id = u.id
+WHERE r.status = 'PENDING'
+ORDER BY c.started_at, r.email
+LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.CampaignRecipient{}
+	for rows.Next() {
+		var rc domain.CampaignRecipient
+		var status string
+		// the address and language fixed at start win over the current ones
+		if err := rows.Scan(&rc.CampaignID, &rc.UserID, &rc.Email, &rc.Lang, &rc.FullName, &rc.FirstName, &status,
+			&rc.Email, &rc.Lang, &rc.UnsubscribeToken); err != nil {
+			return nil, err
+		}
+		rc.UserStatus = domain.UserStatus(status)
+		rc.Status = domain.RecipientPending
+		out = append(out, rc)
+	}
+	return out, rows.Err()
+}
+
+func (r *CampaignsRepo) MarkRecipient(ctx context.Context, campaignID, userID uuid.UUID, status string, errMsg *string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+UPDATE campaign_recipients SET status=$3, error=$4, sent_at=$5
+WHERE campaign_id=$1 AND user_id=$2`, campaignID, userID, status, errMsg, at)
+	return err
+}
+
+func (r *CampaignsRepo) FinishCompleted(ctx context.Context, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+UPDATE campaigns c SET status='SENT', finished_at=$1, updated_at=now()
+WHERE c.status='SENDING'
+  AND NOT EXISTS (SELECT 1 FROM campaign_recipients r WHERE r.campaign_id=c.id AND r.status='PENDING')`, at)
+	return err
+}
+
+func (r *CampaignsRepo) Unsubscribe(ctx context.Context, token string) error {
+	var userID uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT user_id FROM campaign_recipients WHERE unsubscribe_token=$1`, token).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(ctx, `INSERT INTO mail_unsubscribes (user_id) VALUES ($1) ON CONFLICT DO NOTHING`, userID)
+	return err
+}
@@ -0,0 +1,174 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DuplicatesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewDuplicatesRepo(db *pgxpool.Pool) *DuplicatesRepo {
+	return &DuplicatesRepo{db: db}
+}
+
+func (r *DuplicatesRepo) SimilarTalks(ctx context.Context, talkID uuid.UUID, threshold float64) ([]domain.DuplicateCandidate, error) {
+	out := []domain.DuplicateCandidate{}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// The % operator compares against this setting, which lets the
+		// trigram indexes on talks serve the lookup.
+		if _, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`,
+			strconv.FormatFloat(threshold, 'f', -1, 64)); err != nil {
+			return err
+		}
+		var title, abstract string
+		err := tx.QueryRow(ctx, `SELECT lower(title), lower(abstract) FROM talks WHERE id=$1`, talkID).Scan(&title, &abstract)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, `
+SELECT o.id, o.title, o.speaker_user_id, sim.title_sim, sim.abstract_sim
+FROM talks o
+CROSS JOIN LATERAL (
+  SELECT similarity(lower(o.title), $2) AS title_sim,
+         similarity(lower(o.abstract), $3) AS abstract_sim
+) sim
+WHERE o.id <> $1 AND o.status <> 'WITHDRAWN'
+  AND (lower(o.title) % $2 OR lower(o.abstract) % $3)
+ORDER BY greatest(sim.title_sim, sim.abstract_sim) DESC
+LIMIT 20`, talkID, title, abstract)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var c domain.DuplicateCandidate
+			if err := rows.Scan(&c.TalkID, &c.Title, &c.SpeakerUserID, &c.TitleSimilarity, &c.AbstractSimilarity); err != nil {
+				return err
+			}
+			out = append(out, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *DuplicatesRepo) FlagTalks(ctx context.Context, talkID uuid.UUID, candidates []domain.DuplicateCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for _, c := range candidates {
+			a, b := orderedPair(talkID, c.TalkID)
+			if _, err := tx.Exec(ctx, `
+INSERT INTO talk_duplicate_flags (talk_a, talk_b, title_similarity, abstract_similarity)
+VALUES ($1,$2,$3,$4)
+ON CONFLICT (talk_a, talk_b) DO UPDATE
+SET title_similarity=EXCLUDED.title_similarity, abstract_similarity=EXCLUDED.abstract_similarity, updated_at=now()`,
+				a, b, c.TitleSimilarity, c.AbstractSimilarity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *DuplicatesRepo) ListTalkPairs(ctx context.Context, includeDismissed bool) ([]domain.DuplicateTalkPair, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT
+  ta.id, ta.title, ta.status, ta.speaker_user_id, ua.email, COALESCE(pa.surname || ' ' || pa.name || ' ' || pa.patronymic, ''),
+  tb.id, tb.title, tb.status, tb.speaker_user_id, ub.email, COALESCE(pb.surname || ' ' || pb.name || ' ' || pb.patronymic, ''),
+  f.title_similarity, f.abstract_similarity, f.created_at, f.dismissed_at
+FROM talk_duplicate_flags f
+JOIN talks ta ON ta.id = f.talk_a
+JOIN talks tb ON tb.id = f.talk_b
+JOIN users ua ON ua.id = ta.speaker_user_id
+JOIN users ub ON ub.id = tb.speaker_user_id
+LEFT JOIN profiles pa ON pa.user_id = ta.speaker_user_id
+LEFT JOIN profiles pb ON pb.user_id = tb.speaker_user_id
+WHERE $1 OR f.dismissed_at IS NULL
+ORDER BY greatest(f.title_similarity, f.abstract_similarity) DESC, f.created_at DESC`, includeDismissed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.DuplicateTalkPair{}
+	for rows.Next() {
+		var p domain.DuplicateTalkPair
+		var statusA, statusB string
+		if err := rows.Scan(
+			&p.TalkA.ID, &p.TalkA.Title, &statusA, &p.TalkA.SpeakerUserID, &p.TalkA.SpeakerEmail, &p.TalkA.SpeakerFullName,
+			&p.TalkB.ID, &p.TalkB.Title, &statusB, &p.TalkB.SpeakerUserID, &p.TalkB.SpeakerEmail, &p.TalkB.SpeakerFullName,
+			&p.TitleSimilarity, &p.AbstractSimilarity, &p.FlaggedAt, &p.DismissedAt,
+		); err != nil {
+			return nil, err
+		}
+		p.TalkA.Status = domain.TalkStatus(statusA)
+		p.TalkB.Status = domain.TalkStatus(statusB)
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *DuplicatesRepo) DismissTalkPair(ctx context.Context, a, b uuid.UUID, by uuid.UUID) error {
+	a, b = orderedPair(a, b)
+	tag, err := r.db.Exec(ctx, `
+UPDATE talk_duplicate_flags SET dismissed_at=now(), dismissed_by=$3, updated_at=now()
+WHERE talk_a=$1 AND talk_b=$2`, a, b, by)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DuplicatesRepo) DuplicateUsers(ctx context.Context) ([]domain.DuplicateUserGroup, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT
+  min(p.surname || ' ' || p.name || ' ' || p.patronymic),
+  p.birth_date,
+  json_agg(json_build_object('id', u.id, 'email', u.email, 'status', u.status, 'createdAt', u.created_at) ORDER BY u.created_at)
+FROM profiles p
+JOIN users u ON u.id = p.user_id
+WHERE trim(p.surname) <> '' AND trim(p.name) <> '' AND u.disabled_at IS NULL
+GROUP BY lower(trim(p.surname)), lower(trim(p.name)), lower(trim(p.patronymic)), p.birth_date
+HAVING count(*) > 1
+ORDER BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.DuplicateUserGroup{}
+	for rows.Next() {
+		var g domain.DuplicateUserGroup
+		if err := rows.Scan(&g.FullName, &g.BirthDate, &g.Users); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// orderedPair returns the ids in the order of the talk_a < talk_b constraint.
+func orderedPair(a, b uuid.UUID) (uuid.UUID, uuid.UUID) {
+	if a.String() > b.String() {
+		return b, a
+	}
+	return a, b
+}
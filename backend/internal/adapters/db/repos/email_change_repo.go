@@ -0,0 +1,147 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EmailChangeRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailChangeRepo(db *pgxpool.Pool) *EmailChangeRepo {
+	return &EmailChangeRepo{db: db}
+}
+
+func (r *EmailChangeRepo) Create(ctx context.Context, req ports.EmailChangeRequest) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `
+UPDATE email_change_requests SET cancelled_at=now()
+WHERE user_id=$1 AND confirmed_at IS NULL AND cancelled_at IS NULL`, req.UserID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO email_change_requests (user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, expires_at, cancel_expires_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+		req.UserID, req.OldEmail, req.NewEmail, req.ConfirmTokenHash, req.CancelTokenHash, req.ExpiresAt, req.CancelExpiresAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+const emailChangeColumns = `id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, expires_at, cancel_expires_at, confirmed_at, cancelled_at, created_at`
+
+func (r *EmailChangeRepo) ByConfirmHash(ctx context.Context, tokenHash string) (*ports.EmailChangeRequest, error) {
+	return r.get(ctx, `SELECT `+emailChangeColumns+` FROM email_change_requests WHERE confirm_token_hash=$1`, tokenHash)
+}
+
+func (r *EmailChangeRepo) ByCancelHash(ctx context.Context, tokenHash string) (*ports.EmailChangeRequest, error) {
+	return r.get(ctx, `SELECT `+emailChangeColumns+` FROM email_change_requests WHERE cancel_token_hash=$1`, tokenHash)
+}
+
+func (r *EmailChangeRepo) get(ctx context.Context, query string, arg any) (*ports.EmailChangeRequest, error) {
+	var e ports.EmailChangeRequest
+	err := r.db.QueryRow(ctx, query, arg).Scan(&e.ID, &e.UserID, &e.OldEmail, &e.NewEmail, &e.ConfirmTokenHash, &e.CancelTokenHash,
+		&e.ExpiresAt, &e.CancelExpiresAt, &e.ConfirmedAt, &e.CancelledAt, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *EmailChangeRepo) Confirm(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var userID uuid.UUID
+		var newEmail string
+		err := tx.QueryRow(ctx, `
+UPDATE email_change_requests SET confirmed_at=$2
+WHERE id=$1 AND confirmed_at IS NULL AND cancelled_at IS NULL AND expires_at > $2
+RETURNING user_id, new_email`, id, at).Scan(&userID, &newEmail)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrInvalidState
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+UPDATE users SET email=$1, email_verified=true, email_undeliverable_at=NULL, email_bounce_reason=NULL, updated_at=now()
+WHERE id=$2`, newEmail, userID)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		for _, q := range []string{
+			`UPDATE refresh_sessions SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL`,
+			`DELETE FROM email_verify_tokens WHERE user_id=$1`,
+		} {
+			if _, err := tx.Exec(ctx, q, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *EmailChangeRepo) Cancel(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	reverted := false
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var userID uuid.UUID
+		var oldEmail, newEmail string
+		var confirmedAt *time.Time
+		err := tx.QueryRow(ctx, `
+UPDATE email_change_requests SET cancelled_at=$2
+WHERE id=$1 AND cancelled_at IS NULL AND cancel_expires_at > $2
+RETURNING user_id, old_email, new_email, confirmed_at`, id, at).Scan(&userID, &oldEmail, &newEmail, &confirmedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrInvalidState
+		}
+		if err != nil {
+			return err
+		}
+		if confirmedAt == nil {
+			return nil
+		}
+		// only revert if nothing changed the address since
+		tag, err := tx.Exec(ctx, `
+UPDATE users SET email=$1, email_undeliverable_at=NULL, email_bounce_reason=NULL, updated_at=now()
+WHERE id=$2 AND lower(email)=lower($3)`, oldEmail, userID, newEmail)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `UPDATE refresh_sessions SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL`, userID); err != nil {
+			return err
+		}
+		reverted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return reverted, nil
+}
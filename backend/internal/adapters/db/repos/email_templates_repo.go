@@ -0,0 +1,105 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EmailTemplatesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewEmailTemplatesRepo(db *pgxpool.Pool) *EmailTemplatesRepo {
+	return &EmailTemplatesRepo{db: db}
+}
+
+const emailTemplateColumns = `v.name, v.lang, v.subject, v.html, v.text, v.version, v.created_by, v.created_at`
+
+func scanEmailTemplate(row pgx.Row) (*domain.EmailTemplate, error) {
+	var t domain.EmailTemplate
+	err := row.Scan(&t.Name, &t.Lang, &t.Subject, &t.HTML, &t.Text, &t.Version, &t.CreatedBy, &t.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *EmailTemplatesRepo) queryTemplates(ctx context.Context, query string, args ...any) ([]domain.EmailTemplate, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.EmailTemplate{}
+	for rows.Next() {
+		t, err := scanEmailTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+func (r *EmailTemplatesRepo) Active(ctx context.Context, name, lang string) (*domain.EmailTemplate, error) {
+	return scanEmailTemplate(r.db.QueryRow(ctx, `
+SELECT `+emailTemplateColumns+`
+FROM email_templates t
+JOIN email_template_versions v ON v.name=t.name AND v.lang=t.lang AND v.version=t.version
+WHERE t.name=$1 AND t.lang=$2`, name, lang))
+}
+
+func (r *EmailTemplatesRepo) ListActive(ctx context.Context) ([]domain.EmailTemplate, error) {
+	return r.queryTemplates(ctx, `
+SELECT `+emailTemplateColumns+`
+FROM email_templates t
+JOIN email_template_versions v ON v.name=t.name AND v.lang=t.lang AND v.version=t.version
+ORDER BY t.name, t.lang`)
+}
+
+func (r *EmailTemplatesRepo) Save(ctx context.Context, t domain.EmailTemplate, by uuid.UUID) (int, error) {
+	var version int
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+INSERT INTO email_template_versions (name, lang, version, subject, html, text, created_by)
+SELECT $1, $2, COALESCE(max(version), 0) + 1, $3, $4, $5, $6
+FROM email_template_versions WHERE name=$1 AND lang=$2
+RETURNING version`, t.Name, t.Lang, t.Subject, t.HTML, t.Text, by).Scan(&version); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+INSERT INTO email_templates (name, lang, version) VALUES ($1,$2,$3)
+ON CONFLICT (name, lang) DO UPDATE SET version=EXCLUDED.version, updated_at=now()`, t.Name, t.Lang, version)
+		return err
+	})
+	return version, err
+}
+
+func (r *EmailTemplatesRepo) Deactivate(ctx context.Context, name, lang string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM email_templates WHERE name=$1 AND lang=$2`, name, lang)
+	return err
+}
+
+func (r *EmailTemplatesRepo) Versions(ctx context.Context, name, lang string) ([]domain.EmailTemplate, error) {
+	return r.queryTemplates(ctx, `
+SELECT `+emailTemplateColumns+`
+FROM email_template_versions v
+WHERE v.name=$1 AND v.lang=$2
+ORDER BY v.version DESC`, name, lang)
+}
+
+func (r *EmailTemplatesRepo) Version(ctx context.Context, name, lang string, version int) (*domain.EmailTemplate, error) {
+	return scanEmailTemplate(r.db.QueryRow(ctx, `
+SELECT `+emailTemplateColumns+`
+FROM email_template_versions v
+WHERE v.name=$1 AND v.lang=$2 AND v.version=$3`, name, lang, version))
+}
@@ -12,11 +12,12 @@ import (
 )
 
 type EmailTokensRepo struct {
-	q *sqlc.Queries
+	q  *sqlc.Queries
+	db *pgxpool.Pool
 }
 
 func NewEmailTokensRepo(db *pgxpool.Pool) *EmailTokensRepo {
-	return &EmailTokensRepo{q: sqlc.New(db)}
+	return &EmailTokensRepo{q: sqlc.New(db), db: db}
 }
 
 func (r *EmailTokensRepo) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
@@ -45,3 +46,17 @@ func (r *EmailTokensRepo) ByTokenHash(ctx context.Context, tokenHash string) (*p
 func (r *EmailTokensRepo) MarkUsed(ctx context.Context, tokenID uuid.UUID) error {
 	return r.q.MarkEmailTokenUsed(ctx, tokenID)
 }
+
+func (r *EmailTokensRepo) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE email_verify_tokens SET used_at=now() WHERE user_id=$1 AND used_at IS NULL`, userID)
+	return err
+}
+
+func (r *EmailTokensRepo) IssuedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, *time.Time, error) {
+	var n int
+	var last *time.Time
+	err := r.db.QueryRow(ctx, `
+SELECT count(*) FILTER (WHERE created_at > $2), max(created_at)
+FROM email_verify_tokens WHERE user_id=$1`, userID, since).Scan(&n, &last)
+	return n, last, err
+}
@@ -2,7 +2,6 @@ package repos
 
 import (
 	"context"
-	"fmt"
 
 	"confsite/backend/internal/adapters/db/sqlc"
 	"confsite/backend/internal/ports"
@@ -11,49 +10,71 @@ import (
 )
 
 type ExportsRepo struct {
-	q *sqlc.Queries
+	q  *sqlc.Queries
+	db *pgxpool.Pool
 }
 
 func NewExportsRepo(db *pgxpool.Pool) *ExportsRepo {
-	return &ExportsRepo{q: sqlc.New(db)}
+	return &ExportsRepo{q: sqlc.New(db), db: db}
 }
 
 func (r *ExportsRepo) Participants(ctx context.Context) ([]ports.ParticipantExportRow, error) {
-	rows, err := r.q.ExportParticipants(ctx)
+	rows, err := r.db.Query(ctx, `
+SELECT
+  p.surname || ' ' || p.name || ' ' || p.patronymic AS full_name,
+  p.affiliation,
+  p.city,
+  u.email,
+  u.status,
+  COALESCE(u.cancellation_reason, ''),
+  COALESCE(ra.answers, '{}'::jsonb)
+FROM profiles p
+JOIN users u ON u.id = p.user_id
+LEFT JOIN registration_answers ra ON ra.user_id = p.user_id
+WHERE u.status IN ('APPROVED', 'CANCELLED') AND u.disabled_at IS NULL`)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]ports.ParticipantExportRow, 0, len(rows))
-	for _, x := range rows {
-		out = append(out, ports.ParticipantExportRow{
-			FullName:    fmt.Sprint(x.FullName),
-			Affiliation: x.Affiliation,
-			City:        x.City,
-			Email:       x.Email,
-		})
+	defer rows.Close()
+	out := []ports.ParticipantExportRow{}
+	for rows.Next() {
+		var x ports.ParticipantExportRow
+		if err := rows.Scan(&x.FullName, &x.Affiliation, &x.City, &x.Email, &x.Status, &x.CancellationReason, &x.Answers); err != nil {
+			return nil, err
+		}
+		out = append(out, x)
 	}
-	return out, nil
+	return out, rows.Err()
 }
 
 func (r *ExportsRepo) Talks(ctx context.Context) ([]ports.TalkExportRow, error) {
-	rows, err := r.q.ExportTalks(ctx)
+	rows, err := r.db.Query(ctx, `
+SELECT
+  COALESCE(s.title_ru, ''),
+  t.title,
+  t.kind,
+  t.authors,
+  p.surname || ' ' || p.name || ' ' || p.patronymic AS speaker,
+  p.city,
+  p.affiliation,
+  t.abstract,
+  t.status,
+  COALESCE(t.withdrawal_reason, '')
+FROM talks t
+JOIN profiles p ON p.user_id = t.speaker_user_id
+LEFT JOIN sections s ON s.id = t.section_id`)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]ports.TalkExportRow, 0, len(rows))
-	for _, x := range rows {
-		section := x.Section.String
-		if !x.Section.Valid {
-			section = ""
+	defer rows.Close()
+	out := []ports.TalkExportRow{}
+	for rows.Next() {
+		var x ports.TalkExportRow
+		if err := rows.Scan(&x.Section, &x.Title, &x.Kind, &x.AuthorsJSON, &x.Speaker, &x.SpeakerCity, &x.SpeakerAff,
+			&x.Abstract, &x.Status, &x.WithdrawalReason); err != nil {
+			return nil, err
 		}
-		out = append(out, ports.TalkExportRow{
-			Section:     section,
-			Title:       x.Title,
-			Kind:        x.Kind,
-			AuthorsJSON: x.Authors,
-			Speaker:     fmt.Sprint(x.Speaker),
-			Abstract:    x.Abstract,
-		})
+		out = append(out, x)
 	}
-	return out, nil
+	return out, rows.Err()
 }
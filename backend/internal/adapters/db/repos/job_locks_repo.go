@@ -0,0 +1,37 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobLocks implements ports.JobLocker with PostgreSQL session advisory locks.
+type JobLocks struct {
+	db *pgxpool.Pool
+}
+
+func NewJobLocks(db *pgxpool.Pool) *JobLocks {
+	return &JobLocks{db: db}
+}
+
+func (l *JobLocks) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var ok bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+	release := func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name)
+		conn.Release()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,63 @@
+package repos
+
+import (
+	"fmt"
+	"strings"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// whereBuilder collects the optional conditions of a listing query. Each "?"
+// in a condition is replaced with the next positional parameter.
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+func (w *whereBuilder) add(cond string, args ...any) {
+	for _, a := range args {
+		cond = strings.Replace(cond, "?", w.arg(a), 1)
+	}
+	w.conds = append(w.conds, cond)
+}
+
+func (w *whereBuilder) arg(a any) string {
+	w.args = append(w.args, a)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *whereBuilder) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(w.conds, "\n  AND ")
+}
+
+// page appends ORDER BY, LIMIT and OFFSET. sorts maps the public sort keys to
+// SQL expressions; tiebreak keeps the order stable between pages.
+func (w *whereBuilder) page(q ports.ListQuery, sorts map[string]string, def, tiebreak string) (string, error) {
+	order := def
+	if q.Sort != "" {
+		key, dir := q.Sort, "ASC"
+		if strings.HasPrefix(key, "-") {
+			key, dir = key[1:], "DESC"
+		}
+		col, ok := sorts[key]
+		if !ok {
+			return "", domain.ErrInvalidInput
+		}
+		order = col + " " + dir + " NULLS LAST"
+	}
+	q = q.Normalize()
+	return fmt.Sprintf("ORDER BY %s, %s\nLIMIT %s OFFSET %s", order, tiebreak, w.arg(q.Limit), w.arg(q.Offset)), nil
+}
+
+// likePattern turns a search string into an ILIKE "contains" pattern.
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(strings.TrimSpace(s)) + "%"
+}
+
+// consentsUploadedSQL is true when the user u has uploaded both consent files.
+const consentsUploadedSQL = `((SELECT count(DISTINCT cf.consent_type) FROM consent_files cf WHERE cf.user_id=u.id) = 2)`
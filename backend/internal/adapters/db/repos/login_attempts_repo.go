@@ -0,0 +1,79 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoginAttemptsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAttemptsRepo(db *pgxpool.Pool) *LoginAttemptsRepo {
+	return &LoginAttemptsRepo{db: db}
+}
+
+func (r *LoginAttemptsRepo) Get(ctx context.Context, email string) (*domain.LoginAttempts, error) {
+	a := domain.LoginAttempts{Email: email}
+	err := r.db.QueryRow(ctx, `
+SELECT failed_count, last_failed_at, locked_until
+FROM login_attempts
+WHERE email=$1`, email).Scan(&a.FailedCount, &a.LastFailedAt, &a.LockedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *LoginAttemptsRepo) RecordFailure(ctx context.Context, email string, at, windowStart time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+INSERT INTO login_attempts (email, failed_count, last_failed_at, updated_at)
+VALUES ($1, 1, $2, now())
+ON CONFLICT (email) DO UPDATE SET
+  failed_count = CASE
+    WHEN login_attempts.last_failed_at IS NULL OR login_attempts.last_failed_at < $3 THEN 1
+    ELSE login_attempts.failed_count + 1
+  END,
+  last_failed_at = $2,
+  updated_at = now()
+RETURNING failed_count`, email, at, windowStart).Scan(&count)
+	return count, err
+}
+
+func (r *LoginAttemptsRepo) Lock(ctx context.Context, email string, until time.Time) error {
+	_, err := r.db.Exec(ctx, `
+INSERT INTO login_attempts (email, failed_count, locked_until, updated_at)
+VALUES ($1, 0, $2, now())
+ON CONFLICT (email) DO UPDATE SET
+  failed_count = 0,
+  last_failed_at = NULL,
+  locked_until = $2,
+  updated_at = now()`, email, until)
+	return err
+}
+
+func (r *LoginAttemptsRepo) Reset(ctx context.Context, email string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM login_attempts WHERE email=$1`, email)
+	return err
+}
+
+func (r *LoginAttemptsRepo) DeleteStale(ctx context.Context, now, windowStart time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+DELETE FROM login_attempts
+WHERE (locked_until IS NULL OR locked_until <= $1)
+  AND (last_failed_at IS NULL OR last_failed_at < $2)`, now, windowStart)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
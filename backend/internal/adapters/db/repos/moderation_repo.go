@@ -0,0 +1,343 @@
+package repos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ModerationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewModerationRepo(db *pgxpool.Pool) *ModerationRepo {
+	return &ModerationRepo{db: db}
+}
+
+// Filter conditions shared by both bulk queries. u is the user (or speaker),
+// $2..$6 are status, email verified, consents uploaded, section and kind.
+const (
+	bulkVerifiedCond = `($3::boolean IS NULL OR u.email_verified=$3)`
+	bulkConsentsCond = `($4::boolean IS NULL OR ((SELECT count(DISTINCT cf.consent_type) FROM consent_files cf WHERE cf.user_id=u.id) = 2) = $4)`
+)
+
+type bulkRow struct {
+	id       uuid.UUID
+	status   string
+	category string
+	matches  bool
+}
+
+func (r *ModerationRepo) SetUserStatuses(ctx context.Context, key string, actorID uuid.UUID, ids []uuid.UUID, f domain.BulkFilter, status domain.UserStatus, categoryField string) ([]domain.BulkItemResult, bool, error) {
+	var results []domain.BulkItemResult
+	replayed := false
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		stored, ok, err := claimBulkRun(ctx, tx, key, actorID, "user", string(status), ids, f)
+		if err != nil || ok {
+			results, replayed = stored, ok
+			return err
+		}
+		if err := lockCapacity(ctx, tx); err != nil {
+			return err
+		}
+		limits, err := loadLimits(ctx, tx)
+		if err != nil {
+			return err
+		}
+		cond := `($2::text IS NULL OR u.status=$2) AND ` + bulkVerifiedCond + ` AND ` + bulkConsentsCond + `
+  AND (($5::uuid IS NULL AND $6::text IS NULL) OR EXISTS (
+    SELECT 1 FROM talks t
+    WHERE t.speaker_user_id=u.id AND t.status<>'WITHDRAWN'
+      AND ($5::uuid IS NULL OR t.section_id=$5)
+      AND ($6::text IS NULL OR t.kind=$6)))`
+		rows, err := queryBulkRows(ctx, tx, fmt.Sprintf(`
+SELECT u.id, u.status,
+  CASE WHEN $7::text = '' THEN '' ELSE COALESCE((SELECT ra.answers->>$7 FROM registration_answers ra WHERE ra.user_id=u.id), '') END,
+  %[1]s
+FROM users u
+WHERE ($1::uuid[] IS NOT NULL AND u.id = ANY($1)) OR ($1::uuid[] IS NULL AND %[1]s)
+ORDER BY u.created_at
+FOR UPDATE OF u`, cond), append(bulkArgs(ids, f), categoryField)...)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			res := domain.BulkItemResult{ID: row.id, Previous: row.status, Status: row.status}
+			switch {
+			case !row.matches:
+				res.Result, res.Reason = domain.BulkSkipped, "filtered_out"
+			case row.status == string(domain.StatusCancelled):
+				res.Result, res.Reason = domain.BulkSkipped, "cancelled"
+			case row.status == string(status):
+				res.Result = domain.BulkUnchanged
+			case status == domain.StatusApproved:
+				room, err := hasRoom(ctx, tx, limits, row.id, categoryField, row.category)
+				if err != nil {
+					return err
+				}
+				if room {
+					err = approve(ctx, tx, row.id)
+					res.Result, res.Status = domain.BulkUpdated, string(domain.StatusApproved)
+				} else if row.status == string(domain.StatusWaitlisted) {
+					res.Result, res.Reason = domain.BulkUnchanged, "capacity"
+				} else {
+					_, err = enqueue(ctx, tx, row.id)
+					res.Result, res.Status, res.Reason = domain.BulkUpdated, string(domain.StatusWaitlisted), "capacity"
+				}
+				if err != nil {
+					return err
+				}
+			case status == domain.StatusWaitlisted:
+				if _, err := enqueue(ctx, tx, row.id); err != nil {
+					return err
+				}
+				res.Result, res.Status = domain.BulkUpdated, string(status)
+			default:
+				if _, err := tx.Exec(ctx, `UPDATE users SET status=$2, updated_at=now() WHERE id=$1`, row.id, string(status)); err != nil {
+					return err
+				}
+				if _, err := tx.Exec(ctx, `DELETE FROM waitlist_entries WHERE user_id=$1`, row.id); err != nil {
+					return err
+				}
+				res.Result, res.Status = domain.BulkUpdated, string(status)
+			}
+			results = append(results, res)
+		}
+		results = appendNotFound(results, ids)
+		return finishBulkRun(ctx, tx, key, actorID, "user", string(status), f, results)
+	})
+	return results, replayed, err
+}
+
+func (r *ModerationRepo) SetTalkStatuses(ctx context.Context, key string, actorID uuid.UUID, ids []uuid.UUID, f domain.BulkFilter, status domain.TalkStatus) ([]domain.BulkItemResult, bool, error) {
+	var results []domain.BulkItemResult
+	replayed := false
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		stored, ok, err := claimBulkRun(ctx, tx, key, actorID, "talk", string(status), ids, f)
+		if err != nil || ok {
+			results, replayed = stored, ok
+			return err
+		}
+		cond := `($2::text IS NULL OR t.status=$2) AND ` + bulkVerifiedCond + ` AND ` + bulkConsentsCond + `
+  AND ($5::uuid IS NULL OR t.section_id=$5)
+  AND ($6::text IS NULL OR t.kind=$6)`
+		rows, err := queryBulkRows(ctx, tx, fmt.Sprintf(`
+SELECT t.id, t.status, '', %[1]s
+FROM talks t
+JOIN users u ON u.id = t.speaker_user_id
+WHERE ($1::uuid[] IS NOT NULL AND t.id = ANY($1)) OR ($1::uuid[] IS NULL AND %[1]s)
+ORDER BY t.created_at
+FOR UPDATE OF t`, cond), bulkArgs(ids, f)...)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			res := domain.BulkItemResult{ID: row.id, Previous: row.status, Status: row.status}
+			switch {
+			case !row.matches:
+				res.Result, res.Reason = domain.BulkSkipped, "filtered_out"
+			case row.status == string(domain.TalkStatusWithdrawn):
+				res.Result, res.Reason = domain.BulkSkipped, "withdrawn"
+			case row.status == string(status):
+				res.Result = domain.BulkUnchanged
+			default:
+				if _, err := tx.Exec(ctx, `
+UPDATE talks
+SET status=$2, reviewed_at=CASE WHEN $2='WAITING' THEN NULL ELSE now() END
+WHERE id=$1`, row.id, string(status)); err != nil {
+					return err
+				}
+				res.Result, res.Status = domain.BulkUpdated, string(status)
+			}
+			results = append(results, res)
+		}
+		results = appendNotFound(results, ids)
+		return finishBulkRun(ctx, tx, key, actorID, "talk", string(status), f, results)
+	})
+	return results, replayed, err
+}
+
+func bulkArgs(ids []uuid.UUID, f domain.BulkFilter) []any {
+	var kind *string
+	if f.Kind != nil {
+		k := string(*f.Kind)
+		kind = &k
+	}
+	if len(ids) == 0 {
+		ids = nil
+	}
+	return []any{ids, f.Status, f.EmailVerified, f.ConsentsUploaded, f.SectionID, kind}
+}
+
+func queryBulkRows(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]bulkRow, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []bulkRow{}
+	for rows.Next() {
+		var row bulkRow
+		if err := rows.Scan(&row.id, &row.status, &row.category, &row.matches); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+func appendNotFound(results []domain.BulkItemResult, ids []uuid.UUID) []domain.BulkItemResult {
+	seen := make(map[uuid.UUID]bool, len(results))
+	for _, res := range results {
+		seen[res.ID] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			results = append(results, domain.BulkItemResult{ID: id, Result: domain.BulkNotFound})
+		}
+	}
+	if results == nil {
+		results = []domain.BulkItemResult{}
+	}
+	return results
+}
+
+// claimBulkRun registers the idempotency key. It returns the stored results
+// and true when the key was used before; a concurrent run with the same key
+// blocks here until the first one finishes. A key reused with a different
+// payload is refused with domain.ErrKeyReused.
+func claimBulkRun(ctx context.Context, tx pgx.Tx, key string, actorID uuid.UUID, entity, status string, ids []uuid.UUID, f domain.BulkFilter) ([]domain.BulkItemResult, bool, error) {
+	if key == "" {
+		return nil, false, nil
+	}
+	hash, err := bulkPayloadHash(actorID, entity, status, ids, f)
+	if err != nil {
+		return nil, false, err
+	}
+	tag, err := tx.Exec(ctx, `
+INSERT INTO bulk_moderation_runs (key, actor_user_id, entity, status, payload_hash)
+VALUES ($1,$2,$3,$4,$5)
+ON CONFLICT (key) DO NOTHING`, key, actorID, entity, status, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, false, nil
+	}
+	var storedEntity, storedStatus string
+	var storedHash *string
+	results := []domain.BulkItemResult{}
+	if err := tx.QueryRow(ctx, `SELECT entity, status, payload_hash, results FROM bulk_moderation_runs WHERE key=$1`, key).
+		Scan(&storedEntity, &storedStatus, &storedHash, &results); err != nil {
+		return nil, false, err
+	}
+	// runs stored before payload hashes were recorded only know entity and status
+	if storedEntity != entity || storedStatus != status || (storedHash != nil && *storedHash != hash) {
+		return nil, false, domain.ErrKeyReused
+	}
+	return results, true, nil
+}
+
+// bulkPayloadHash identifies what a bulk call asked for. IDs are sorted, so a
+// retry may list them in any order.
+func bulkPayloadHash(actorID uuid.UUID, entity, status string, ids []uuid.UUID, f domain.BulkFilter) (string, error) {
+	sorted := append([]uuid.UUID(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	payload, err := json.Marshal(struct {
+		Actor  uuid.UUID         `json:"actor"`
+		Entity string            `json:"entity"`
+		Status string            `json:"status"`
+		IDs    []uuid.UUID       `json:"ids"`
+		Filter domain.BulkFilter `json:"filter"`
+	}{actorID, entity, status, sorted, f})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// finishBulkRun stores the results under the key and writes one audit entry
+// for the whole run.
+func finishBulkRun(ctx context.Context, tx pgx.Tx, key string, actorID uuid.UUID, entity, status string, f domain.BulkFilter, results []domain.BulkItemResult) error {
+	if key != "" {
+		if _, err := tx.Exec(ctx, `UPDATE bulk_moderation_runs SET results=$2 WHERE key=$1`, key, results); err != nil {
+			return err
+		}
+	}
+	counts := map[string]int{}
+	for _, res := range results {
+		counts[res.Result]++
+	}
+	details := map[string]any{
+		"status": status,
+		"filter": f,
+		"counts": counts,
+	}
+	if key != "" {
+		details["key"] = key
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO audit_logs (actor_user_id, action, entity, details)
+VALUES ($1,$2,$3,$4)`, actorID, entity+".bulk_status", entity, details)
+	return err
+}
+
+func (r *ModerationRepo) EnqueueMail(ctx context.Context, mails []domain.QueuedMail) error {
+	if len(mails) == 0 {
+		return nil
+	}
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for _, m := range mails {
+			if _, err := tx.Exec(ctx, `
+INSERT INTO bulk_mail_queue (batch_id, to_email, subject, html_body, text_body)
+VALUES ($1,$2,$3,$4,$5)`, m.BatchID, m.To, m.Subject, m.HTML, m.Text); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ModerationRepo) PendingMail(ctx context.Context, limit int) ([]domain.QueuedMail, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT id, batch_id, to_email, subject, html_body, text_body
+FROM bulk_mail_queue
+WHERE sent_at IS NULL AND failed_at IS NULL
+ORDER BY created_at, id
+LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.QueuedMail{}
+	for rows.Next() {
+		var m domain.QueuedMail
+		if err := rows.Scan(&m.ID, &m.BatchID, &m.To, &m.Subject, &m.HTML, &m.Text); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *ModerationRepo) MarkMail(ctx context.Context, id uuid.UUID, errMsg *string) error {
+	if errMsg == nil {
+		_, err := r.db.Exec(ctx, `UPDATE bulk_mail_queue SET sent_at=now() WHERE id=$1`, id)
+		return err
+	}
+	_, err := r.db.Exec(ctx, `UPDATE bulk_mail_queue SET failed_at=now(), last_error=$2 WHERE id=$1`, id, *errMsg)
+	return err
+}
@@ -2,76 +2,150 @@
 
 import (
 	"context"
+	"errors"
+	"time"
 
-	"confsite/backend/internal/adapters/db/sqlc"
 	"confsite/backend/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type NewsRepo struct {
-	q  *sqlc.Queries
 	db *pgxpool.Pool
 }
 
 func NewNewsRepo(db *pgxpool.Pool) *NewsRepo {
-	return &NewsRepo{q: sqlc.New(db), db: db}
+	return &NewsRepo{db: db}
 }
 
-func (r *NewsRepo) List(ctx context.Context) ([]domain.News, error) {
-	ns, err := r.q.ListNews(ctx)
+const newsColumns = `id, title_ru, body_ru, title_en, body_en, pinned, status, publish_at, expires_at,
+published_at, notify_subscribers, notified_at, campaign_id, created_at, updated_at`
+
+func scanNews(row pgx.Row) (domain.News, error) {
+	var n domain.News
+	var status string
+	err := row.Scan(&n.ID, &n.TitleRu, &n.BodyRu, &n.TitleEn, &n.BodyEn, &n.Pinned, &status, &n.PublishAt, &n.ExpiresAt,
+		&n.PublishedAt, &n.NotifySubscribers, &n.NotifiedAt, &n.CampaignID, &n.CreatedAt, &n.UpdatedAt)
+	n.Status = domain.NewsStatus(status)
+	return n, err
+}
+
+func (r *NewsRepo) queryNews(ctx context.Context, sql string, args ...any) ([]domain.News, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]domain.News, 0, len(ns))
-	for _, n := range ns {
-		out = append(out, domain.News{
-			ID:          n.ID,
-			TitleRu:     n.TitleRu,
-			BodyRu:      n.BodyRu,
-			TitleEn:     n.TitleEn,
-			BodyEn:      n.BodyEn,
-			Pinned:      n.Pinned,
-			PublishedAt: n.PublishedAt,
-		})
+	defer rows.Close()
+	out := []domain.News{}
+	for rows.Next() {
+		n, err := scanNews(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
 	}
-	return out, nil
+	return out, rows.Err()
+}
+
+func (r *NewsRepo) List(ctx context.Context) ([]domain.News, error) {
+	return r.queryNews(ctx, `SELECT `+newsColumns+` FROM news
+ORDER BY COALESCE(published_at, publish_at, created_at) DESC`)
+}
+
+// ListVisible reports a scheduled item whose time has come as published even
+// before the scheduler flips its status.
+func (r *NewsRepo) ListVisible(ctx context.Context, now time.Time) ([]domain.News, error) {
+	return r.queryNews(ctx, `
+SELECT id, title_ru, body_ru, title_en, body_en, pinned, 'PUBLISHED', publish_at, expires_at,
+  COALESCE(published_at, publish_at), notify_subscribers, notified_at, campaign_id, created_at, updated_at
+FROM news
+WHERE (status = 'PUBLISHED' OR (status = 'SCHEDULED' AND publish_at <= $1))
+  AND (expires_at IS NULL OR expires_at > $1)
+ORDER BY pinned DESC, COALESCE(published_at, publish_at) DESC`, now)
 }
 
 func (r *NewsRepo) Get(ctx context.Context, id uuid.UUID) (*domain.News, error) {
-	n, err := r.q.GetNews(ctx, id)
+	n, err := scanNews(r.db.QueryRow(ctx, `SELECT `+newsColumns+` FROM news WHERE id=$1`, id))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
 		return nil, err
 	}
-	return &domain.News{
-		ID:          n.ID,
-		TitleRu:     n.TitleRu,
-		BodyRu:      n.BodyRu,
-		TitleEn:     n.TitleEn,
-		BodyEn:      n.BodyEn,
-		Pinned:      n.Pinned,
-		PublishedAt: n.PublishedAt,
-	}, nil
+	return &n, nil
 }
 
 func (r *NewsRepo) Create(ctx context.Context, n domain.News) (uuid.UUID, error) {
 	id := uuid.New()
 	_, err := r.db.Exec(ctx, `
-INSERT INTO news (id,title_ru,body_ru,title_en,body_en,pinned,published_at)
-VALUES ($1,$2,$3,$4,$5,$6,now())`,
-		id, n.TitleRu, n.BodyRu, n.TitleEn, n.BodyEn, n.Pinned)
+INSERT INTO news (id,title_ru,body_ru,title_en,body_en,pinned,status,publish_at,expires_at,published_at,notify_subscribers)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		id, n.TitleRu, n.BodyRu, n.TitleEn, n.BodyEn, n.Pinned, string(n.Status), n.PublishAt, n.ExpiresAt, n.PublishedAt, n.NotifySubscribers)
 	return id, err
 }
 
 func (r *NewsRepo) Update(ctx context.Context, n domain.News) error {
-	_, err := r.db.Exec(ctx, `
-UPDATE news SET title_ru=$2, body_ru=$3, title_en=$4, body_en=$5, pinned=$6
-WHERE id=$1`, n.ID, n.TitleRu, n.BodyRu, n.TitleEn, n.BodyEn, n.Pinned)
-	return err
+	tag, err := r.db.Exec(ctx, `
+UPDATE news SET title_ru=$2, body_ru=$3, title_en=$4, body_en=$5, pinned=$6, status=$7, publish_at=$8, expires_at=$9,
+  published_at=$10, notify_subscribers=$11, updated_at=now()
+WHERE id=$1`, n.ID, n.TitleRu, n.BodyRu, n.TitleEn, n.BodyEn, n.Pinned, string(n.Status), n.PublishAt, n.ExpiresAt,
+		n.PublishedAt, n.NotifySubscribers)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
 }
 
 func (r *NewsRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := r.db.Exec(ctx, `DELETE FROM news WHERE id=$1`, id)
 	return err
 }
+
+func (r *NewsRepo) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+UPDATE news SET status='PUBLISHED', published_at=COALESCE(published_at, publish_at), updated_at=now()
+WHERE status='SCHEDULED' AND publish_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *NewsRepo) ArchiveExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+UPDATE news SET status='ARCHIVED', updated_at=now()
+WHERE status='PUBLISHED' AND expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *NewsRepo) ClaimNotification(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE news SET notified_at=$2 WHERE id=$1 AND notified_at IS NULL`, id, now)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *NewsRepo) ReleaseNotification(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE news SET notified_at=NULL WHERE id=$1 AND campaign_id IS NULL`, id)
+	return err
+}
+
+func (r *NewsRepo) PendingNotifications(ctx context.Context) ([]domain.News, error) {
+	return r.queryNews(ctx, `SELECT `+newsColumns+` FROM news
+WHERE status='PUBLISHED' AND notify_subscribers AND notified_at IS NULL
+ORDER BY published_at`)
+}
+
+func (r *NewsRepo) SetCampaign(ctx context.Context, id, campaignID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE news SET campaign_id=$2 WHERE id=$1`, id, campaignID)
+	return err
+}
@@ -0,0 +1,41 @@
+package repos
+
+import (
+	"context"
+
+	"confsite/backend/internal/ports"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OIDCStatesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOIDCStatesRepo(db *pgxpool.Pool) *OIDCStatesRepo {
+	return &OIDCStatesRepo{db: db}
+}
+
+func (r *OIDCStatesRepo) Create(ctx context.Context, st ports.OIDCLoginState) error {
+	// opportunistic cleanup of abandoned login attempts
+	_, _ = r.db.Exec(ctx, `DELETE FROM oidc_login_states WHERE expires_at < now()`)
+	_, err := r.db.Exec(ctx, `
+INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, redirect_to, expires_at)
+VALUES ($1,$2,$3,$4,$5,$6)`,
+		st.StateHash, st.Provider, st.Nonce, st.CodeVerifier, st.RedirectTo, st.ExpiresAt)
+	return err
+}
+
+func (r *OIDCStatesRepo) Consume(ctx context.Context, stateHash string) (*ports.OIDCLoginState, error) {
+	var st ports.OIDCLoginState
+	err := r.db.QueryRow(ctx, `
+DELETE FROM oidc_login_states
+WHERE state_hash=$1
+RETURNING state_hash, provider, nonce, code_verifier, redirect_to, expires_at`, stateHash).Scan(
+		&st.StateHash, &st.Provider, &st.Nonce, &st.CodeVerifier, &st.RedirectTo, &st.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
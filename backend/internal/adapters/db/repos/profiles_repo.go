@@ -71,7 +71,7 @@ func (r *ProfilesRepo) ListApprovedPublic(ctx context.Context) ([]domain.PublicP
 SELECT p.surname, p.name, p.patronymic, p.affiliation, p.city
 FROM profiles p
 JOIN users u ON u.id=p.user_id
-WHERE u.status='APPROVED'
+WHERE u.status='APPROVED' AND u.disabled_at IS NULL
 ORDER BY p.surname, p.name`)
 	if err != nil {
 		return nil, err
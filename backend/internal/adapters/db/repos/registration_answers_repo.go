@@ -0,0 +1,34 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RegistrationAnswersRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRegistrationAnswersRepo(db *pgxpool.Pool) *RegistrationAnswersRepo {
+	return &RegistrationAnswersRepo{db: db}
+}
+
+func (r *RegistrationAnswersRepo) Get(ctx context.Context, userID uuid.UUID) (map[string]any, error) {
+	answers := map[string]any{}
+	err := r.db.QueryRow(ctx, `SELECT answers FROM registration_answers WHERE user_id=$1`, userID).Scan(&answers)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return map[string]any{}, nil
+	}
+	return answers, err
+}
+
+func (r *RegistrationAnswersRepo) Upsert(ctx context.Context, userID uuid.UUID, answers map[string]any) error {
+	_, err := r.db.Exec(ctx, `
+INSERT INTO registration_answers (user_id, answers) VALUES ($1,$2)
+ON CONFLICT (user_id) DO UPDATE SET answers=EXCLUDED.answers, updated_at=now()`, userID, answers)
+	return err
+}
@@ -0,0 +1,118 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RegistrationFieldsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRegistrationFieldsRepo(db *pgxpool.Pool) *RegistrationFieldsRepo {
+	return &RegistrationFieldsRepo{db: db}
+}
+
+const registrationFieldColumns = `id, key, type, label_ru, label_en, help_ru, help_en, options, required, validation, sort_order, active, created_at, updated_at`
+
+func scanRegistrationField(row pgx.Row) (*domain.RegistrationField, error) {
+	var f domain.RegistrationField
+	var typ string
+	err := row.Scan(&f.ID, &f.Key, &typ, &f.LabelRu, &f.LabelEn, &f.HelpRu, &f.HelpEn, &f.Options,
+		&f.Required, &f.Validation, &f.SortOrder, &f.Active, &f.CreatedAt, &f.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.Type = domain.RegistrationFieldType(typ)
+	if f.Options == nil {
+		f.Options = []domain.RegistrationFieldOption{}
+	}
+	// a bad stored pattern is reported when answers are checked against it
+	if err := f.Validation.CompilePattern(); err != nil {
+		println("Warning: registration field", f.Key, "has an invalid pattern:", err.Error())
+	}
+	return &f, nil
+}
+
+func (r *RegistrationFieldsRepo) List(ctx context.Context, activeOnly bool) ([]domain.RegistrationField, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT `+registrationFieldColumns+`
+FROM registration_fields
+WHERE active OR NOT $1
+ORDER BY sort_order, created_at`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.RegistrationField{}
+	for rows.Next() {
+		f, err := scanRegistrationField(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *f)
+	}
+	return out, rows.Err()
+}
+
+func (r *RegistrationFieldsRepo) Get(ctx context.Context, id uuid.UUID) (*domain.RegistrationField, error) {
+	return scanRegistrationField(r.db.QueryRow(ctx, `SELECT `+registrationFieldColumns+` FROM registration_fields WHERE id=$1`, id))
+}
+
+func (r *RegistrationFieldsRepo) Create(ctx context.Context, f domain.RegistrationField) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, `
+INSERT INTO registration_fields (key, type, label_ru, label_en, help_ru, help_en, options, required, validation, sort_order, active)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+RETURNING id`, f.Key, string(f.Type), f.LabelRu, f.LabelEn, f.HelpRu, f.HelpEn, f.Options,
+		f.Required, f.Validation, f.SortOrder, f.Active).Scan(&id)
+	if isUniqueViolation(err) {
+		return uuid.Nil, domain.ErrInvalidInput
+	}
+	return id, err
+}
+
+func (r *RegistrationFieldsRepo) Update(ctx context.Context, f domain.RegistrationField) error {
+	tag, err := r.db.Exec(ctx, `
+UPDATE registration_fields
+SET key=$2, type=$3, label_ru=$4, label_en=$5, help_ru=$6, help_en=$7, options=$8,
+    required=$9, validation=$10, sort_order=$11, active=$12, updated_at=now()
+WHERE id=$1`, f.ID, f.Key, string(f.Type), f.LabelRu, f.LabelEn, f.HelpRu, f.HelpEn, f.Options,
+		f.Required, f.Validation, f.SortOrder, f.Active)
+	if isUniqueViolation(err) {
+		return domain.ErrInvalidInput
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *RegistrationFieldsRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM registration_fields WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,111 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RemindersRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRemindersRepo(db *pgxpool.Pool) *RemindersRepo {
+	return &RemindersRepo{db: db}
+}
+
+// Due treats an item as due once it has been outstanding for a whole
+// interval: the account or the talk approval is older than since. Only
+// participants are reminded: approved users or those who filled in their
+// profile, never admins or section admins.
+func (r *RemindersRepo) Due(ctx context.Context, since time.Time) ([]domain.ReminderCandidate, error) {
+	rows, err := r.db.Query(ctx, `
+WITH due AS (
+  SELECT u.id, u.email, COALESCE(u.preferred_lang, 'ru') AS lang,
+    (NOT `+consentsUploadedSQL+` AND u.created_at < $1
+      AND NOT EXISTS (SELECT 1 FROM reminders_sent rs WHERE rs.user_id=u.id AND rs.kind='CONSENTS' AND rs.sent_at > $1)) AS consents,
+    CASE WHEN EXISTS (SELECT 1 FROM reminders_sent rs WHERE rs.user_id=u.id AND rs.kind='LICENSE_AGREEMENT' AND rs.sent_at > $1)
+      THEN '{}'::text[]
+      ELSE COALESCE((SELECT array_agg(t.title ORDER BY t.created_at) FROM talks t
+        WHERE t.speaker_user_id=u.id AND t.status='APPROVED' AND COALESCE(t.reviewed_at, t.created_at) < $1
+          AND NOT EXISTS (SELECT 1 FROM signed_documents d
+            WHERE d.user_id=u.id AND d.talk_id=t.id AND d.document_type='LICENSE_AGREEMENT')), '{}')
+    END AS license_talks,
+    CASE WHEN EXISTS (SELECT 1 FROM reminders_sent rs WHERE rs.user_id=u.id AND rs.kind='TALK_FILE' AND rs.sent_at > $1)
+      THEN '{}'::text[]
+      ELSE COALESCE((SELECT array_agg(t.title ORDER BY t.created_at) FROM talks t
+        WHERE t.speaker_user_id=u.id AND t.status='APPROVED' AND COALESCE(t.reviewed_at, t.created_at) < $1
+          AND (t.file_url IS NULL OR t.file_url = '')), '{}')
+    END AS file_talks
+  FROM users u
+  WHERE u.disabled_at IS NULL AND u.email_verified AND u.email_undeliverable_at IS NULL
+    AND u.status NOT IN ('REJECTED', 'CANCELLED')
+    AND (u.status = 'APPROVED' OR EXISTS (SELECT 1 FROM profiles p WHERE p.user_id=u.id AND p.surname <> ''))
+    AND NOT EXISTS (
+      SELECT 1 FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+      WHERE ur.user_id = u.id AND r.code IN ('ADMIN', 'SECTION_ADMIN'))
+)
+SELECT id, email, lang, consents, license_talks, file_talks
+FROM due
+WHERE consents OR cardinality(license_talks) > 0 OR cardinality(file_talks) > 0
+ORDER BY email`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.ReminderCandidate{}
+	for rows.Next() {
+		var c domain.ReminderCandidate
+		if err := rows.Scan(&c.UserID, &c.Email, &c.Lang, &c.MissingConsents, &c.LicenseTalks, &c.FileTalks); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *RemindersRepo) Record(ctx context.Context, items []domain.ReminderSent) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for _, it := range items {
+			talks := it.Talks
+			if talks == nil {
+				talks = []string{}
+			}
+			if _, err := tx.Exec(ctx, `
+INSERT INTO reminders_sent (user_id, kind, email, talks, sent_at) VALUES ($1,$2,$3,$4,$5)`,
+				it.UserID, string(it.Kind), it.Email, talks, it.SentAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *RemindersRepo) Sent(ctx context.Context, from, to *time.Time) ([]domain.ReminderSent, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT rs.id, rs.user_id, rs.email, COALESCE(trim(p.surname || ' ' || p.name || ' ' || p.patronymic), ''),
+  rs.kind, rs.talks, rs.sent_at
+FROM reminders_sent rs
+LEFT JOIN profiles p ON p.user_id = rs.user_id
+WHERE ($1::timestamptz IS NULL OR rs.sent_at >= $1) AND ($2::timestamptz IS NULL OR rs.sent_at < $2)
+ORDER BY rs.sent_at DESC, rs.email`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.ReminderSent{}
+	for rows.Next() {
+		var it domain.ReminderSent
+		var kind string
+		if err := rows.Scan(&it.ID, &it.UserID, &it.Email, &it.FullName, &kind, &it.Talks, &it.SentAt); err != nil {
+			return nil, err
+		}
+		it.Kind = domain.ReminderKind(kind)
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
@@ -3,6 +3,7 @@ package repos
 import (
 	"context"
 	"strings"
+	"time"
 
 	"confsite/backend/internal/adapters/db/sqlc"
 	"confsite/backend/internal/domain"
@@ -47,9 +48,10 @@ func (r *SectionsRepo) Create(ctx context.Context, titleRu, titleEn string, sort
 
 func (r *SectionsRepo) ListResponsibleEmails(ctx context.Context) ([]domain.SectionResponsibleEmail, error) {
 	rows, err := r.db.Query(ctx, `
-SELECT section_id, email
-FROM section_responsibles
-ORDER BY section_id, lower(email)`)
+SELECT sr.section_id, sr.email, COALESCE(rp.mode, 'IMMEDIATE')
+FROM section_responsibles sr
+LEFT JOIN responsible_preferences rp ON rp.email = lower(sr.email)
+ORDER BY sr.section_id, lower(sr.email)`)
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +60,11 @@ ORDER BY section_id, lower(email)`)
 	out := make([]domain.SectionResponsibleEmail, 0)
 	for rows.Next() {
 		var item domain.SectionResponsibleEmail
-		if err := rows.Scan(&item.SectionID, &item.Email); err != nil {
+		var mode string
+		if err := rows.Scan(&item.SectionID, &item.Email, &mode); err != nil {
 			return nil, err
 		}
+		item.Mode = domain.NotifyMode(mode)
 		out = append(out, item)
 	}
 	return out, rows.Err()
@@ -92,3 +96,77 @@ ON CONFLICT (section_id, email) DO NOTHING`, sectionID, e); err != nil {
 
 	return tx.Commit(ctx)
 }
+
+func (r *SectionsRepo) SetResponsibleMode(ctx context.Context, email string, mode domain.NotifyMode) error {
+	tag, err := r.db.Exec(ctx, `
+INSERT INTO responsible_preferences (email, mode)
+SELECT lower($1), $2
+WHERE EXISTS (SELECT 1 FROM section_responsibles WHERE lower(email) = lower($1))
+ON CONFLICT (email) DO UPDATE SET mode=EXCLUDED.mode, updated_at=now()`, strings.TrimSpace(email), string(mode))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SectionsRepo) DigestRecipients(ctx context.Context) ([]domain.DigestRecipient, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT rp.email,
+  COALESCE((SELECT u.preferred_lang FROM users u WHERE lower(u.email) = rp.email), 'ru'),
+  array_agg(sr.section_id ORDER BY sr.section_id), rp.last_digest_at
+FROM responsible_preferences rp
+JOIN section_responsibles sr ON lower(sr.email) = rp.email
+WHERE rp.mode = 'DIGEST'
+GROUP BY rp.email, rp.last_digest_at
+ORDER BY rp.email`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.DigestRecipient{}
+	for rows.Next() {
+		var d domain.DigestRecipient
+		if err := rows.Scan(&d.Email, &d.Lang, &d.SectionIDs, &d.LastDigestAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *SectionsRepo) DigestTalks(ctx context.Context, sectionIDs []uuid.UUID, since time.Time) ([]domain.DigestTalk, error) {
+	rows, err := r.db.Query(ctx, `
+SELECT t.id, t.section_id, s.title_ru, s.title_en, t.title,
+  COALESCE(trim(p.surname || ' ' || p.name || ' ' || p.patronymic), ''),
+  t.kind, t.status, t.created_at, t.revised_at
+FROM talks t
+JOIN sections s ON s.id = t.section_id
+LEFT JOIN profiles p ON p.user_id = t.speaker_user_id
+WHERE t.section_id = ANY($1::uuid[]) AND t.status <> 'WITHDRAWN'
+  AND (t.created_at > $2 OR t.revised_at > $2 OR t.status = 'WAITING')
+ORDER BY s.sort_order, s.title_ru, t.created_at`, sectionIDs, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.DigestTalk{}
+	for rows.Next() {
+		var t domain.DigestTalk
+		var kind, status string
+		if err := rows.Scan(&t.ID, &t.SectionID, &t.SectionTitleRu, &t.SectionTitleEn, &t.Title,
+			&t.Speaker, &kind, &status, &t.CreatedAt, &t.RevisedAt); err != nil {
+			return nil, err
+		}
+		t.Kind, t.Status = domain.TalkKind(kind), domain.TalkStatus(status)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *SectionsRepo) MarkDigestSent(ctx context.Context, email string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE responsible_preferences SET last_digest_at=$2 WHERE email=lower($1)`, email, at)
+	return err
+}
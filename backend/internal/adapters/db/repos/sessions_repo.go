@@ -12,11 +12,12 @@ import (
 )
 
 type SessionsRepo struct {
-	q *sqlc.Queries
+	q  *sqlc.Queries
+	db *pgxpool.Pool
 }
 
 func NewSessionsRepo(db *pgxpool.Pool) *SessionsRepo {
-	return &SessionsRepo{q: sqlc.New(db)}
+	return &SessionsRepo{q: sqlc.New(db), db: db}
 }
 
 func (r *SessionsRepo) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
@@ -45,3 +46,8 @@ func (r *SessionsRepo) ByTokenHash(ctx context.Context, tokenHash string) (*doma
 func (r *SessionsRepo) Revoke(ctx context.Context, sessionID uuid.UUID) error {
 	return r.q.RevokeSession(ctx, sessionID)
 }
+
+func (r *SessionsRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE refresh_sessions SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL`, userID)
+	return err
+}
@@ -0,0 +1,78 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SitemapRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSitemapRepo(db *pgxpool.Pool) *SitemapRepo {
+	return &SitemapRepo{db: db}
+}
+
+// LastModified counts a withdrawn talk as a program change: it leaves the
+// program when it is withdrawn.
+func (r *SitemapRepo) LastModified(ctx context.Context) (domain.SiteLastModified, error) {
+	var lm domain.SiteLastModified
+	err := r.db.QueryRow(ctx, `
+SELECT
+  GREATEST(
+    (SELECT max(updated_at) FROM sections),
+    (SELECT max(updated_at) FROM section_translations)),
+  GREATEST(
+    (SELECT max(uploaded_at) FROM program_files),
+    (SELECT max(GREATEST(created_at, reviewed_at, revised_at, withdrawn_at)) FROM talks
+     WHERE status = 'APPROVED' OR withdrawn_at IS NOT NULL)),
+  GREATEST(
+    (SELECT max(updated_at) FROM materials),
+    (SELECT max(updated_at) FROM material_translations))`).Scan(&lm.Sections, &lm.Program, &lm.Materials)
+	if err != nil {
+		return lm, err
+	}
+
+	lm.PageTranslations = map[string]time.Time{}
+	rows, err := r.db.Query(ctx, `
+SELECT b.slug, max(tr.updated_at) FROM page_translations tr
+JOIN page_contents b ON b.id = tr.page_id
+GROUP BY b.slug`)
+	if err != nil {
+		return lm, err
+	}
+	for rows.Next() {
+		var slug string
+		var at time.Time
+		if err := rows.Scan(&slug, &at); err != nil {
+			rows.Close()
+			return lm, err
+		}
+		lm.PageTranslations[slug] = at
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return lm, err
+	}
+
+	lm.NewsTranslations = map[uuid.UUID]time.Time{}
+	rows, err = r.db.Query(ctx, `SELECT news_id, max(updated_at) FROM news_translations GROUP BY news_id`)
+	if err != nil {
+		return lm, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var at time.Time
+		if err := rows.Scan(&id, &at); err != nil {
+			return lm, err
+		}
+		lm.NewsTranslations[id] = at
+	}
+	return lm, rows.Err()
+}
@@ -0,0 +1,88 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// Highlight markers are control characters so that the text can be escaped
+// before they are turned into <mark> tags.
+const (
+	markStart = "\x01"
+	markStop  = "\x02"
+)
+
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", markStart, markStop)
+var snippetOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=25, MinWords=10, FragmentDelimiter=\" … \"", markStart, markStop)
+
+func (r *TalksRepo) Search(ctx context.Context, f ports.TalkSearchFilter) ([]domain.TalkSearchHit, int, error) {
+	cfg := "russian"
+	if f.Lang == "en" {
+		cfg = "english"
+	}
+	var w whereBuilder
+	query := w.arg(strings.TrimSpace(f.Search))
+	w.add(`t.search_vector @@ (websearch_to_tsquery('russian', ` + query + `) || websearch_to_tsquery('english', ` + query + `))`)
+	if f.Status != nil {
+		w.add(`t.status = ?`, string(*f.Status))
+	}
+	if f.SectionID != nil {
+		w.add(`t.section_id = ?`, *f.SectionID)
+	}
+	if f.Kind != nil {
+		w.add(`t.kind = ?`, string(*f.Kind))
+	}
+	config := w.arg(cfg)
+	headline, snippet := w.arg(headlineOptions), w.arg(snippetOptions)
+	where := w.sql()
+	f.ListQuery.Sort = ""
+	page, err := w.page(f.ListQuery, nil, "rank DESC", "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+SELECT t.id, t.title, t.kind, t.status, t.section_id, s.title_ru, s.title_en,
+  COALESCE(p.surname || ' ' || p.name || ' ' || p.patronymic, ''),
+  t.authors,
+  ts_rank_cd(t.search_vector, websearch_to_tsquery('russian', `+query+`) || websearch_to_tsquery('english', `+query+`)) AS rank,
+  ts_headline(`+config+`::regconfig, t.title, websearch_to_tsquery(`+config+`::regconfig, `+query+`), `+headline+`),
+  ts_headline(`+config+`::regconfig, t.abstract, websearch_to_tsquery(`+config+`::regconfig, `+query+`), `+snippet+`),
+  count(*) OVER ()
+FROM talks t
+LEFT JOIN profiles p ON p.user_id = t.speaker_user_id
+LEFT JOIN sections s ON s.id = t.section_id
+`+where+`
+`+page, w.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := []domain.TalkSearchHit{}
+	total := 0
+	for rows.Next() {
+		var h domain.TalkSearchHit
+		var kind, status string
+		if err := rows.Scan(&h.ID, &h.Title, &kind, &status, &h.SectionID, &h.SectionTitleRu, &h.SectionTitleEn,
+			&h.SpeakerFullName, &h.AuthorsJSON, &h.Rank, &h.TitleHighlight, &h.AbstractSnippet, &total); err != nil {
+			return nil, 0, err
+		}
+		h.Kind = domain.TalkKind(kind)
+		h.Status = domain.TalkStatus(status)
+		h.TitleHighlight = markHighlights(h.TitleHighlight)
+		h.AbstractSnippet = markHighlights(h.AbstractSnippet)
+		out = append(out, h)
+	}
+	return out, total, rows.Err()
+}
+
+func markHighlights(s string) string {
+	s = html.EscapeString(s)
+	return strings.NewReplacer(markStart, "<mark>", markStop, "</mark>").Replace(s)
+}
@@ -6,6 +6,7 @@ import (
 
 	"confsite/backend/internal/adapters/db/sqlc"
 	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -23,8 +24,8 @@ func NewTalksRepo(db *pgxpool.Pool) *TalksRepo {
 func (r *TalksRepo) Create(ctx context.Context, t domain.Talk) (uuid.UUID, error) {
 	id := uuid.New()
 	_, err := r.db.Exec(ctx, `
-INSERT INTO talks (id, speaker_user_id, section_id, title, affiliation, abstract, kind, authors, status, file_url)
-VALUES ($1,$2,$3,$4,$5,$6,$7,$8,'WAITING',$9)`,
+INSERT INTO talks (id, speaker_user_id, section_id, title, affiliation, abstract, kind, authors, status, file_url, search_vector)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,'WAITING',$9, talk_search_vector($4,$5,$6,$8,$3))`,
 		id, t.SpeakerUserID, t.SectionID, t.Title, t.Affiliation, t.Abstract, string(t.Kind), t.AuthorsJSON, t.FileURL)
 	return id, err
 }
@@ -32,7 +33,8 @@ VALUES ($1,$2,$3,$4,$5,$6,$7,$8,'WAITING',$9)`,
 func (r *TalksRepo) Update(ctx context.Context, t domain.Talk) error {
 	_, err := r.db.Exec(ctx, `
 UPDATE talks
-SET section_id=$2, title=$3, affiliation=$4, abstract=$5, kind=$6, authors=$7, status='WAITING', reviewed_at=NULL
+SET section_id=$2, title=$3, affiliation=$4, abstract=$5, kind=$6, authors=$7, status='WAITING', reviewed_at=NULL,
+    revised_at=now(), search_vector=talk_search_vector($3,$4,$5,$7,$2)
 WHERE id=$1`,
 		t.ID, t.SectionID, t.Title, t.Affiliation, t.Abstract, string(t.Kind), t.AuthorsJSON)
 	return err
@@ -48,9 +50,9 @@ func (r *TalksRepo) Get(ctx context.Context, id uuid.UUID) (*domain.Talk, error)
 	var kind string
 	var status string
 	if err := r.db.QueryRow(ctx, `
-SELECT id, speaker_user_id, section_id, title, affiliation, abstract, kind, status, authors, file_url, created_at
+SELECT id, speaker_user_id, section_id, title, affiliation, abstract, kind, status, authors, file_url, created_at, withdrawn_at, withdrawal_reason
 FROM talks WHERE id=$1`, id).Scan(
-		&t.ID, &t.SpeakerUserID, &t.SectionID, &t.Title, &t.Affiliation, &t.Abstract, &kind, &status, &t.AuthorsJSON, &t.FileURL, &t.CreatedAt,
+		&t.ID, &t.SpeakerUserID, &t.SectionID, &t.Title, &t.Affiliation, &t.Abstract, &kind, &status, &t.AuthorsJSON, &t.FileURL, &t.CreatedAt, &t.WithdrawnAt, &t.WithdrawalReason,
 	); err != nil {
 		return nil, err
 	}
@@ -61,7 +63,7 @@ FROM talks WHERE id=$1`, id).Scan(
 
 func (r *TalksRepo) ListBySpeaker(ctx context.Context, speakerID uuid.UUID) ([]domain.Talk, error) {
 	rows, err := r.db.Query(ctx, `
-SELECT id, speaker_user_id, section_id, title, affiliation, abstract, kind, status, authors, file_url, created_at
+SELECT id, speaker_user_id, section_id, title, affiliation, abstract, kind, status, authors, file_url, created_at, withdrawn_at, withdrawal_reason
 FROM talks WHERE speaker_user_id=$1 ORDER BY created_at DESC`, speakerID)
 	if err != nil {
 		return nil, err
@@ -73,7 +75,7 @@ FROM talks WHERE speaker_user_id=$1 ORDER BY created_at DESC`, speakerID)
 		var t domain.Talk
 		var kind string
 		var status string
-		if err := rows.Scan(&t.ID, &t.SpeakerUserID, &t.SectionID, &t.Title, &t.Affiliation, &t.Abstract, &kind, &status, &t.AuthorsJSON, &t.FileURL, &t.CreatedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.SpeakerUserID, &t.SectionID, &t.Title, &t.Affiliation, &t.Abstract, &kind, &status, &t.AuthorsJSON, &t.FileURL, &t.CreatedAt, &t.WithdrawnAt, &t.WithdrawalReason); err != nil {
 			return nil, err
 		}
 		t.Kind = domain.TalkKind(kind)
@@ -83,8 +85,11 @@ FROM talks WHERE speaker_user_id=$1 ORDER BY created_at DESC`, speakerID)
 	return out, rows.Err()
 }
 
+// CountBySpeaker counts talks towards the per-speaker limit; withdrawn talks do not count.
 func (r *TalksRepo) CountBySpeaker(ctx context.Context, speakerID uuid.UUID) (int64, error) {
-	return r.q.CountTalksBySpeaker(ctx, speakerID)
+	var n int64
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM talks WHERE speaker_user_id=$1 AND status<>'WITHDRAWN'`, speakerID).Scan(&n)
+	return n, err
 }
 
 func (r *TalksRepo) ListAdmin(ctx context.Context, sectionID *uuid.UUID, onlyPlenary bool) ([]domain.AdminTalkRow, error) {
@@ -96,7 +101,8 @@ SELECT
 	t.section_id, t.schedule_time, t.file_url,
 	s.title_ru, s.title_en,
 	p.surname || ' ' || p.name || ' ' || p.patronymic AS speaker_full_name,
-	p.city, p.affiliation
+	p.city, p.affiliation,
+	t.withdrawn_at, t.withdrawal_reason
 FROM talks t
 JOIN profiles p ON p.user_id=t.speaker_user_id
 LEFT JOIN sections s ON s.id=t.section_id
@@ -123,6 +129,7 @@ ORDER BY COALESCE(t.schedule_time, t.created_at) DESC`
 			&row.SectionID, &row.ScheduleTime, &row.FileURL,
 			&row.SectionTitleRu, &row.SectionTitleEn,
 			&row.SpeakerFullName, &row.SpeakerCity, &row.SpeakerAffiliation,
+			&row.WithdrawnAt, &row.WithdrawalReason,
 		); err != nil {
 			println("ListAdmin scan error:", err.Error())
 			return nil, err
@@ -140,10 +147,95 @@ ORDER BY COALESCE(t.schedule_time, t.created_at) DESC`
 	return out, nil
 }
 
+// talkListSorts are the sort keys of ListAdminPage.
+var talkListSorts = map[string]string{
+	"createdAt":    "t.created_at",
+	"title":        "t.title",
+	"status":       "t.status",
+	"kind":         "t.kind",
+	"section":      "s.sort_order",
+	"scheduleTime": "t.schedule_time",
+	"speaker":      "p.surname || ' ' || p.name || ' ' || p.patronymic",
+}
+
+func (r *TalksRepo) ListAdminPage(ctx context.Context, f ports.TalkListFilter) ([]domain.AdminTalkRow, int, error) {
+	var w whereBuilder
+	if f.Status != nil {
+		w.add(`t.status = ?`, string(*f.Status))
+	}
+	if f.SectionID != nil {
+		w.add(`t.section_id = ?`, *f.SectionID)
+	}
+	if f.Kind != nil {
+		w.add(`t.kind = ?`, string(*f.Kind))
+	}
+	if f.ConsentsUploaded != nil {
+		w.add(consentsUploadedSQL+` = ?`, *f.ConsentsUploaded)
+	}
+	if f.From != nil {
+		w.add(`t.created_at >= ?`, *f.From)
+	}
+	if f.To != nil {
+		w.add(`t.created_at < ?`, *f.To)
+	}
+	if f.Search != "" {
+		q := likePattern(f.Search)
+		w.add(`(t.title ILIKE ? OR (p.surname || ' ' || p.name || ' ' || p.patronymic) ILIKE ? OR u.email ILIKE ? OR p.affiliation ILIKE ?)`, q, q, q, q)
+	}
+	where := w.sql()
+	page, err := w.page(f.ListQuery, talkListSorts, "COALESCE(t.schedule_time, t.created_at) DESC", "t.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+SELECT
+	t.id, t.title, t.kind, t.authors, t.abstract,
+	t.status,
+	t.section_id, t.schedule_time, t.file_url,
+	s.title_ru, s.title_en,
+	p.surname || ' ' || p.name || ' ' || p.patronymic AS speaker_full_name,
+	p.city, p.affiliation,
+	t.withdrawn_at, t.withdrawal_reason,
+	count(*) OVER ()
+FROM talks t
+JOIN users u ON u.id=t.speaker_user_id
+JOIN profiles p ON p.user_id=t.speaker_user_id
+LEFT JOIN sections s ON s.id=t.section_id
+`+where+`
+`+page, w.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := []domain.AdminTalkRow{}
+	total := 0
+	for rows.Next() {
+		var row domain.AdminTalkRow
+		var kind, status string
+		if err := rows.Scan(
+			&row.ID, &row.Title, &kind, &row.AuthorsJSON, &row.Abstract, &status,
+			&row.SectionID, &row.ScheduleTime, &row.FileURL,
+			&row.SectionTitleRu, &row.SectionTitleEn,
+			&row.SpeakerFullName, &row.SpeakerCity, &row.SpeakerAffiliation,
+			&row.WithdrawnAt, &row.WithdrawalReason,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		row.Kind = domain.TalkKind(kind)
+		row.Status = domain.TalkStatus(status)
+		out = append(out, row)
+	}
+	return out, total, rows.Err()
+}
+
 func (r *TalksRepo) UpdateSchedule(ctx context.Context, talkID uuid.UUID, sectionID *uuid.UUID, scheduleTime *time.Time) error {
 	_, err := r.db.Exec(ctx, `
 UPDATE talks
-SET section_id=$1, schedule_time=$2
+SET section_id=$1, schedule_time=$2,
+    search_vector=talk_search_vector(title, affiliation, abstract, authors, $1)
 WHERE id=$3`,
 		sectionID, scheduleTime, talkID)
 	return err
@@ -153,7 +245,7 @@ func (r *TalksRepo) UpdateFile(ctx context.Context, talkID uuid.UUID, fileURL st
 	println("UpdateFile: talkID =", talkID.String(), "fileURL =", fileURL)
 	_, err := r.db.Exec(ctx, `
 UPDATE talks
-SET file_url=$1, status='WAITING', reviewed_at=NULL
+SET file_url=$1, status='WAITING', reviewed_at=NULL, revised_at=now()
 WHERE id=$2`,
 		fileURL, talkID)
 	if err != nil {
@@ -171,3 +263,11 @@ SET status=$2, reviewed_at=CASE WHEN $2='WAITING' THEN NULL ELSE now() END
 WHERE id=$1`, talkID, string(status))
 	return err
 }
+
+func (r *TalksRepo) Withdraw(ctx context.Context, talkID uuid.UUID, reason string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+UPDATE talks
+SET status='WITHDRAWN', withdrawn_at=$2, withdrawal_reason=$3, schedule_time=NULL
+WHERE id=$1`, talkID, at, reason)
+	return err
+}
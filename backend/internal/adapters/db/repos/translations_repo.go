@@ -0,0 +1,180 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// translationTable describes where the translations of one entity live.
+// baseBody is the prefix of the ru/en body columns ("" for sections);
+// pages are addressed by slug, the others by id.
+type translationTable struct {
+	table, fk, base, baseBody string
+	bySlug                    bool
+}
+
+var translationTables = map[domain.ContentEntity]translationTable{
+	domain.ContentPage:     {table: "page_translations", fk: "page_id", base: "page_contents", baseBody: "body", bySlug: true},
+	domain.ContentNews:     {table: "news_translations", fk: "news_id", base: "news", baseBody: "body"},
+	domain.ContentSection:  {table: "section_translations", fk: "section_id", base: "sections"},
+	domain.ContentMaterial: {table: "material_translations", fk: "material_id", base: "materials", baseBody: "description"},
+}
+
+// keyWhere is the condition on the base table (aliased b) for one key.
+func (t translationTable) keyWhere() string {
+	if t.bySlug {
+		return "b.slug = $1"
+	}
+	return "b.id::text = $1"
+}
+
+func (t translationTable) keyExpr() string {
+	if t.bySlug {
+		return "b.slug"
+	}
+	return "b.id::text"
+}
+
+func (t translationTable) bodyCols() (ru, en string) {
+	if t.baseBody == "" {
+		return "''", "''"
+	}
+	return "COALESCE(b." + t.baseBody + "_ru, '')", "COALESCE(b." + t.baseBody + "_en, '')"
+}
+
+type TranslationsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTranslationsRepo(db *pgxpool.Pool) *TranslationsRepo {
+	return &TranslationsRepo{db: db}
+}
+
+func tableFor(entity domain.ContentEntity) (translationTable, error) {
+	t, ok := translationTables[entity]
+	if !ok {
+		return t, domain.ErrInvalidInput
+	}
+	return t, nil
+}
+
+func (r *TranslationsRepo) List(ctx context.Context, entity domain.ContentEntity, key string) ([]domain.Translation, error) {
+	t, err := tableFor(entity)
+	if err != nil {
+		return nil, err
+	}
+	bodyRu, bodyEn := t.bodyCols()
+	var id uuid.UUID
+	ru := domain.Translation{Entity: entity, Key: key, Locale: "ru"}
+	en := domain.Translation{Entity: entity, Key: key, Locale: "en"}
+	err = r.db.QueryRow(ctx, fmt.Sprintf(`
+SELECT b.id, b.title_ru, %s, b.title_en, %s FROM %s b WHERE %s`, bodyRu, bodyEn, t.base, t.keyWhere()), key).
+		Scan(&id, &ru.Title, &ru.Body, &en.Title, &en.Body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := []domain.Translation{ru, en}
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+SELECT locale, title, body, updated_at FROM %s WHERE %s = $1 ORDER BY locale`, t.table, t.fk), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		tr := domain.Translation{Entity: entity, Key: key}
+		if err := rows.Scan(&tr.Locale, &tr.Title, &tr.Body, &tr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, tr)
+	}
+	return out, rows.Err()
+}
+
+func (r *TranslationsRepo) ForKeys(ctx context.Context, entity domain.ContentEntity, keys, locales []string) ([]domain.Translation, error) {
+	t, err := tableFor(entity)
+	if err != nil {
+		return nil, err
+	}
+	out := []domain.Translation{}
+	if len(keys) == 0 || len(locales) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+SELECT %s, tr.locale, tr.title, tr.body, tr.updated_at
+FROM %s tr JOIN %s b ON b.id = tr.%s
+WHERE %s = ANY($1::text[]) AND tr.locale = ANY($2::text[])`, t.keyExpr(), t.table, t.base, t.fk, t.keyExpr()), keys, locales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		tr := domain.Translation{Entity: entity}
+		if err := rows.Scan(&tr.Key, &tr.Locale, &tr.Title, &tr.Body, &tr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, tr)
+	}
+	return out, rows.Err()
+}
+
+func (r *TranslationsRepo) Upsert(ctx context.Context, tr domain.Translation) error {
+	t, err := tableFor(tr.Entity)
+	if err != nil {
+		return err
+	}
+	if tr.Locale == "ru" || tr.Locale == "en" {
+		set, args := "title_"+tr.Locale+" = $2", []any{tr.Key, tr.Title}
+		if t.baseBody != "" {
+			set += ", " + t.baseBody + "_" + tr.Locale + " = $3"
+			args = append(args, tr.Body)
+		}
+		set += ", updated_at = now()"
+		tag, err := r.db.Exec(ctx, fmt.Sprintf(`UPDATE %s b SET %s WHERE %s`, t.base, set, t.keyWhere()), args...)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrNotFound
+		}
+		return nil
+	}
+	tag, err := r.db.Exec(ctx, fmt.Sprintf(`
+INSERT INTO %s (%s, locale, title, body)
+SELECT b.id, $2, $3, $4 FROM %s b WHERE %s
+ON CONFLICT (%s, locale) DO UPDATE SET title = EXCLUDED.title, body = EXCLUDED.body, updated_at = now()`,
+		t.table, t.fk, t.base, t.keyWhere(), t.fk), tr.Key, tr.Locale, tr.Title, tr.Body)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TranslationsRepo) Delete(ctx context.Context, entity domain.ContentEntity, key, locale string) error {
+	t, err := tableFor(entity)
+	if err != nil {
+		return err
+	}
+	tag, err := r.db.Exec(ctx, fmt.Sprintf(`
+DELETE FROM %s tr USING %s b WHERE b.id = tr.%s AND %s AND tr.locale = $2`,
+		t.table, t.base, t.fk, t.keyWhere()), key, locale)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
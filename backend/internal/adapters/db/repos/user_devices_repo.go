@@ -0,0 +1,35 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserDevicesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserDevicesRepo(db *pgxpool.Pool) *UserDevicesRepo {
+	return &UserDevicesRepo{db: db}
+}
+
+func (r *UserDevicesRepo) Touch(ctx context.Context, userID uuid.UUID, fingerprint, userAgent, ip string, at time.Time) (bool, error) {
+	var inserted bool
+	err := r.db.QueryRow(ctx, `
+INSERT INTO user_devices (user_id, fingerprint, user_agent, last_ip, first_seen_at, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $5)
+ON CONFLICT (user_id, fingerprint) DO UPDATE SET
+  last_ip = $4,
+  last_seen_at = $5
+RETURNING (xmax = 0)`, userID, fingerprint, userAgent, ip, at).Scan(&inserted)
+	return inserted, err
+}
+
+func (r *UserDevicesRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var n int64
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM user_devices WHERE user_id=$1`, userID).Scan(&n)
+	return n, err
+}
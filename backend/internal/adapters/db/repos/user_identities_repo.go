@@ -0,0 +1,40 @@
+package repos
+
+import (
+	"context"
+
+	"confsite/backend/internal/ports"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserIdentitiesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserIdentitiesRepo(db *pgxpool.Pool) *UserIdentitiesRepo {
+	return &UserIdentitiesRepo{db: db}
+}
+
+func (r *UserIdentitiesRepo) ByProviderSubject(ctx context.Context, provider, subject string) (*ports.UserIdentity, error) {
+	var id ports.UserIdentity
+	err := r.db.QueryRow(ctx, `
+SELECT user_id, provider, subject, email
+FROM user_identities
+WHERE provider=$1 AND subject=$2`, provider, subject).Scan(&id.UserID, &id.Provider, &id.Subject, &id.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func (r *UserIdentitiesRepo) Link(ctx context.Context, id ports.UserIdentity) error {
+	_, err := r.db.Exec(ctx, `
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES ($1,$2,$3,$4)
+ON CONFLICT (provider, subject) DO UPDATE SET
+  email = $4,
+  last_login_at = now()`,
+		id.UserID, id.Provider, id.Subject, id.Email)
+	return err
+}
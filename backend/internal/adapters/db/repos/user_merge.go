@@ -0,0 +1,247 @@
+package repos
+
+import (
+	"context"
+	"strings"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// mergeProfileColumns maps the API names of profile fields to their columns.
+var mergeProfileColumns = map[string]string{
+	"surname":               "surname",
+	"name":                  "name",
+	"patronymic":            "patronymic",
+	"birthDate":             "birth_date",
+	"city":                  "city",
+	"academicDegree":        "academic_degree",
+	"affiliation":           "affiliation",
+	"position":              "position",
+	"phone":                 "phone",
+	"postalAddress":         "postal_address",
+	"consentDataProcessing": "consent_data_processing",
+	"consentDataTransfer":   "consent_data_transfer",
+}
+
+// Merge moves everything owned by m.SourceID to m.TargetID and disables the
+// source account, all in one transaction with an audit entry. An approved
+// source hands its place to a target that is not approved yet, under the
+// capacity lock; the merge is refused with domain.ErrCapacityFull when the
+// target's category has no room, and with domain.ErrTalkLimitReached when the
+// accounts hold more than three talks together. Consent files
+// and signed documents present on both accounts keep the newer upload.
+// Registration answers of the target win over the source's. The source's API
+// tokens are revoked and its pending verification and email change requests
+// dropped rather than moved.
+func (r *UsersRepo) Merge(ctx context.Context, m domain.UserMerge) (domain.UserMergeResult, error) {
+	var res domain.UserMergeResult
+	if m.SourceID == m.TargetID {
+		return res, domain.ErrInvalidInput
+	}
+	var setCols []string
+	for _, f := range m.ProfileFromSource {
+		col, ok := mergeProfileColumns[f]
+		if !ok {
+			return res, domain.ErrInvalidInput
+		}
+		setCols = append(setCols, col+"=s."+col)
+	}
+
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockCapacity(ctx, tx); err != nil {
+			return err
+		}
+		var sourceEmail string
+		var sourceStatus, targetStatus domain.UserStatus
+		var n int
+		rows, err := tx.Query(ctx, `
+SELECT id, email, status, disabled_at IS NOT NULL FROM users WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE`,
+			[]uuid.UUID{m.SourceID, m.TargetID})
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var u domain.User
+			var disabled bool
+			if err := rows.Scan(&u.ID, &u.Email, &u.Status, &disabled); err != nil {
+				rows.Close()
+				return err
+			}
+			if disabled {
+				rows.Close()
+				return domain.ErrInvalidState
+			}
+			if u.ID == m.SourceID {
+				sourceEmail = u.Email
+				sourceStatus = u.Status
+			} else {
+				targetStatus = u.Status
+			}
+			n++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if n != 2 {
+			return domain.ErrNotFound
+		}
+
+		var talks int
+		if err := tx.QueryRow(ctx, `
+SELECT count(*) FROM talks WHERE speaker_user_id = ANY($1::uuid[]) AND status<>'WITHDRAWN'`,
+			[]uuid.UUID{m.SourceID, m.TargetID}).Scan(&talks); err != nil {
+			return err
+		}
+		if talks > 3 {
+			return domain.ErrTalkLimitReached
+		}
+
+		// profile: fill a missing target profile from the source, then copy the chosen fields
+		if _, err := tx.Exec(ctx, `
+INSERT INTO profiles (user_id, surname, name, patronymic, birth_date, city, academic_degree, affiliation,
+  position, phone, postal_address, consent_data_processing, consent_data_transfer)
+SELECT $2, surname, name, patronymic, birth_date, city, academic_degree, affiliation,
+  position, phone, postal_address, consent_data_processing, consent_data_transfer
+FROM profiles WHERE user_id=$1
+ON CONFLICT (user_id) DO NOTHING`, m.SourceID, m.TargetID); err != nil {
+			return err
+		}
+		if len(setCols) > 0 {
+			if _, err := tx.Exec(ctx, `
+UPDATE profiles t SET `+strings.Join(setCols, ", ")+`, updated_at=now()
+FROM profiles s
+WHERE t.user_id=$2 AND s.user_id=$1`, m.SourceID, m.TargetID); err != nil {
+				return err
+			}
+		}
+
+		tag, err := tx.Exec(ctx, `UPDATE talks SET speaker_user_id=$2 WHERE speaker_user_id=$1`, m.SourceID, m.TargetID)
+		if err != nil {
+			return err
+		}
+		res.Talks = int(tag.RowsAffected())
+
+		// consent files: drop whichever side of a clash is older, then move the rest
+		if _, err := tx.Exec(ctx, `
+DELETE FROM consent_files c
+USING consent_files o
+WHERE c.consent_type = o.consent_type
+  AND ((c.user_id=$1 AND o.user_id=$2 AND c.uploaded_at <= o.uploaded_at)
+    OR (c.user_id=$2 AND o.user_id=$1 AND c.uploaded_at < o.uploaded_at))`, m.SourceID, m.TargetID); err != nil {
+			return err
+		}
+		tag, err = tx.Exec(ctx, `UPDATE consent_files SET user_id=$2 WHERE user_id=$1`, m.SourceID, m.TargetID)
+		if err != nil {
+			return err
+		}
+		res.ConsentFiles = int(tag.RowsAffected())
+
+		if _, err := tx.Exec(ctx, `
+DELETE FROM signed_documents d
+USING signed_documents o
+WHERE d.document_type = o.document_type AND d.talk_id IS NOT DISTINCT FROM o.talk_id
+  AND ((d.user_id=$1 AND o.user_id=$2 AND d.uploaded_at <= o.uploaded_at)
+    OR (d.user_id=$2 AND o.user_id=$1 AND d.uploaded_at < o.uploaded_at))`, m.SourceID, m.TargetID); err != nil {
+			return err
+		}
+		tag, err = tx.Exec(ctx, `UPDATE signed_documents SET user_id=$2 WHERE user_id=$1`, m.SourceID, m.TargetID)
+		if err != nil {
+			return err
+		}
+		res.SignedDocuments = int(tag.RowsAffected())
+
+		tag, err = tx.Exec(ctx, `UPDATE refresh_sessions SET user_id=$2 WHERE user_id=$1 AND revoked_at IS NULL`, m.SourceID, m.TargetID)
+		if err != nil {
+			return err
+		}
+		res.Sessions = int(tag.RowsAffected())
+
+		tag, err = tx.Exec(ctx, `
+INSERT INTO user_roles (user_id, role_id, section_id)
+SELECT $2, role_id, section_id FROM user_roles WHERE user_id=$1
+ON CONFLICT DO NOTHING`, m.SourceID, m.TargetID)
+		if err != nil {
+			return err
+		}
+		res.Roles = int(tag.RowsAffected())
+		if _, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id=$1`, m.SourceID); err != nil {
+			return err
+		}
+
+		tag, err = tx.Exec(ctx, `UPDATE user_identities SET user_id=$2 WHERE user_id=$1`, m.SourceID, m.TargetID)
+		if err != nil {
+			return err
+		}
+		res.Identities = int(tag.RowsAffected())
+
+		if _, err := tx.Exec(ctx, `
+INSERT INTO registration_answers (user_id, answers)
+SELECT $2, answers FROM registration_answers WHERE user_id=$1
+ON CONFLICT (user_id) DO UPDATE SET answers = EXCLUDED.answers || registration_answers.answers, updated_at=now()`,
+			m.SourceID, m.TargetID); err != nil {
+			return err
+		}
+
+		for _, q := range []string{
+			`UPDATE api_tokens SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL`,
+			`DELETE FROM email_verify_tokens WHERE user_id=$1`,
+			`DELETE FROM email_change_requests WHERE user_id=$1`,
+			`DELETE FROM waitlist_entries WHERE user_id=$1`,
+			`DELETE FROM registration_answers WHERE user_id=$1`,
+		} {
+			if _, err := tx.Exec(ctx, q, m.SourceID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `
+UPDATE users SET disabled_at=now(), merged_into=$2, updated_at=now() WHERE id=$1`, m.SourceID, m.TargetID); err != nil {
+			return err
+		}
+
+		// the disabled source no longer counts, so the target needs the room it freed
+		if sourceStatus == domain.StatusApproved && targetStatus != domain.StatusApproved {
+			limits, err := loadLimits(ctx, tx)
+			if err != nil {
+				return err
+			}
+			cat, err := userCategory(ctx, tx, m.TargetID, m.CategoryField)
+			if err != nil {
+				return err
+			}
+			room, err := hasRoom(ctx, tx, limits, m.TargetID, m.CategoryField, cat)
+			if err != nil {
+				return err
+			}
+			if !room {
+				return domain.ErrCapacityFull
+			}
+			if _, err := tx.Exec(ctx, `
+UPDATE users SET status='APPROVED', cancelled_at=NULL, cancellation_reason=NULL, updated_at=now() WHERE id=$1`, m.TargetID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM waitlist_entries WHERE user_id=$1`, m.TargetID); err != nil {
+				return err
+			}
+			res.ApprovalCarried = true
+		}
+
+		_, err = tx.Exec(ctx, `
+INSERT INTO audit_logs (actor_user_id, action, entity, entity_id, details)
+VALUES ($1,'user.merge','user',$2,$3)`, m.ActorID, m.TargetID, map[string]any{
+			"sourceId":          m.SourceID,
+			"sourceEmail":       sourceEmail,
+			"profileFromSource": m.ProfileFromSource,
+			"moved":             res,
+		})
+		return err
+	})
+	if err != nil {
+		return domain.UserMergeResult{}, err
+	}
+	return res, nil
+}
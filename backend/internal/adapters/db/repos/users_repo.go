@@ -3,11 +3,15 @@
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"confsite/backend/internal/adapters/db/sqlc"
 	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -28,20 +32,27 @@ func (r *UsersRepo) Create(ctx context.Context, email, passwordHash string) (uui
 	return id, err
 }
 
-func (r *UsersRepo) ByEmail(ctx context.Context, email string) (*domain.User, []domain.RoleAssignment, error) {
-	u, err := r.q.GetUserByEmail(ctx, email)
-	if err != nil {
-		return nil, nil, err
-	}
-	roles, err := r.loadRoles(ctx, u.ID)
-	if err != nil {
-		return nil, nil, err
+const userColumns = `id, email, password_hash, email_verified, status, cancelled_at, cancellation_reason, disabled_at, merged_into, preferred_lang, email_undeliverable_at, email_bounce_reason, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var u domain.User
+	if err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerified, &u.Status,
+		&u.CancelledAt, &u.CancellationReason, &u.DisabledAt, &u.MergedInto, &u.PreferredLang, &u.EmailUndeliverableAt, &u.EmailBounceReason, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
 	}
-	return mapUser(u), roles, nil
+	return &u, nil
+}
+
+func (r *UsersRepo) ByEmail(ctx context.Context, email string) (*domain.User, []domain.RoleAssignment, error) {
+	return r.byQuery(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email)
 }
 
 func (r *UsersRepo) ByID(ctx context.Context, id uuid.UUID) (*domain.User, []domain.RoleAssignment, error) {
-	u, err := r.q.GetUserByID(ctx, id)
+	return r.byQuery(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+}
+
+func (r *UsersRepo) byQuery(ctx context.Context, query string, arg any) (*domain.User, []domain.RoleAssignment, error) {
+	u, err := scanUser(r.db.QueryRow(ctx, query, arg))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -49,7 +60,7 @@ func (r *UsersRepo) ByID(ctx context.Context, id uuid.UUID) (*domain.User, []dom
 	if err != nil {
 		return nil, nil, err
 	}
-	return mapUser(u), roles, nil
+	return u, roles, nil
 }
 
 func (r *UsersRepo) SetEmailVerified(ctx context.Context, id uuid.UUID, verified bool) error {
@@ -61,11 +72,65 @@ func (r *UsersRepo) SetStatus(ctx context.Context, id uuid.UUID, status domain.U
 	return r.q.UpdateUserStatus(ctx, sqlc.UpdateUserStatusParams{ID: id, Status: string(status)})
 }
 
+func (r *UsersRepo) SetPreferredLang(ctx context.Context, id uuid.UUID, lang string) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET preferred_lang=$1, updated_at=now() WHERE id=$2`, lang, id)
+	return err
+}
+
+func (r *UsersRepo) PreferredLangs(ctx context.Context, emails []string) (map[string]string, error) {
+	lower := make([]string, 0, len(emails))
+	for _, e := range emails {
+		lower = append(lower, strings.ToLower(strings.TrimSpace(e)))
+	}
+	rows, err := r.db.Query(ctx, `
+SELECT lower(email), preferred_lang FROM users
+WHERE lower(email) = ANY($1::text[]) AND preferred_lang IS NOT NULL`, lower)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]string{}
+	for rows.Next() {
+		var email, lang string
+		if err := rows.Scan(&email, &lang); err != nil {
+			return nil, err
+		}
+		out[email] = lang
+	}
+	return out, rows.Err()
+}
+
 func (r *UsersRepo) SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
 	_, err := r.db.Exec(ctx, `UPDATE users SET password_hash=$1 WHERE id=$2`, passwordHash, id)
 	return err
 }
 
+// DeleteUnverifiedBefore only removes accounts that never got past sign-up:
+// still WAITING and with nothing attached that a cascade would destroy. The
+// empty profile every registration creates does not count.
+func (r *UsersRepo) DeleteUnverifiedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+DELETE FROM users u
+WHERE u.email_verified = false
+  AND u.status = 'WAITING'
+  AND u.created_at < $1
+  AND NOT EXISTS (SELECT 1 FROM talks t WHERE t.speaker_user_id = u.id)
+  AND NOT EXISTS (
+    SELECT 1 FROM profiles p WHERE p.user_id = u.id
+      AND (trim(p.surname) <> '' OR trim(p.name) <> '' OR p.consent_data_processing OR p.consent_data_transfer))
+  AND NOT EXISTS (SELECT 1 FROM consent_files cf WHERE cf.user_id = u.id)
+  AND NOT EXISTS (SELECT 1 FROM signed_documents sd WHERE sd.user_id = u.id)
+  AND NOT EXISTS (SELECT 1 FROM registration_answers ra WHERE ra.user_id = u.id)
+  AND NOT EXISTS (
+    SELECT 1 FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+    WHERE ur.user_id = u.id AND r.code = 'ADMIN'
+  )`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (r *UsersRepo) AssignRole(ctx context.Context, userID uuid.UUID, role domain.Role, sectionID *uuid.UUID) error {
 	_, err := r.db.Exec(ctx, `
 INSERT INTO user_roles(user_id, role_id, section_id)
@@ -86,26 +151,124 @@ WHERE ur.user_id=$1 AND ur.role_id=r.id AND r.code=$2 AND
 	return err
 }
 
-func (r *UsersRepo) ListUsers(ctx context.Context) ([]domain.UserWithRoles, error) {
-	rows, err := r.db.Query(ctx, `SELECT id,email,password_hash,email_verified,status,created_at,updated_at FROM users ORDER BY created_at DESC`)
+// userListSorts are the sort keys of ListAdmin.
+var userListSorts = map[string]string{
+	"createdAt":   "u.created_at",
+	"email":       "u.email",
+	"status":      "u.status",
+	"name":        "p.surname || ' ' || p.name || ' ' || p.patronymic",
+	"affiliation": "p.affiliation",
+	"city":        "p.city",
+}
+
+func (r *UsersRepo) ListAdmin(ctx context.Context, f ports.UserListFilter) ([]domain.AdminUserRow, int, error) {
+	var w whereBuilder
+	if f.Status != nil {
+		w.add(`u.status = ?`, string(*f.Status))
+	}
+	if f.Role != nil {
+		w.add(`EXISTS (SELECT 1 FROM user_roles ur JOIN roles r ON r.id=ur.role_id WHERE ur.user_id=u.id AND r.code=?)`, string(*f.Role))
+	}
+	if f.SectionID != nil || f.Kind != nil {
+		var kind *string
+		if f.Kind != nil {
+			k := string(*f.Kind)
+			kind = &k
+		}
+		w.add(`EXISTS (SELECT 1 FROM talks t WHERE t.speaker_user_id=u.id AND t.status<>'WITHDRAWN'
+    AND (?::uuid IS NULL OR t.section_id=?) AND (?::text IS NULL OR t.kind=?))`, f.SectionID, f.SectionID, kind, kind)
+	}
+	if f.EmailVerified != nil {
+		w.add(`u.email_verified = ?`, *f.EmailVerified)
+	}
+	if f.ConsentsUploaded != nil {
+		w.add(consentsUploadedSQL+` = ?`, *f.ConsentsUploaded)
+	}
+	if f.EmailUndeliverable != nil {
+		w.add(`(u.email_undeliverable_at IS NOT NULL) = ?`, *f.EmailUndeliverable)
+	}
+	if f.From != nil {
+		w.add(`u.created_at >= ?`, *f.From)
+	}
+	if f.To != nil {
+		w.add(`u.created_at < ?`, *f.To)
+	}
+	if f.Search != "" {
+		q := likePattern(f.Search)
+		w.add(`(u.email ILIKE ? OR (p.surname || ' ' || p.name || ' ' || p.patronymic) ILIKE ? OR p.affiliation ILIKE ?
+    OR EXISTS (SELECT 1 FROM talks t WHERE t.speaker_user_id=u.id AND t.title ILIKE ?))`, q, q, q, q)
+	}
+	where := w.sql()
+	page, err := w.page(f.ListQuery, userListSorts, "u.created_at DESC", "u.id")
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+SELECT u.id, u.email, u.password_hash, u.email_verified, u.status, u.cancelled_at, u.cancellation_reason, u.disabled_at, u.merged_into, u.preferred_lang, u.email_undeliverable_at, u.email_bounce_reason, u.created_at, u.updated_at,
+  COALESCE((SELECT json_agg(json_build_object('role', r.code, 'sectionId', ur.section_id))
+            FROM user_roles ur JOIN roles r ON r.id=ur.role_id WHERE ur.user_id=u.id), '[]'::json),
+  p.user_id, p.surname, p.name, p.patronymic, p.birth_date, p.city, p.academic_degree, p.affiliation,
+  p.position, p.phone, p.postal_address, p.consent_data_processing, p.consent_data_transfer, p.updated_at,
+  count(*) OVER ()
+FROM users u
+LEFT JOIN profiles p ON p.user_id = u.id
+`+where+`
+`+page, w.args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	out := []domain.UserWithRoles{}
+	out := []domain.AdminUserRow{}
+	total := 0
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerified, &u.Status, &u.CreatedAt, &u.UpdatedAt); err != nil {
-			return nil, err
+		var row domain.AdminUserRow
+		var roles []struct {
+			Role      string     `json:"role"`
+			SectionID *uuid.UUID `json:"sectionId"`
 		}
-		rs, err := r.loadRoles(ctx, u.ID)
-		if err != nil {
-			return nil, err
+		var (
+			pUserID                                        *uuid.UUID
+			surname, name, patronymic, city, affiliation   *string
+			position, phone, postalAddress, academicDegree *string
+			birthDate, profileUpdatedAt                    *time.Time
+			consentDataProcessing, consentDataTransfer     *bool
+		)
+		u := &row.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerified, &u.Status,
+			&u.CancelledAt, &u.CancellationReason, &u.DisabledAt, &u.MergedInto, &u.PreferredLang, &u.EmailUndeliverableAt, &u.EmailBounceReason, &u.CreatedAt, &u.UpdatedAt,
+			&roles,
+			&pUserID, &surname, &name, &patronymic, &birthDate, &city, &academicDegree, &affiliation,
+			&position, &phone, &postalAddress, &consentDataProcessing, &consentDataTransfer, &profileUpdatedAt,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		for _, ra := range roles {
+			row.Roles = append(row.Roles, domain.RoleAssignment{Role: domain.Role(ra.Role), SectionID: ra.SectionID})
 		}
-		out = append(out, domain.UserWithRoles{User: u, Roles: rs})
+		if pUserID != nil {
+			row.Profile = &domain.Profile{
+				UserID:                *pUserID,
+				Surname:               *surname,
+				Name:                  *name,
+				Patronymic:            *patronymic,
+				BirthDate:             *birthDate,
+				City:                  *city,
+				AcademicDegree:        academicDegree,
+				Affiliation:           *affiliation,
+				Position:              *position,
+				Phone:                 *phone,
+				PostalAddress:         *postalAddress,
+				ConsentDataProcessing: *consentDataProcessing,
+				ConsentDataTransfer:   *consentDataTransfer,
+				UpdatedAt:             *profileUpdatedAt,
+			}
+		}
+		out = append(out, row)
 	}
-	return out, rows.Err()
+	return out, total, rows.Err()
 }
 
 func (r *UsersRepo) loadRoles(ctx context.Context, userID uuid.UUID) ([]domain.RoleAssignment, error) {
@@ -131,16 +294,4 @@ WHERE ur.user_id=$1`, userID)
 	return out, rows.Err()
 }
 
-func mapUser(u sqlc.User) *domain.User {
-	return &domain.User{
-		ID:            u.ID,
-		Email:         u.Email,
-		PasswordHash:  u.PasswordHash,
-		EmailVerified: u.EmailVerified,
-		Status:        domain.UserStatus(u.Status),
-		CreatedAt:     u.CreatedAt,
-		UpdatedAt:     u.UpdatedAt,
-	}
-}
-
 var _ = errors.New // keep linter quiet if you remove errors in future
@@ -0,0 +1,355 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// totalCategory is the capacity_limits row holding the overall limit.
+const totalCategory = "*"
+
+type WaitlistRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWaitlistRepo(db *pgxpool.Pool) *WaitlistRepo {
+	return &WaitlistRepo{db: db}
+}
+
+func (r *WaitlistRepo) Limits(ctx context.Context) (domain.CapacityLimits, error) {
+	return loadLimits(ctx, r.db)
+}
+
+func (r *WaitlistRepo) SetLimits(ctx context.Context, l domain.CapacityLimits) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM capacity_limits`); err != nil {
+			return err
+		}
+		if l.Total != nil {
+			if _, err := tx.Exec(ctx, `INSERT INTO capacity_limits (category, capacity) VALUES ($1,$2)`, totalCategory, *l.Total); err != nil {
+				return err
+			}
+		}
+		for cat, n := range l.Categories {
+			if _, err := tx.Exec(ctx, `INSERT INTO capacity_limits (category, capacity) VALUES ($1,$2)`, cat, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *WaitlistRepo) Admit(ctx context.Context, userID uuid.UUID, categoryField string) (domain.UserStatus, int, error) {
+	status := domain.StatusApproved
+	position := 0
+	err := r.locked(ctx, func(tx pgx.Tx) error {
+		limits, err := loadLimits(ctx, tx)
+		if err != nil {
+			return err
+		}
+		cat, err := userCategory(ctx, tx, userID, categoryField)
+		if err != nil {
+			return err
+		}
+		room, err := hasRoom(ctx, tx, limits, userID, categoryField, cat)
+		if err != nil {
+			return err
+		}
+		if room {
+			return approve(ctx, tx, userID)
+		}
+		status = domain.StatusWaitlisted
+		position, err = enqueue(ctx, tx, userID)
+		return err
+	})
+	return status, position, err
+}
+
+func (r *WaitlistRepo) Enqueue(ctx context.Context, userID uuid.UUID) (int, error) {
+	position := 0
+	err := r.locked(ctx, func(tx pgx.Tx) error {
+		var err error
+		position, err = enqueue(ctx, tx, userID)
+		return err
+	})
+	return position, err
+}
+
+func (r *WaitlistRepo) PromoteNext(ctx context.Context, categoryField string) ([]uuid.UUID, error) {
+	promoted := []uuid.UUID{}
+	err := r.locked(ctx, func(tx pgx.Tx) error {
+		limits, err := loadLimits(ctx, tx)
+		if err != nil {
+			return err
+		}
+		entries, err := listEntries(ctx, tx, categoryField)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			room, err := hasRoom(ctx, tx, limits, e.UserID, categoryField, e.Category)
+			if err != nil {
+				return err
+			}
+			if !room {
+				continue
+			}
+			ok, err := promote(ctx, tx, e.UserID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				promoted = append(promoted, e.UserID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+func (r *WaitlistRepo) Promote(ctx context.Context, userID uuid.UUID) error {
+	return r.locked(ctx, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id=$1)`, userID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		ok, err := promote(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrInvalidState
+		}
+		return nil
+	})
+}
+
+func (r *WaitlistRepo) List(ctx context.Context, categoryField string) ([]domain.WaitlistEntry, error) {
+	return listEntries(ctx, r.db, categoryField)
+}
+
+// Move puts the user at the given 1-based position and renumbers the queue.
+func (r *WaitlistRepo) Move(ctx context.Context, userID uuid.UUID, position int) error {
+	return r.locked(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `SELECT user_id FROM waitlist_entries ORDER BY position`)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return err
+		}
+		order := make([]uuid.UUID, 0, len(ids))
+		found := false
+		for _, id := range ids {
+			if id == userID {
+				found = true
+				continue
+			}
+			order = append(order, id)
+		}
+		if !found {
+			return domain.ErrNotFound
+		}
+		if position < 1 {
+			position = 1
+		}
+		if position > len(order)+1 {
+			position = len(order) + 1
+		}
+		order = append(order[:position-1], append([]uuid.UUID{userID}, order[position-1:]...)...)
+		for i, id := range order {
+			if _, err := tx.Exec(ctx, `UPDATE waitlist_entries SET position=$2 WHERE user_id=$1`, id, i+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *WaitlistRepo) Leave(ctx context.Context, userID uuid.UUID, status domain.UserStatus) error {
+	if status != domain.StatusWaiting && status != domain.StatusRejected {
+		return domain.ErrInvalidInput
+	}
+	return r.locked(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE users SET status=$2, updated_at=now() WHERE id=$1`, userID, string(status)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM waitlist_entries WHERE user_id=$1`, userID)
+		return err
+	})
+}
+
+func (r *WaitlistRepo) Cancel(ctx context.Context, userID uuid.UUID, reason string, at time.Time) error {
+	return r.locked(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+UPDATE users SET status='CANCELLED', cancelled_at=$2, cancellation_reason=$3, updated_at=now()
+WHERE id=$1`, userID, at, reason); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM waitlist_entries WHERE user_id=$1`, userID)
+		return err
+	})
+}
+
+// locked runs fn in a transaction holding the capacity lock.
+func (r *WaitlistRepo) locked(ctx context.Context, fn func(pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockCapacity(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// lockCapacity serializes status changes that count against capacity until
+// the transaction ends.
+func lockCapacity(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('confsite:capacity'))`)
+	return err
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func loadLimits(ctx context.Context, q querier) (domain.CapacityLimits, error) {
+	l := domain.CapacityLimits{Categories: map[string]int{}}
+	rows, err := q.Query(ctx, `SELECT category, capacity FROM capacity_limits`)
+	if err != nil {
+		return l, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cat string
+		var n int
+		if err := rows.Scan(&cat, &n); err != nil {
+			return l, err
+		}
+		if cat == totalCategory {
+			total := n
+			l.Total = &total
+			continue
+		}
+		l.Categories[cat] = n
+	}
+	return l, rows.Err()
+}
+
+func userCategory(ctx context.Context, q querier, userID uuid.UUID, categoryField string) (string, error) {
+	if categoryField == "" {
+		return "", nil
+	}
+	var cat string
+	err := q.QueryRow(ctx, `SELECT COALESCE(answers->>$2, '') FROM registration_answers WHERE user_id=$1`, userID, categoryField).Scan(&cat)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return cat, err
+}
+
+// hasRoom reports whether one more approval (not counting the user) fits both
+// the overall limit and the limit of the user's category.
+func hasRoom(ctx context.Context, q querier, l domain.CapacityLimits, userID uuid.UUID, categoryField, category string) (bool, error) {
+	if l.Total != nil {
+		var n int
+		if err := q.QueryRow(ctx, `SELECT count(*) FROM users WHERE status='APPROVED' AND disabled_at IS NULL AND id<>$1`, userID).Scan(&n); err != nil {
+			return false, err
+		}
+		if n >= *l.Total {
+			return false, nil
+		}
+	}
+	limit, ok := l.Categories[category]
+	if categoryField == "" || !ok {
+		return true, nil
+	}
+	var n int
+	err := q.QueryRow(ctx, `
+SELECT count(*)
+FROM users u
+LEFT JOIN registration_answers ra ON ra.user_id = u.id
+WHERE u.status='APPROVED' AND u.disabled_at IS NULL AND u.id<>$1 AND COALESCE(ra.answers->>$2, '')=$3`, userID, categoryField, category).Scan(&n)
+	return n < limit, err
+}
+
+func approve(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `UPDATE users SET status='APPROVED', updated_at=now() WHERE id=$1`, userID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `DELETE FROM waitlist_entries WHERE user_id=$1`, userID)
+	return err
+}
+
+// promote approves a user who is still WAITLISTED; false means the user left
+// the waitlist in the meantime and nothing changed.
+func promote(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (bool, error) {
+	tag, err := tx.Exec(ctx, `UPDATE users u SET status='APPROVED', updated_at=now() WHERE u.id=$1 AND u.status='WAITLISTED'`, userID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM waitlist_entries WHERE user_id=$1`, userID); err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// enqueue appends the user to the waitlist (keeping an existing place) and
+// returns the 1-based position.
+func enqueue(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (int, error) {
+	if _, err := tx.Exec(ctx, `UPDATE users SET status='WAITLISTED', updated_at=now() WHERE id=$1`, userID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO waitlist_entries (user_id, position)
+VALUES ($1, COALESCE((SELECT max(position) FROM waitlist_entries), 0) + 1)
+ON CONFLICT (user_id) DO NOTHING`, userID); err != nil {
+		return 0, err
+	}
+	var pos int
+	err := tx.QueryRow(ctx, `
+SELECT count(*) FROM waitlist_entries
+WHERE position <= (SELECT position FROM waitlist_entries WHERE user_id=$1)`, userID).Scan(&pos)
+	return pos, err
+}
+
+func listEntries(ctx context.Context, q querier, categoryField string) ([]domain.WaitlistEntry, error) {
+	rows, err := q.Query(ctx, `
+SELECT w.user_id, u.email,
+  COALESCE(p.surname || ' ' || p.name || ' ' || p.patronymic, ''),
+  CASE WHEN $1 = '' THEN '' ELSE COALESCE(ra.answers->>$1, '') END,
+  w.queued_at
+FROM waitlist_entries w
+JOIN users u ON u.id = w.user_id
+LEFT JOIN profiles p ON p.user_id = w.user_id
+LEFT JOIN registration_answers ra ON ra.user_id = w.user_id
+WHERE u.status='WAITLISTED'
+ORDER BY w.position`, categoryField)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.WaitlistEntry{}
+	for rows.Next() {
+		e := domain.WaitlistEntry{Position: len(out) + 1}
+		if err := rows.Scan(&e.UserID, &e.Email, &e.FullName, &e.Category, &e.QueuedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
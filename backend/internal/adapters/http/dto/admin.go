@@ -1,7 +1,53 @@
 package dto
 
+import (
+	"time"
+
+	"confsite/backend/internal/domain"
+)
+
 type SetUserStatusRequest struct {
-	Status string `json:"status" binding:"required"` // WAITING/APPROVED/REJECTED
+	Status string `json:"status" binding:"required"` // WAITING/APPROVED/REJECTED/WAITLISTED
+}
+
+// MergeUsersRequest folds SourceID into TargetID. ProfileFromSource names the
+// profile fields (surname, name, patronymic, birthDate, city, academicDegree,
+// affiliation, position, phone, postalAddress, consentDataProcessing,
+// consentDataTransfer) taken from the source; the rest keep the target's value.
+type MergeUsersRequest struct {
+	SourceID          string   `json:"sourceId" binding:"required"`
+	TargetID          string   `json:"targetId" binding:"required"`
+	ProfileFromSource []string `json:"profileFromSource"`
+}
+
+type CapacityRequest struct {
+	Total      *int           `json:"total"`      // null = unlimited
+	Categories map[string]int `json:"categories"` // category value -> limit
+}
+
+type MoveWaitlistRequest struct {
+	Position int `json:"position" binding:"required,min=1"`
+}
+
+// BulkStatusRequest applies one status to the listed ids, or to every row
+// matching the filter when ids is empty.
+type BulkStatusRequest struct {
+	Status string            `json:"status" binding:"required"`
+	IDs    []string          `json:"ids"`
+	Filter BulkFilterRequest `json:"filter"`
+}
+
+type BulkFilterRequest struct {
+	Status           *string `json:"status"` // current status
+	SectionID        *string `json:"sectionId"`
+	Kind             *string `json:"kind"`
+	EmailVerified    *bool   `json:"emailVerified"`
+	ConsentsUploaded *bool   `json:"consentsUploaded"`
+}
+
+type DismissDuplicateRequest struct {
+	TalkA string `json:"talkA" binding:"required"`
+	TalkB string `json:"talkB" binding:"required"`
 }
 
 type SetTalkStatusRequest struct {
@@ -18,12 +64,24 @@ type SetSectionResponsiblesRequest struct {
 	Emails []string `json:"emails"`
 }
 
+// ResponsibleModeRequest: mode is IMMEDIATE or DIGEST.
+type ResponsibleModeRequest struct {
+	Email string `json:"email" binding:"required"`
+	Mode  string `json:"mode" binding:"required"`
+}
+
+// NewsUpsertRequest: status defaults to PUBLISHED; SCHEDULED needs publishAt.
+// notifySubscribers mails the item to subscribers when it is published.
 type NewsUpsertRequest struct {
-	TitleRu string `json:"titleRu" binding:"required"`
-	BodyRu  string `json:"bodyRu" binding:"required"`
-	TitleEn string `json:"titleEn" binding:"required"`
-	BodyEn  string `json:"bodyEn" binding:"required"`
-	Pinned  bool   `json:"pinned"`
+	TitleRu           string     `json:"titleRu" binding:"required"`
+	BodyRu            string     `json:"bodyRu" binding:"required"`
+	TitleEn           string     `json:"titleEn" binding:"required"`
+	BodyEn            string     `json:"bodyEn" binding:"required"`
+	Pinned            bool       `json:"pinned"`
+	Status            string     `json:"status" binding:"omitempty,oneof=DRAFT SCHEDULED PUBLISHED ARCHIVED"`
+	PublishAt         *time.Time `json:"publishAt"`
+	ExpiresAt         *time.Time `json:"expiresAt"`
+	NotifySubscribers bool       `json:"notifySubscribers"`
 }
 
 type PageUpsertRequest struct {
@@ -32,3 +90,47 @@ type PageUpsertRequest struct {
 	TitleEn string `json:"titleEn" binding:"required"`
 	BodyEn  string `json:"bodyEn" binding:"required"`
 }
+
+// EmailTemplateRequest holds Go template sources: text/template for the
+// subject and text, html/template for the HTML part.
+type EmailTemplateRequest struct {
+	Subject string `json:"subject" binding:"required"`
+	HTML    string `json:"html" binding:"required"`
+	Text    string `json:"text" binding:"required"`
+}
+
+type EmailTemplateRollbackRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// CampaignRequest holds text/template sources; bodies are plain text with
+// paragraphs separated by blank lines.
+type CampaignRequest struct {
+	Name      string                 `json:"name" binding:"required"`
+	Segment   domain.CampaignSegment `json:"segment"`
+	SubjectRu string                 `json:"subjectRu" binding:"required"`
+	BodyRu    string                 `json:"bodyRu" binding:"required"`
+	SubjectEn string                 `json:"subjectEn" binding:"required"`
+	BodyEn    string                 `json:"bodyEn" binding:"required"`
+}
+
+// CampaignTestRequest: an empty lang sends both language versions.
+type CampaignTestRequest struct {
+	Lang string `json:"lang"`
+}
+
+// BounceEvent is one delivery report posted to the bounce webhook. Type is
+// "bounce" or "complaint"; BounceType is "hard"/"permanent" or "soft"/"transient".
+type BounceEvent struct {
+	Email      string `json:"email"`
+	Type       string `json:"type"`
+	BounceType string `json:"bounceType"`
+	Reason     string `json:"reason"`
+}
+
+// TranslationRequest: Body is the page/news body or the material
+// description and is ignored for sections.
+type TranslationRequest struct {
+	Title string `json:"title" binding:"required"`
+	Body  string `json:"body"`
+}
@@ -10,9 +10,52 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PreferredLangRequest sets the language of the user's emails ("ru" or "en").
+type PreferredLangRequest struct {
+	Lang string `json:"lang" binding:"required"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"newEmail" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 type MeResponse struct {
 	ID     string   `json:"id"`
 	Email  string   `json:"email"`
 	Status string   `json:"status"`
 	Roles  []string `json:"roles"`
+	// ImpersonatedBy is the admin's user id while an impersonation session is active.
+	ImpersonatedBy *string `json:"impersonatedBy,omitempty"`
+	// EmailUndeliverable is set after a hard bounce until the email is changed.
+	EmailUndeliverable bool `json:"emailUndeliverable"`
+	// PreferredLang is the language of the user's emails; empty until known.
+	PreferredLang string `json:"preferredLang,omitempty"`
+}
+
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays int      `json:"expiresInDays"` // default 90, max 365
+}
+
+type APITokenResponse struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"userId"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expiresAt"`
+	LastUsedAt *string  `json:"lastUsedAt"`
+	LastUsedIP *string  `json:"lastUsedIp"`
+	RevokedAt  *string  `json:"revokedAt"`
+	CreatedAt  string   `json:"createdAt"`
 }
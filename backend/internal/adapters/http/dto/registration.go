@@ -12,4 +12,50 @@ type RegistrationSubmitRequest struct {
 	Phone                string  `json:"phone" binding:"required"`
 	PostalAddress        string  `json:"postalAddress" binding:"required"`
 	ConsentDataProcessing bool    `json:"consentDataProcessing" binding:"required"`
-	ConsentDataTransfer   bool    `json:"consentDataTransfer" binding:"required"`}
\ No newline at end of file
+	ConsentDataTransfer   bool    `json:"consentDataTransfer" binding:"required"`
+	// Answers to the organizer-defined fields, keyed by field key.
+	Answers map[string]any `json:"answers"`
+}
+
+type RegistrationFieldOptionDTO struct {
+	Value   string `json:"value" binding:"required"`
+	LabelRu string `json:"labelRu" binding:"required"`
+	LabelEn string `json:"labelEn" binding:"required"`
+}
+
+type RegistrationFieldValidationDTO struct {
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	MinDate   string `json:"minDate,omitempty"`
+	MaxDate   string `json:"maxDate,omitempty"`
+}
+
+type RegistrationFieldUpsertRequest struct {
+	Key        string                         `json:"key" binding:"required"`
+	Type       string                         `json:"type" binding:"required"` // text/select/checkbox/date/file
+	LabelRu    string                         `json:"labelRu" binding:"required"`
+	LabelEn    string                         `json:"labelEn" binding:"required"`
+	HelpRu     *string                        `json:"helpRu"`
+	HelpEn     *string                        `json:"helpEn"`
+	Options    []RegistrationFieldOptionDTO   `json:"options"`
+	Required   bool                           `json:"required"`
+	Validation RegistrationFieldValidationDTO `json:"validation"`
+	SortOrder  int32                          `json:"sortOrder"`
+	Active     *bool                          `json:"active"` // defaults to true
+}
+
+type RegistrationFieldResponse struct {
+	ID         string                         `json:"id"`
+	Key        string                         `json:"key"`
+	Type       string                         `json:"type"`
+	LabelRu    string                         `json:"labelRu"`
+	LabelEn    string                         `json:"labelEn"`
+	HelpRu     *string                        `json:"helpRu"`
+	HelpEn     *string                        `json:"helpEn"`
+	Options    []RegistrationFieldOptionDTO   `json:"options"`
+	Required   bool                           `json:"required"`
+	Validation RegistrationFieldValidationDTO `json:"validation"`
+	SortOrder  int32                          `json:"sortOrder"`
+	Active     bool                           `json:"active"`
+}
\ No newline at end of file
@@ -11,6 +11,11 @@ type TalkUpsertRequest struct {
 	Authors     []TalkAuthorDTO `json:"authors" binding:"required"`
 }
 
+// ReasonRequest is the body of talk withdrawal and registration cancellation.
+type ReasonRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
 type AdminUpdateTalkRequest struct {
 	SectionID    *string `json:"sectionId"`
 	ScheduleTime *string `json:"scheduleTime"`
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"net/mail"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"confsite/backend/internal/adapters/http/dto"
 	"confsite/backend/internal/app/services"
 	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
 	"confsite/backend/internal/ports"
 
 	"github.com/gin-gonic/gin"
@@ -17,9 +19,34 @@ import (
 
 func AdminListUsers(s *services.AdminService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		rows, err := s.ListUsersDetailed(c)
+		p := listParams{c: c}
+		f := ports.UserListFilter{
+			ListQuery:          p.query(),
+			SectionID:          p.uuid("sectionId"),
+			EmailVerified:      p.bool("emailVerified"),
+			ConsentsUploaded:   p.bool("consentsUploaded"),
+			EmailUndeliverable: p.bool("emailUndeliverable"),
+			From:               p.time("from"),
+			To:                 p.time("to"),
+		}
+		if v := p.str("status"); v != nil {
+			st := domain.UserStatus(*v)
+			f.Status = &st
+		}
+		if v := p.str("role"); v != nil {
+			role := domain.Role(*v)
+			f.Role = &role
+		}
+		if v := p.str("kind"); v != nil {
+			kind := domain.TalkKind(*v)
+			f.Kind = &kind
+		}
+		if !p.ok() {
+			return
+		}
+		rows, total, err := s.ListUsersDetailed(c, f)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			writeListError(c, err)
 			return
 		}
 		out := make([]gin.H, 0, len(rows))
@@ -97,9 +124,15 @@ func AdminListUsers(s *services.AdminService) gin.HandlerFunc {
 				}(),
 				"consentDataProcessing": consentDataProcessing,
 				"consentDataTransfer":   consentDataTransfer,
+				"cancelledAt":           u.CancelledAt,
+				"cancellationReason":    u.CancellationReason,
+				"disabledAt":            u.DisabledAt,
+				"mergedInto":            u.MergedInto,
+				"emailUndeliverableAt":  u.EmailUndeliverableAt,
+				"emailBounceReason":     u.EmailBounceReason,
 			})
 		}
-		c.JSON(http.StatusOK, out)
+		writeListPage(c, out, total, f.ListQuery)
 	}
 }
 
@@ -111,11 +144,12 @@ func AdminSetUserStatus(s *services.AdminService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if err := s.SetUserStatus(c, id, domain.UserStatus(req.Status), ctxLang(c)); err != nil {
+		st, err := s.SetUserStatus(c, id, domain.UserStatus(req.Status), ctxLang(c))
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"ok": true, "status": req.Status})
+		c.JSON(http.StatusOK, gin.H{"ok": true, "status": st})
 	}
 }
 
@@ -147,7 +181,7 @@ func AdminSectionsCreate(sr ports.SectionRepo) gin.HandlerFunc {
 
 func AdminNewsList(ns *services.NewsService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		rows, err := ns.List(c)
+		rows, err := ns.ListAll(c)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
 			return
@@ -163,16 +197,12 @@ func AdminNewsCreate(ns *services.NewsService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		_, err := ns.Create(c, domain.News{
-			TitleRu: req.TitleRu, BodyRu: req.BodyRu,
-			TitleEn: req.TitleEn, BodyEn: req.BodyEn,
-			Pinned: req.Pinned,
-		})
+		id, err := ns.Create(c, c.MustGet(middleware.CtxUserIDKey).(uuid.UUID), newsFromRequest(req))
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "bad"})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"ok": true})
+		c.JSON(http.StatusOK, gin.H{"ok": true, "id": id})
 	}
 }
 
@@ -184,12 +214,13 @@ func AdminNewsUpdate(ns *services.NewsService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if err := ns.Update(c, domain.News{
-			ID:      id,
-			TitleRu: req.TitleRu, BodyRu: req.BodyRu,
-			TitleEn: req.TitleEn, BodyEn: req.BodyEn,
-			Pinned: req.Pinned,
-		}); err != nil {
+		n := newsFromRequest(req)
+		n.ID = id
+		if err := ns.Update(c, c.MustGet(middleware.CtxUserIDKey).(uuid.UUID), n); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": "bad"})
 			return
 		}
@@ -197,6 +228,53 @@ func AdminNewsUpdate(ns *services.NewsService) gin.HandlerFunc {
 	}
 }
 
+// AdminNewsPreview renders an item in any state the way the public API
+// would, in the request's language, with its lifecycle fields.
+func AdminNewsPreview(ns *services.NewsService, tr *services.TranslationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_id"})
+			return
+		}
+		n, err := ns.Preview(c, id)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		loc, err := tr.Resolve(c, domain.ContentNews, ctxContentLocale(c), newsItems([]domain.News{*n}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		out := newsJSON(*n, loc[n.ID.String()])
+		out["status"] = n.Status
+		out["publishAt"] = n.PublishAt
+		out["expiresAt"] = n.ExpiresAt
+		c.JSON(http.StatusOK, out)
+	}
+}
+
+func newsFromRequest(req dto.NewsUpsertRequest) domain.News {
+	status := domain.NewsStatus(req.Status)
+	if status == "" {
+		status = domain.NewsPublished
+	}
+	return domain.News{
+		TitleRu: req.TitleRu, BodyRu: req.BodyRu,
+		TitleEn: req.TitleEn, BodyEn: req.BodyEn,
+		Pinned:            req.Pinned,
+		Status:            status,
+		PublishAt:         req.PublishAt,
+		ExpiresAt:         req.ExpiresAt,
+		NotifySubscribers: req.NotifySubscribers,
+	}
+}
+
 func AdminNewsDelete(ns *services.NewsService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := uuid.MustParse(c.Param("id"))
@@ -241,14 +319,32 @@ func AdminPagesUpsert(ps *services.PageService) gin.HandlerFunc {
 
 func AdminTalksList(tr ports.TalkRepo) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		rows, err := tr.ListAdmin(c, nil, false)
+		p := listParams{c: c}
+		f := ports.TalkListFilter{
+			ListQuery:        p.query(),
+			SectionID:        p.uuid("sectionId"),
+			ConsentsUploaded: p.bool("consentsUploaded"),
+			From:             p.time("from"),
+			To:               p.time("to"),
+		}
+		if v := p.str("status"); v != nil {
+			st := domain.TalkStatus(*v)
+			f.Status = &st
+		}
+		if v := p.str("kind"); v != nil {
+			kind := domain.TalkKind(*v)
+			f.Kind = &kind
+		}
+		if !p.ok() {
+			return
+		}
+		rows, total, err := tr.ListAdminPage(c, f)
 		if err != nil {
 			println("AdminTalksList error:", err.Error())
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			writeListError(c, err)
 			return
 		}
-		println("AdminTalksList found", len(rows), "talks")
-		c.JSON(http.StatusOK, rows)
+		writeListPage(c, rows, total, f.ListQuery)
 	}
 }
 
@@ -333,8 +429,13 @@ func AdminListSectionResponsibles(sr ports.SectionRepo) gin.HandlerFunc {
 			return
 		}
 		bySection := make(map[uuid.UUID][]string)
+		modes := make(map[uuid.UUID]map[string]domain.NotifyMode)
 		for _, item := range items {
 			bySection[item.SectionID] = append(bySection[item.SectionID], item.Email)
+			if modes[item.SectionID] == nil {
+				modes[item.SectionID] = map[string]domain.NotifyMode{}
+			}
+			modes[item.SectionID][item.Email] = item.Mode
 		}
 
 		out := make([]gin.H, 0, len(sections))
@@ -343,11 +444,16 @@ func AdminListSectionResponsibles(sr ports.SectionRepo) gin.HandlerFunc {
 			if emails == nil {
 				emails = []string{}
 			}
+			sectionModes := modes[s.ID]
+			if sectionModes == nil {
+				sectionModes = map[string]domain.NotifyMode{}
+			}
 			out = append(out, gin.H{
 				"sectionId":      s.ID,
 				"sectionTitleRu": s.TitleRu,
 				"sectionTitleEn": s.TitleEn,
 				"emails":         emails,
+				"modes":          sectionModes,
 			})
 		}
 		c.JSON(http.StatusOK, out)
@@ -393,14 +499,51 @@ func AdminSetSectionResponsibles(sr ports.SectionRepo) gin.HandlerFunc {
 	}
 }
 
+// AdminSetResponsibleMode chooses immediate emails or the daily digest for a
+// responsible address across all its sections.
+func AdminSetResponsibleMode(s *services.DigestService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.ResponsibleModeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.SetMode(c, req.Email, domain.NotifyMode(req.Mode)); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrInvalidInput):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_mode"})
+			case errors.Is(err, domain.ErrNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
 func AdminAuditList(ar ports.AuditRepo) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		rows, err := ar.List(c, 200)
+		p := listParams{c: c}
+		f := ports.AuditListFilter{
+			ListQuery:   p.query(),
+			Action:      p.str("action"),
+			Entity:      p.str("entity"),
+			ActorUserID: p.uuid("actorId"),
+			EntityID:    p.uuid("entityId"),
+			From:        p.time("from"),
+			To:          p.time("to"),
+		}
+		if !p.ok() {
+			return
+		}
+		rows, total, err := ar.List(c, f)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			writeListError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, rows)
+		writeListPage(c, rows, total, f.ListQuery)
 	}
 }
 
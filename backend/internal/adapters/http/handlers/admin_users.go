@@ -2,12 +2,15 @@ package http
 
 import (
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"net/http"
+	"time"
 
 	"confsite/backend/internal/app/services"
 	"confsite/backend/internal/domain"
 	"confsite/backend/internal/lib/auth"
+	"confsite/backend/internal/middleware"
 	"confsite/backend/internal/ports"
 
 	"github.com/gin-gonic/gin"
@@ -17,11 +20,31 @@ import (
 func ApproveUserHandler(s *services.AdminService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := uuid.MustParse(c.Param("id"))
-		if err := s.SetUserStatus(c, id, domain.StatusApproved, ctxLang(c)); err != nil {
+		st, err := s.SetUserStatus(c, id, domain.StatusApproved, ctxLang(c))
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"status": "APPROVED"})
+		c.JSON(http.StatusOK, gin.H{"status": st})
+	}
+}
+
+func AdminUnlockUser(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		if err := s.UnlockAccount(c, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
 	}
 }
 
@@ -71,3 +94,37 @@ func generateRandomPassword(length int) string {
 	}
 	return string(b)
 }
+
+// AdminImpersonate swaps the admin's access cookie for a time-limited token of
+// the target user. The refresh cookie still belongs to the admin.
+func AdminImpersonate(s *services.AuthService, cfg services.AppConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		actorID := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		sess, err := s.Impersonate(c, actorID, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			case errors.Is(err, domain.ErrForbidden), errors.Is(err, domain.ErrInvalidInput):
+				c.JSON(http.StatusForbidden, gin.H{"error": "cannot_impersonate"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			}
+			return
+		}
+		maxAge := int(time.Until(sess.AccessExp).Seconds())
+		c.SetCookie("access_token", sess.AccessToken, maxAge, "/", cfg.CookieDomain, cfg.CookieSecure, true)
+		c.JSON(http.StatusOK, gin.H{
+			"ok":        true,
+			"userId":    sess.UserID,
+			"email":     sess.Email,
+			"roles":     sess.Roles,
+			"expiresAt": sess.AccessExp,
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func ListMyAPITokens(s *services.APITokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		items, err := s.List(c, uid)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"items": apiTokensResponse(items), "scopes": domain.APIScopes})
+	}
+}
+
+func CreateAPIToken(s *services.APITokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.CreateAPITokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		raw, t, err := s.Create(c, uid, req.Name, req.Scopes, time.Duration(req.ExpiresInDays)*24*time.Hour)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidInput) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_token_request"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		// The raw token is only ever shown in this response.
+		c.JSON(http.StatusCreated, gin.H{"token": raw, "item": apiTokenResponse(*t)})
+	}
+}
+
+func RevokeMyAPIToken(s *services.APITokenService) gin.HandlerFunc {
+	return revokeAPIToken(s, false)
+}
+
+func AdminListAPITokens(s *services.APITokenService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.ListAll(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"items": apiTokensResponse(items)})
+	}
+}
+
+func AdminRevokeAPIToken(s *services.APITokenService) gin.HandlerFunc {
+	return revokeAPIToken(s, true)
+}
+
+func revokeAPIToken(s *services.APITokenService, asAdmin bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token id"})
+			return
+		}
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		if err := s.Revoke(c, uid, id, asAdmin); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func apiTokensResponse(items []domain.APIToken) []dto.APITokenResponse {
+	out := make([]dto.APITokenResponse, 0, len(items))
+	for _, t := range items {
+		out = append(out, apiTokenResponse(t))
+	}
+	return out
+}
+
+func apiTokenResponse(t domain.APIToken) dto.APITokenResponse {
+	return dto.APITokenResponse{
+		ID:         t.ID.String(),
+		UserID:     t.UserID.String(),
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.Scopes,
+		ExpiresAt:  t.ExpiresAt.Format(time.RFC3339),
+		LastUsedAt: formatTimePtr(t.LastUsedAt),
+		LastUsedIP: t.LastUsedIP,
+		RevokedAt:  formatTimePtr(t.RevokedAt),
+		CreatedAt:  t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
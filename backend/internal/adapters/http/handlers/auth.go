@@ -1,14 +1,20 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"confsite/backend/internal/adapters/http/dto"
 	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/lib/auth"
+	"confsite/backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func Register(s *services.AuthService) gin.HandlerFunc {
@@ -38,18 +44,30 @@ func Login(s *services.AuthService, cfg services.AppConfig) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		issued, err := s.Login(c, req.Email, req.Password, ctxLang(c))
+		issued, err := s.Login(c, req.Email, req.Password, ctxLang(c), ctxClient(c))
 		if err != nil {
+			var te *services.LoginThrottledError
+			if errors.As(err, &te) {
+				retry := int(te.RetryAfter.Seconds() + 0.999)
+				c.Header("Retry-After", strconv.Itoa(retry))
+				code := "too_many_attempts"
+				if te.Locked {
+					code = "account_locked"
+				}
+				c.JSON(http.StatusTooManyRequests, gin.H{"error": code, "retryAfter": retry})
+				return
+			}
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 		setAuthCookies(c, issued, cfg)
 		c.JSON(http.StatusOK, gin.H{
-			"ok":     true,
-			"userId": issued.UserID,
-			"roles":  issued.Roles,
-			"status": issued.Status,
-			"email":  issued.Email,
+			"ok":                 true,
+			"userId":             issued.UserID,
+			"roles":              issued.Roles,
+			"status":             issued.Status,
+			"email":              issued.Email,
+			"emailUndeliverable": issued.EmailUndeliverable,
 		})
 	}
 }
@@ -95,6 +113,77 @@ func VerifyEmail(s *services.AuthService) gin.HandlerFunc {
 	}
 }
 
+func OIDCProviders(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.OIDCProviders())
+	}
+}
+
+// oidcStateCookie binds an SSO login attempt to the browser that started it.
+const oidcStateCookie = "oidc_state"
+
+func OIDCStart(s *services.AuthService, cfg services.AppConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authURL, state, err := s.StartOIDC(c, c.Param("provider"), c.Query("next"))
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "unknown provider"})
+				return
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": "provider unavailable"})
+			return
+		}
+		// Lax so the cookie survives the top-level redirect back from the provider.
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(oidcStateCookie, state, int(services.OIDCStateTTL.Seconds()), oidcCookiePath(c), cfg.CookieDomain, cfg.CookieSecure, true)
+		c.Redirect(http.StatusFound, authURL)
+	}
+}
+
+// OIDCCallback is hit by the browser coming back from the identity provider;
+// it always answers with a redirect to the frontend.
+func OIDCCallback(s *services.AuthService, cfg services.AppConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fail := func(code string) {
+			c.Redirect(http.StatusFound, strings.TrimRight(cfg.AppURL, "/")+"/login?sso_error="+code)
+		}
+		browserState, _ := c.Cookie(oidcStateCookie)
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(oidcStateCookie, "", -1, oidcCookiePath(c), cfg.CookieDomain, cfg.CookieSecure, true)
+		if c.Query("error") != "" || c.Query("code") == "" || c.Query("state") == "" {
+			fail("denied")
+			return
+		}
+		issued, next, err := s.CompleteOIDC(c, c.Param("provider"), c.Query("code"), c.Query("state"), browserState, ctxClient(c), ctxLang(c))
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrEmailNotVerified):
+				fail("email_not_verified")
+			case errors.Is(err, domain.ErrNotFound):
+				fail("unknown_provider")
+			default:
+				fail("failed")
+			}
+			return
+		}
+		setAuthCookies(c, issued, cfg)
+		if next == "" {
+			next = "/cabinet"
+		}
+		c.Redirect(http.StatusFound, strings.TrimRight(cfg.AppURL, "/")+next)
+	}
+}
+
+// oidcCookiePath scopes the state cookie to the provider's start/callback
+// routes, i.e. the request path without its last segment.
+func oidcCookiePath(c *gin.Context) string {
+	p := c.Request.URL.Path
+	if i := strings.LastIndex(p, "/"); i > 0 {
+		return p[:i]
+	}
+	return "/"
+}
+
 func setAuthCookies(c *gin.Context, issued *services.IssuedTokens, cfg services.AppConfig) {
 	accessAge := int(time.Until(issued.AccessExp).Seconds())
 	if accessAge < 0 {
@@ -112,3 +201,136 @@ func clearAuthCookies(c *gin.Context, cfg services.AppConfig) {
 	c.SetCookie("access_token", "", -1, "/", cfg.CookieDomain, cfg.CookieSecure, true)
 	c.SetCookie("refresh_token", "", -1, "/", cfg.CookieDomain, cfg.CookieSecure, true)
 }
+
+// JWKS publishes the public keys that verify access tokens.
+func JWKS(keys *auth.KeySet) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, keys.JWKS())
+	}
+}
+
+// StopImpersonation ends an impersonation session and restores the admin's own
+// session from the refresh cookie.
+func StopImpersonation(s *services.AuthService, cfg services.AppConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := c.Get(middleware.CtxActorIDKey)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "not_impersonating"})
+			return
+		}
+		s.StopImpersonation(c, actor.(uuid.UUID), c.MustGet(middleware.CtxUserIDKey).(uuid.UUID))
+
+		refresh, _ := c.Cookie("refresh_token")
+		issued, err := s.Refresh(c, refresh)
+		if err != nil {
+			clearAuthCookies(c, cfg)
+			c.JSON(http.StatusOK, gin.H{"ok": true, "loggedOut": true})
+			return
+		}
+		setAuthCookies(c, issued, cfg)
+		c.JSON(http.StatusOK, gin.H{"ok": true, "userId": issued.UserID, "roles": issued.Roles})
+	}
+}
+
+func RequestEmailChange(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.ChangeEmailRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		if err := s.RequestEmailChange(c, uid, req.Password, req.NewEmail, ctxLang(c)); err != nil {
+			writeEmailChangeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+// SetPreferredLang stores the language the user wants emails in.
+func SetPreferredLang(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.PreferredLangRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		if err := s.SetPreferredLang(c, uid, strings.ToLower(strings.TrimSpace(req.Lang))); err != nil {
+			if errors.Is(err, domain.ErrInvalidInput) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_lang"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func ConfirmEmailChange(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.TokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.ConfirmEmailChange(c, req.Token); err != nil {
+			writeEmailChangeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func CancelEmailChange(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.TokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.CancelEmailChange(c, req.Token); err != nil {
+			writeEmailChangeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func writeEmailChangeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "email_already_exists"})
+	case errors.Is(err, domain.ErrUnauthorized):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_password"})
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_or_expired_token"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
+
+func ResendVerification(s *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.ResendVerificationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.ResendVerification(c, req.Email, ctxLang(c)); err != nil {
+			var te *services.VerificationThrottledError
+			if errors.As(err, &te) {
+				retry := int(te.RetryAfter.Seconds() + 0.999)
+				c.Header("Retry-After", strconv.Itoa(retry))
+				c.JSON(http.StatusTooManyRequests, gin.H{"error": "too_many_attempts", "retryAfter": retry})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MailBounceWebhook accepts one event or an array of events from the mail
+// provider. The X-Webhook-Secret header must match BOUNCE_WEBHOOK_SECRET; the
+// endpoint is disabled while the secret is unset.
+func MailBounceWebhook(s *services.BounceService, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Webhook-Secret")), []byte(secret)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		raw, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		var events []dto.BounceEvent
+		if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '[' {
+			err = json.Unmarshal(raw, &events)
+		} else {
+			var ev dto.BounceEvent
+			err = json.Unmarshal(raw, &ev)
+			events = []dto.BounceEvent{ev}
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		recorded := 0
+		for _, ev := range events {
+			kind, ok := bounceKind(ev)
+			if !ok {
+				continue
+			}
+			err := s.Process(c, domain.MailBounce{Email: ev.Email, Kind: kind, Reason: ev.Reason, Source: "webhook"})
+			if errors.Is(err, domain.ErrInvalidInput) {
+				continue
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+				return
+			}
+			recorded++
+		}
+		c.JSON(http.StatusOK, gin.H{"recorded": recorded})
+	}
+}
+
+func bounceKind(ev dto.BounceEvent) (domain.BounceKind, bool) {
+	switch strings.ToLower(ev.Type) {
+	case "complaint":
+		return domain.BounceComplaint, true
+	case "bounce", "":
+		switch strings.ToLower(ev.BounceType) {
+		case "hard", "permanent":
+			return domain.BounceHard, true
+		case "soft", "transient":
+			return domain.BounceSoft, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func AdminBulkUserStatus(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		req, status, ok := bindBulkRequest(c)
+		if !ok {
+			return
+		}
+		results, replayed, err := s.BulkSetUserStatus(c, uid, req, domain.UserStatus(status))
+		writeBulkResults(c, status, results, replayed, err)
+	}
+}
+
+func AdminBulkTalkStatus(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		req, status, ok := bindBulkRequest(c)
+		if !ok {
+			return
+		}
+		results, replayed, err := s.BulkSetTalkStatus(c, uid, req, domain.TalkStatus(status))
+		writeBulkResults(c, status, results, replayed, err)
+	}
+}
+
+// bindBulkRequest reads the body and the Idempotency-Key header.
+func bindBulkRequest(c *gin.Context) (services.BulkRequest, string, bool) {
+	var body dto.BulkStatusRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return services.BulkRequest{}, "", false
+	}
+	req := services.BulkRequest{Key: c.GetHeader("Idempotency-Key")}
+	for _, raw := range body.IDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "id": raw})
+			return services.BulkRequest{}, "", false
+		}
+		req.IDs = append(req.IDs, id)
+	}
+	f := body.Filter
+	req.Filter = domain.BulkFilter{
+		Status:           f.Status,
+		EmailVerified:    f.EmailVerified,
+		ConsentsUploaded: f.ConsentsUploaded,
+	}
+	if f.SectionID != nil {
+		sid, err := uuid.Parse(*f.SectionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid section id"})
+			return services.BulkRequest{}, "", false
+		}
+		req.Filter.SectionID = &sid
+	}
+	if f.Kind != nil {
+		kind := domain.TalkKind(*f.Kind)
+		req.Filter.Kind = &kind
+	}
+	return req, body.Status, true
+}
+
+func writeBulkResults(c *gin.Context, status string, results []domain.BulkItemResult, replayed bool, err error) {
+	if err != nil {
+		if errors.Is(err, domain.ErrKeyReused) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "idempotency_key_reused"})
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_bulk_request"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+		return
+	}
+	counts := map[string]int{}
+	for _, res := range results {
+		counts[res.Result]++
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":   status,
+		"replayed": replayed,
+		"counts":   counts,
+		"results":  results,
+	})
+}
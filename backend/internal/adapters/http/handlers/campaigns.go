@@ -0,0 +1,220 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func AdminListCampaigns(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.List(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func AdminCreateCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		var req dto.CampaignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := s.Create(c, uid, campaignFromRequest(req))
+		if err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": id})
+	}
+}
+
+func AdminGetCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		item, err := s.Get(c, id)
+		if err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+// AdminUpdateCampaign edits a draft; started campaigns are read-only (409).
+func AdminUpdateCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		var req dto.CampaignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		item := campaignFromRequest(req)
+		item.ID = id
+		if err := s.Update(c, item); err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func AdminDeleteCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		if err := s.Delete(c, id); err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+// AdminCampaignRecipients previews the segment of a draft, or lists delivery
+// status (?status=PENDING|SENT|FAILED|SKIPPED) once the campaign is started.
+func AdminCampaignRecipients(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		items, err := s.Recipients(c, id, c.Query("status"))
+		if err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": len(items), "items": items})
+	}
+}
+
+// AdminTestCampaign sends the campaign to the calling admin only.
+func AdminTestCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		var req dto.CampaignTestRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if err := s.SendTest(c, uid, id, req.Lang); err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+// AdminStartCampaign freezes the recipient list and queues delivery.
+func AdminStartCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		n, err := s.Start(c, uid, id)
+		if err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "recipients": n})
+	}
+}
+
+func AdminCancelCampaign(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := campaignID(c)
+		if !ok {
+			return
+		}
+		if err := s.Cancel(c, id); err != nil {
+			writeCampaignError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+// PublicUnsubscribe handles the link in the campaign email footer.
+func PublicUnsubscribe(s *services.CampaignService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.TokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.Unsubscribe(c, req.Token); err != nil {
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidInput) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_token"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func campaignID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func campaignFromRequest(req dto.CampaignRequest) domain.Campaign {
+	return domain.Campaign{
+		Name:      req.Name,
+		Segment:   req.Segment,
+		SubjectRu: req.SubjectRu,
+		BodyRu:    req.BodyRu,
+		SubjectEn: req.SubjectEn,
+		BodyEn:    req.BodyEn,
+	}
+}
+
+func writeCampaignError(c *gin.Context, err error) {
+	var tplErr *services.TemplateError
+	switch {
+	case errors.As(err, &tplErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_template", "detail": tplErr.Error()})
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_campaign"})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, domain.ErrInvalidState):
+		c.JSON(http.StatusConflict, gin.H{"error": "campaign_not_draft"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
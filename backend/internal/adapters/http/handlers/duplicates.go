@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
+	"confsite/backend/internal/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminDuplicatesReport lists suspected duplicate talks and accounts.
+// Dismissed talk pairs are hidden unless ?includeDismissed=true.
+func AdminDuplicatesReport(dr ports.DuplicateRepo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		includeDismissed := c.Query("includeDismissed") == "true"
+		pairs, err := dr.ListTalkPairs(c, includeDismissed)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		groups, err := dr.DuplicateUsers(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		talks := make([]gin.H, 0, len(pairs))
+		for _, p := range pairs {
+			talks = append(talks, gin.H{
+				"talkA":              duplicateTalkJSON(p.TalkA),
+				"talkB":              duplicateTalkJSON(p.TalkB),
+				"titleSimilarity":    p.TitleSimilarity,
+				"abstractSimilarity": p.AbstractSimilarity,
+				"flaggedAt":          p.FlaggedAt,
+				"dismissedAt":        p.DismissedAt,
+			})
+		}
+		users := make([]gin.H, 0, len(groups))
+		for _, g := range groups {
+			users = append(users, gin.H{
+				"fullName":  g.FullName,
+				"birthDate": g.BirthDate.Format("2006-01-02"),
+				"users":     g.Users,
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{"talks": talks, "users": users})
+	}
+}
+
+func duplicateTalkJSON(t domain.DuplicateTalkRef) gin.H {
+	return gin.H{
+		"id":              t.ID,
+		"title":           t.Title,
+		"status":          t.Status,
+		"speakerUserId":   t.SpeakerUserID,
+		"speakerEmail":    t.SpeakerEmail,
+		"speakerFullName": t.SpeakerFullName,
+	}
+}
+
+func AdminDismissDuplicateTalks(dr ports.DuplicateRepo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		var req dto.DismissDuplicateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		a, errA := uuid.Parse(req.TalkA)
+		b, errB := uuid.Parse(req.TalkB)
+		if errA != nil || errB != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid talk id"})
+			return
+		}
+		if err := dr.DismissTalkPair(c, a, b, uid); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
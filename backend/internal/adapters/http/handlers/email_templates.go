@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminListEmailTemplates returns every email with its documented variables
+// and the ru/en templates currently in use.
+func AdminListEmailTemplates(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.List(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func AdminGetEmailTemplate(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := s.Get(c, c.Param("name"), c.Param("lang"))
+		if err != nil {
+			writeEmailTemplateError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, t)
+	}
+}
+
+func AdminSaveEmailTemplate(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		var req dto.EmailTemplateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		version, err := s.Save(c, uid, domain.EmailTemplate{
+			Name:    c.Param("name"),
+			Lang:    c.Param("lang"),
+			Subject: req.Subject,
+			HTML:    req.HTML,
+			Text:    req.Text,
+		})
+		if err != nil {
+			writeEmailTemplateError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "version": version})
+	}
+}
+
+// AdminResetEmailTemplate switches back to the template file.
+func AdminResetEmailTemplate(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.Reset(c, c.Param("name"), c.Param("lang")); err != nil {
+			writeEmailTemplateError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+// AdminPreviewEmailTemplate renders the posted draft, or the template in use
+// when the body is empty, with sample data.
+func AdminPreviewEmailTemplate(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var draft *domain.EmailTemplate
+		if c.Request.ContentLength != 0 {
+			var req dto.EmailTemplateRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			draft = &domain.EmailTemplate{Subject: req.Subject, HTML: req.HTML, Text: req.Text}
+		}
+		subject, html, text, err := s.Preview(c, c.Param("name"), c.Param("lang"), draft)
+		if err != nil {
+			writeEmailTemplateError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"subject": subject, "html": html, "text": text})
+	}
+}
+
+func AdminEmailTemplateVersions(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.Versions(c, c.Param("name"), c.Param("lang"))
+		if err != nil {
+			writeEmailTemplateError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func AdminRollbackEmailTemplate(s *services.EmailTemplateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		var req dto.EmailTemplateRollbackRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		version, err := s.Rollback(c, uid, c.Param("name"), c.Param("lang"), req.Version)
+		if err != nil {
+			writeEmailTemplateError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "version": version})
+	}
+}
+
+func writeEmailTemplateError(c *gin.Context, err error) {
+	var tplErr *services.TemplateError
+	switch {
+	case errors.As(err, &tplErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_template", "detail": tplErr.Error()})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
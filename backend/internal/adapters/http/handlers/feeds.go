@@ -0,0 +1,344 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedSize is how many of the latest news items the feeds carry.
+const feedSize = 50
+
+// siteLangs are the languages of the frontend; each page is available in
+// both through ?lang=.
+var siteLangs = []string{"ru", "en"}
+
+var feedTitles = map[string]string{
+	"ru": "Новости конференции",
+	"en": "Conference news",
+}
+
+type atomLink struct {
+	Rel      string `xml:"rel,attr"`
+	Type     string `xml:"type,attr,omitempty"`
+	Hreflang string `xml:"hreflang,attr,omitempty"`
+	Href     string `xml:"href,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Lang      string     `xml:"xml:lang,attr,omitempty"`
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published"`
+	Links     []atomLink `xml:"link"`
+	Content   atomText   `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string      `xml:"xml:lang,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	Language      string     `xml:"language"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	AtomLinks     []atomLink `xml:"atom:link"`
+	Items         []rssItem  `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	XMLNSAtom string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+// feedItem is a news item resolved to the feed's locale.
+type feedItem struct {
+	news    domain.News
+	text    services.Localized
+	updated time.Time
+}
+
+// newsFeedItems returns the latest public news, newest first, with the text
+// in the requested locale and the last change of that text.
+func newsFeedItems(c *gin.Context, s *services.NewsService, tr *services.TranslationService) ([]feedItem, time.Time, error) {
+	rows, err := s.List(c)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].PublishedAt.After(*rows[j].PublishedAt) })
+	if len(rows) > feedSize {
+		rows = rows[:feedSize]
+	}
+	loc, err := tr.Resolve(c, domain.ContentNews, ctxContentLocale(c), newsItems(rows))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var last time.Time
+	items := make([]feedItem, 0, len(rows))
+	for _, n := range rows {
+		it := feedItem{news: n, text: loc[n.ID.String()], updated: n.UpdatedAt}
+		if n.PublishedAt.After(it.updated) {
+			it.updated = *n.PublishedAt
+		}
+		if it.text.UpdatedAt != nil && it.text.UpdatedAt.After(it.updated) {
+			it.updated = *it.text.UpdatedAt
+		}
+		if it.updated.After(last) {
+			last = it.updated
+		}
+		items = append(items, it)
+	}
+	return items, last, nil
+}
+
+func siteURL(appURL, path, lang string) string {
+	u := strings.TrimRight(appURL, "/") + path
+	if lang != "" {
+		u += "?lang=" + lang
+	}
+	return u
+}
+
+// langAlternates links the ru and en versions of a frontend page.
+func langAlternates(appURL, path, typ string) []atomLink {
+	out := make([]atomLink, 0, len(siteLangs))
+	for _, l := range siteLangs {
+		out = append(out, atomLink{Rel: "alternate", Type: typ, Hreflang: l, Href: siteURL(appURL, path, l)})
+	}
+	return out
+}
+
+// feedAlternates links the feed of the same format in the other site
+// languages.
+func feedAlternates(appURL, path, typ, lang string) []atomLink {
+	out := []atomLink{}
+	for _, l := range siteLangs {
+		if l != lang {
+			out = append(out, atomLink{Rel: "alternate", Type: typ, Hreflang: l, Href: siteURL(appURL, path, l)})
+		}
+	}
+	return out
+}
+
+// siteLang is the ?lang= for frontend links: the frontend itself speaks only
+// ru and en and picks its own language otherwise.
+func siteLang(lang string) string {
+	for _, l := range siteLangs {
+		if l == lang {
+			return l
+		}
+	}
+	return ""
+}
+
+func feedTitle(lang string) string {
+	if t, ok := feedTitles[lang]; ok {
+		return t
+	}
+	return feedTitles["en"]
+}
+
+// PublicNewsAtom serves the published news as an Atom feed in the locale of
+// ?lang= (or Accept-Language).
+func PublicNewsAtom(s *services.NewsService, tr *services.TranslationService, appURL string) gin.HandlerFunc {
+	const path = "/api/public/feeds/news.atom"
+	const typ = "application/atom+xml"
+	return func(c *gin.Context) {
+		items, last, err := newsFeedItems(c, s, tr)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		lang := ctxContentLocale(c)
+		self := siteURL(appURL, path, lang)
+		feed := atomFeed{
+			Lang:    lang,
+			ID:      self,
+			Title:   feedTitle(lang),
+			Updated: last.UTC().Format(time.RFC3339),
+			Links: append([]atomLink{
+				{Rel: "self", Type: typ, Href: self},
+				{Rel: "alternate", Type: "text/html", Href: siteURL(appURL, "/news", siteLang(lang))},
+			}, feedAlternates(appURL, path, typ, lang)...),
+		}
+		for _, it := range items {
+			e := atomEntry{
+				ID:        "urn:uuid:" + it.news.ID.String(),
+				Title:     it.text.Title,
+				Updated:   it.updated.UTC().Format(time.RFC3339),
+				Published: it.news.PublishedAt.UTC().Format(time.RFC3339),
+				Links:     langAlternates(appURL, "/news/"+it.news.ID.String(), "text/html"),
+				Content:   atomText{Type: "text", Text: it.text.Body},
+			}
+			if it.text.Locale != lang {
+				e.Lang = it.text.Locale
+			}
+			feed.Entries = append(feed.Entries, e)
+		}
+		writeXML(c, typ, feed, last)
+	}
+}
+
+// PublicNewsRSS serves the same items as PublicNewsAtom as RSS 2.0.
+func PublicNewsRSS(s *services.NewsService, tr *services.TranslationService, appURL string) gin.HandlerFunc {
+	const path = "/api/public/feeds/news.rss"
+	const typ = "application/rss+xml"
+	return func(c *gin.Context) {
+		items, last, err := newsFeedItems(c, s, tr)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		lang := ctxContentLocale(c)
+		ch := rssChannel{
+			Title:       feedTitle(lang),
+			Link:        siteURL(appURL, "/news", siteLang(lang)),
+			Description: feedTitle(lang),
+			Language:    lang,
+			AtomLinks: append([]atomLink{{Rel: "self", Type: typ, Href: siteURL(appURL, path, lang)}},
+				feedAlternates(appURL, path, typ, lang)...),
+			Items: []rssItem{},
+		}
+		if !last.IsZero() {
+			ch.LastBuildDate = last.UTC().Format(time.RFC1123Z)
+		}
+		for _, it := range items {
+			ch.Items = append(ch.Items, rssItem{
+				Title:       it.text.Title,
+				Link:        siteURL(appURL, "/news/"+it.news.ID.String(), siteLang(lang)),
+				GUID:        rssGUID{IsPermaLink: "false", Value: "urn:uuid:" + it.news.ID.String()},
+				PubDate:     it.news.PublishedAt.UTC().Format(time.RFC1123Z),
+				Description: it.text.Body,
+			})
+		}
+		writeXML(c, typ, rssFeed{Version: "2.0", XMLNSAtom: "http://www.w3.org/2005/Atom", Channel: ch}, last)
+	}
+}
+
+type sitemapLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type sitemapURL struct {
+	Loc     string        `xml:"loc"`
+	LastMod string        `xml:"lastmod,omitempty"`
+	Links   []sitemapLink `xml:"xhtml:link"`
+}
+
+type sitemapURLSet struct {
+	XMLName    xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	XMLNSXHTML string       `xml:"xmlns:xhtml,attr"`
+	URLs       []sitemapURL `xml:"url"`
+}
+
+// Sitemap lists every public page in ru and en; each version names the
+// other and the language-neutral URL as hreflang alternates.
+func Sitemap(s *services.SitemapService, appURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries, err := s.Entries(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		set := sitemapURLSet{XMLNSXHTML: "http://www.w3.org/1999/xhtml"}
+		var last time.Time
+		for _, e := range entries {
+			links := make([]sitemapLink, 0, len(siteLangs)+1)
+			for _, l := range siteLangs {
+				links = append(links, sitemapLink{Rel: "alternate", Hreflang: l, Href: siteURL(appURL, e.Path, l)})
+			}
+			links = append(links, sitemapLink{Rel: "alternate", Hreflang: "x-default", Href: siteURL(appURL, e.Path, "")})
+			lastmod := ""
+			if e.LastMod != nil {
+				lastmod = e.LastMod.UTC().Format(time.RFC3339)
+				if e.LastMod.After(last) {
+					last = *e.LastMod
+				}
+			}
+			for _, l := range siteLangs {
+				set.URLs = append(set.URLs, sitemapURL{Loc: siteURL(appURL, e.Path, l), LastMod: lastmod, Links: links})
+			}
+		}
+		writeXML(c, "application/xml", set, last)
+	}
+}
+
+// writeXML sends v with an ETag of the document and, when known, its
+// Last-Modified time, and answers 304 when the client's copy is current.
+// If-None-Match wins over If-Modified-Since.
+func writeXML(c *gin.Context, contentType string, v any, lastModified time.Time) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+		return
+	}
+	body = append([]byte(xml.Header), body...)
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	// Without ?lang the language comes from Accept-Language.
+	c.Header("Vary", "Accept-Language")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	// Only the ETag decides: Last-Modified does not move back when an item is
+	// unpublished or expires, so If-Modified-Since could serve a stale feed.
+	if inm := c.GetHeader("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", body)
+}
+
+// etagMatches applies the weak comparison of If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagMatches(t *testing.T) {
+	const etag = `"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{` "x" ,W/"abc" `, true},
+		{`*`, true},
+		{`"abcd"`, false},
+		{`abc`, false},
+		{`"x", "y"`, false},
+		{``, false},
+	}
+	for _, tc := range tests {
+		if got := etagMatches(tc.header, etag); got != tc.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
+
+type testDoc struct {
+	XMLName xml.Name `xml:"doc"`
+	Title   string   `xml:"title"`
+}
+
+func serveXML(doc testDoc, lastModified time.Time, header http.Header) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/feed", nil)
+	for k, v := range header {
+		c.Request.Header[k] = v
+	}
+	writeXML(c, "application/atom+xml", doc, lastModified)
+	// gin writes a bodiless status once the handler chain ends
+	c.Writer.WriteHeaderNow()
+	return rec
+}
+
+func TestWriteXML(t *testing.T) {
+	modified := time.Date(2026, 5, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	first := serveXML(testDoc{Title: "News"}, modified, nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status %d, etag %q", first.Code, etag)
+	}
+	if ct := first.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if lm := first.Header().Get("Last-Modified"); lm != "Fri, 01 May 2026 09:00:00 GMT" {
+		t.Errorf("Last-Modified = %q", lm)
+	}
+	if body := first.Body.String(); !strings.HasPrefix(body, xml.Header) || !strings.Contains(body, "<title>News</title>") {
+		t.Errorf("body = %q", body)
+	}
+
+	changedETag := serveXML(testDoc{Title: "Other news"}, modified, nil).Header().Get("ETag")
+	if changedETag == etag {
+		t.Error("a different document got the same ETag")
+	}
+	if got := serveXML(testDoc{Title: "News"}, time.Time{}, nil).Header().Get("Last-Modified"); got != "" {
+		t.Errorf("Last-Modified without a time = %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"matching etag", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"weak etag in a list", http.Header{"If-None-Match": {`"old", W/` + etag}}, http.StatusNotModified},
+		{"stale etag", http.Header{"If-None-Match": {changedETag}}, http.StatusOK},
+		// Last-Modified alone never answers 304: it does not move back when
+		// an item is unpublished
+		{"if-modified-since only", http.Header{"If-Modified-Since": {modified.Add(time.Hour).UTC().Format(http.TimeFormat)}}, http.StatusOK},
+		{"stale etag wins over if-modified-since", http.Header{
+			"If-None-Match":     {changedETag},
+			"If-Modified-Since": {modified.Add(time.Hour).UTC().Format(http.TimeFormat)},
+		}, http.StatusOK},
+	}
+	for _, tc := range tests {
+		rec := serveXML(testDoc{Title: "News"}, modified, tc.header)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, rec.Code, tc.want)
+		}
+		if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+			t.Errorf("%s: 304 with a body", tc.name)
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Errorf("%s: ETag = %q, want %q", tc.name, rec.Header().Get("ETag"), etag)
+		}
+	}
+}
@@ -2,12 +2,14 @@
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"path/filepath"
 	"strings"
 
 	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
 	"confsite/backend/internal/ports"
 
 	"confsite/backend/internal/lib/files"
@@ -116,6 +118,10 @@ func UploadTalkFile(st ports.Storage, talkSvc *services.TalkService) gin.Handler
 
 		// persist file URL and notify emails (org + user)
 		if err := talkSvc.SetFileURL(c, uid, tid, fileURL, ctxLang(c)); err != nil {
+			if errors.Is(err, domain.ErrInvalidState) {
+				c.JSON(http.StatusConflict, gin.H{"error": "talk_withdrawn"})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
 			return
 		}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"confsite/backend/internal/app/services"
 	"confsite/backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -13,3 +14,28 @@ func ctxLang(c *gin.Context) string {
 	}
 	return lang
 }
+
+// ctxContentLocale is the negotiated content locale, which may be any
+// enabled locale; ctxLang stays ru/en for UI strings and emails.
+func ctxContentLocale(c *gin.Context) string {
+	if l := c.GetString(middleware.CtxContentLocaleKey); l != "" {
+		return l
+	}
+	return ctxLang(c)
+}
+
+func ctxClient(c *gin.Context) services.ClientInfo {
+	if meta, ok := c.Get(middleware.CtxAuditMetaKey); ok {
+		if m, ok := meta.(middleware.AuditMeta); ok {
+			return services.ClientInfo{IP: m.IP, UserAgent: m.UserAgent}
+		}
+	}
+	return services.ClientInfo{IP: c.ClientIP(), UserAgent: c.GetHeader("User-Agent")}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
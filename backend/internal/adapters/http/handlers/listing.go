@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// listParams reads the query parameters shared by admin listings:
+// limit, offset, sort (key or -key) and q. Parse errors are collected so a
+// handler can report the first bad parameter once.
+type listParams struct {
+	c   *gin.Context
+	bad string
+}
+
+func (p *listParams) query() ports.ListQuery {
+	return ports.ListQuery{
+		Limit:  p.int("limit"),
+		Offset: p.int("offset"),
+		Sort:   p.c.Query("sort"),
+		Search: p.c.Query("q"),
+	}.Normalize()
+}
+
+func (p *listParams) int(name string) int {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		p.fail(name)
+	}
+	return n
+}
+
+func (p *listParams) str(name string) *string {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	return &raw
+}
+
+func (p *listParams) uuid(name string) *uuid.UUID {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		p.fail(name)
+		return nil
+	}
+	return &id
+}
+
+func (p *listParams) bool(name string) *bool {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fail(name)
+		return nil
+	}
+	return &b
+}
+
+// time accepts RFC 3339 or a plain YYYY-MM-DD date (midnight UTC).
+func (p *listParams) time(name string) *time.Time {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", raw)
+	}
+	if err != nil {
+		p.fail(name)
+		return nil
+	}
+	return &t
+}
+
+func (p *listParams) fail(name string) {
+	if p.bad == "" {
+		p.bad = name
+	}
+}
+
+// ok writes a 400 naming the first bad parameter and reports whether all were valid.
+func (p *listParams) ok() bool {
+	if p.bad != "" {
+		p.c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_query", "param": p.bad})
+		return false
+	}
+	return true
+}
+
+func writeListError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_query", "param": "sort"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+}
+
+func writeListPage(c *gin.Context, items any, total int, q ports.ListQuery) {
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": q.Limit, "offset": q.Offset})
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 
+	"confsite/backend/internal/app/services"
 	"confsite/backend/internal/domain"
 	"confsite/backend/internal/lib/files"
 	"confsite/backend/internal/middleware"
@@ -34,15 +35,33 @@ type MaterialResponse struct {
 	MimeType       *string   `json:"mimeType"`
 	CreatedAt      string    `json:"createdAt"`
 	UpdatedAt      string    `json:"updatedAt"`
+	// Title, Description and Locale are the best translation for the
+	// request's content locale; set on the public list only.
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Locale      string `json:"locale,omitempty"`
 }
 
-func PublicMaterialsList(repo ports.MaterialRepo) gin.HandlerFunc {
+func PublicMaterialsList(repo ports.MaterialRepo, tr *services.TranslationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ms, err := repo.List(c)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch materials"})
 			return
 		}
+		items := make([]services.TranslatableItem, 0, len(ms))
+		for _, m := range ms {
+			items = append(items, services.TranslatableItem{
+				Key:     m.ID.String(),
+				TitleRu: m.TitleRu, BodyRu: derefString(m.DescriptionRu),
+				TitleEn: m.TitleEn, BodyEn: derefString(m.DescriptionEn),
+			})
+		}
+		loc, err := tr.Resolve(c, domain.ContentMaterial, ctxContentLocale(c), items)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch materials"})
+			return
+		}
 
 		out := make([]MaterialResponse, 0, len(ms))
 		for _, m := range ms {
@@ -58,6 +77,9 @@ func PublicMaterialsList(repo ports.MaterialRepo) gin.HandlerFunc {
 				MimeType:       m.MimeType,
 				CreatedAt:      m.CreatedAt.String(),
 				UpdatedAt:      m.UpdatedAt.String(),
+				Title:          loc[m.ID.String()].Title,
+				Description:    loc[m.ID.String()].Body,
+				Locale:         loc[m.ID.String()].Locale,
 			})
 		}
 
@@ -23,11 +23,21 @@ func Me(users ports.UserRepo) gin.HandlerFunc {
 		for _, r := range roles {
 			rs = append(rs, string(r.Role))
 		}
-		c.JSON(http.StatusOK, dto.MeResponse{
+		resp := dto.MeResponse{
 			ID:     u.ID.String(),
 			Email:  u.Email,
 			Status: string(u.Status),
 			Roles:  rs,
-		})
+
+			EmailUndeliverable: u.EmailUndeliverableAt != nil,
+		}
+		if u.PreferredLang != nil {
+			resp.PreferredLang = *u.PreferredLang
+		}
+		if actor, ok := c.Get(middleware.CtxActorIDKey); ok {
+			by := actor.(uuid.UUID).String()
+			resp.ImpersonatedBy = &by
+		}
+		c.JSON(http.StatusOK, resp)
 	}
 }
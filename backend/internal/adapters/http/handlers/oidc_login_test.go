@@ -0,0 +1,359 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"confsite/backend/internal/adapters/oidc"
+	"confsite/backend/internal/adapters/oidc/oidcstub"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/lib/auth"
+	"confsite/backend/internal/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// startStubProvider runs the oidcstub provider for sso.user@example.org and
+// returns its issuer URL.
+func startStubProvider(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(nil)
+	issuer := "http://" + srv.Listener.Addr().String()
+	p, err := oidcstub.New(oidcstub.Config{Issuer: issuer, ClientID: "confsite", Email: "sso.user@example.org", Subject: "stub-user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Config.Handler = p.Handler()
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return issuer
+}
+
+// In-memory repositories; embedding the port interface leaves every method
+// the SSO flow does not use unimplemented.
+
+type memUsers struct {
+	ports.UserRepo
+	mu      sync.Mutex
+	byID    map[uuid.UUID]*domain.User
+	created int
+}
+
+func (m *memUsers) add(email string, verified bool) uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.New()
+	m.byID[id] = &domain.User{ID: id, Email: email, EmailVerified: verified, Status: domain.StatusWaiting}
+	return id
+}
+
+func (m *memUsers) Create(_ context.Context, email, _ string) (uuid.UUID, error) {
+	m.mu.Lock()
+	m.created++
+	m.mu.Unlock()
+	return m.add(email, false), nil
+}
+
+func (m *memUsers) ByEmail(_ context.Context, email string) (*domain.User, []domain.RoleAssignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.byID {
+		if u.Email == email {
+			cp := *u
+			return &cp, nil, nil
+		}
+	}
+	return nil, nil, domain.ErrNotFound
+}
+
+func (m *memUsers) ByID(_ context.Context, id uuid.UUID) (*domain.User, []domain.RoleAssignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.byID[id]
+	if !ok {
+		return nil, nil, domain.ErrNotFound
+	}
+	cp := *u
+	return &cp, nil, nil
+}
+
+func (m *memUsers) SetEmailVerified(_ context.Context, id uuid.UUID, verified bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byID[id].EmailVerified = verified
+	return nil
+}
+
+func (m *memUsers) AssignRole(context.Context, uuid.UUID, domain.Role, *uuid.UUID) error { return nil }
+
+func (m *memUsers) SetPreferredLang(context.Context, uuid.UUID, string) error { return nil }
+
+type memSessions struct{ ports.SessionRepo }
+
+func (memSessions) Create(context.Context, uuid.UUID, string, time.Time) error { return nil }
+
+type memDevices struct{ ports.UserDeviceRepo }
+
+func (memDevices) CountByUser(context.Context, uuid.UUID) (int64, error) { return 0, nil }
+
+func (memDevices) Touch(context.Context, uuid.UUID, string, string, string, time.Time) (bool, error) {
+	return true, nil
+}
+
+type memProfiles struct{ ports.ProfileRepo }
+
+func (memProfiles) Upsert(context.Context, domain.Profile) error { return nil }
+
+type memStates struct {
+	mu     sync.Mutex
+	states map[string]ports.OIDCLoginState
+}
+
+func (m *memStates) Create(_ context.Context, st ports.OIDCLoginState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[st.StateHash] = st
+	return nil
+}
+
+func (m *memStates) Consume(_ context.Context, hash string) (*ports.OIDCLoginState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st, ok := m.states[hash]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	delete(m.states, hash)
+	return &st, nil
+}
+
+// corruptVerifiers swaps every stored PKCE verifier, as if the code were
+// redeemed by someone who never saw the original one.
+func (m *memStates) corruptVerifiers() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, st := range m.states {
+		st.CodeVerifier = uuid.NewString()
+		m.states[k] = st
+	}
+}
+
+type memIdentities struct {
+	mu    sync.Mutex
+	links map[string]ports.UserIdentity
+}
+
+func (m *memIdentities) ByProviderSubject(_ context.Context, provider, subject string) (*ports.UserIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.links[provider+"|"+subject]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &id, nil
+}
+
+func (m *memIdentities) Link(_ context.Context, id ports.UserIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.links[id.Provider+"|"+id.Subject] = id
+	return nil
+}
+
+type oidcHarness struct {
+	users  *memUsers
+	states *memStates
+	idents *memIdentities
+	router *gin.Engine
+}
+
+func newOIDCHarness(t *testing.T) *oidcHarness {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	issuer := startStubProvider(t)
+	keys, err := auth.NewHMACKeySet([]byte("test-secret"), "confsite", "confsite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := services.AppConfig{
+		AppURL:           "http://app.test",
+		AccessTTL:        15 * time.Minute,
+		RefreshTTL:       time.Hour,
+		OIDCRedirectBase: "http://api.test/api",
+	}
+	hs := &oidcHarness{
+		users:  &memUsers{byID: map[uuid.UUID]*domain.User{}},
+		states: &memStates{states: map[string]ports.OIDCLoginState{}},
+		idents: &memIdentities{links: map[string]ports.UserIdentity{}},
+	}
+	idp := oidc.New(oidc.Config{ID: "stub", Issuer: issuer, ClientID: "confsite"})
+	svc := services.NewAuthService(cfg, keys, hs.users, memSessions{}, nil, nil, memProfiles{}, nil, memDevices{},
+		[]ports.IdentityProvider{idp}, hs.states, hs.idents, nil, nil, nil, services.SystemClock{})
+	hs.router = gin.New()
+	hs.router.GET("/api/auth/oidc/:provider/start", OIDCStart(svc, cfg))
+	hs.router.GET("/api/auth/oidc/:provider/callback", OIDCCallback(svc, cfg))
+	return hs
+}
+
+// start begins a login in a fresh browser and returns its state cookie and
+// the callback URL the provider sent the browser back to.
+func (hs *oidcHarness) start(t *testing.T) (*http.Cookie, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	hs.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/stub/start?next=/cabinet/talks", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("start: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var stateCookie *http.Cookie
+	for _, ck := range rec.Result().Cookies() {
+		if ck.Name == oidcStateCookie {
+			stateCookie = ck
+		}
+	}
+	if stateCookie == nil || !stateCookie.HttpOnly || stateCookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("start: want an httpOnly SameSite=Lax state cookie, got %+v", stateCookie)
+	}
+	authURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authURL.Query().Get("state") != stateCookie.Value {
+		t.Fatal("start: state cookie does not match the state sent to the provider")
+	}
+
+	noFollow := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noFollow.Get(authURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d", resp.StatusCode)
+	}
+	back, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stateCookie, back.RequestURI()
+}
+
+// callback delivers the provider redirect to the API with the given state
+// cookie and returns the frontend URL the browser ends up on.
+func (hs *oidcHarness) callback(t *testing.T, callbackURI string, stateCookie *http.Cookie) (string, []*http.Cookie) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, strings.Replace(callbackURI, "http://api.test", "", 1), nil)
+	if stateCookie != nil {
+		req.AddCookie(&http.Cookie{Name: stateCookie.Name, Value: stateCookie.Value})
+	}
+	rec := httptest.NewRecorder()
+	hs.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("callback: status %d: %s", rec.Code, rec.Body.String())
+	}
+	return rec.Header().Get("Location"), rec.Result().Cookies()
+}
+
+func hasCookie(cookies []*http.Cookie, name string) bool {
+	for _, ck := range cookies {
+		if ck.Name == name && ck.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOIDCLoginCreatesAccount(t *testing.T) {
+	hs := newOIDCHarness(t)
+	cookie, cb := hs.start(t)
+	loc, cookies := hs.callback(t, cb, cookie)
+	if loc != "http://app.test/cabinet/talks" {
+		t.Fatalf("landed on %q", loc)
+	}
+	if !hasCookie(cookies, "access_token") || !hasCookie(cookies, "refresh_token") {
+		t.Fatal("no session cookies issued")
+	}
+	if hs.users.created != 1 {
+		t.Fatalf("created %d accounts, want 1", hs.users.created)
+	}
+	u, _, err := hs.users.ByEmail(context.Background(), "sso.user@example.org")
+	if err != nil || !u.EmailVerified {
+		t.Fatalf("new account missing or unverified: %+v %v", u, err)
+	}
+	link, err := hs.idents.ByProviderSubject(context.Background(), "stub", "stub-user-1")
+	if err != nil || link.UserID != u.ID {
+		t.Fatalf("identity not linked to the new account: %+v %v", link, err)
+	}
+}
+
+func TestOIDCLoginLinksVerifiedEmail(t *testing.T) {
+	hs := newOIDCHarness(t)
+	existing := hs.users.add("sso.user@example.org", true)
+	cookie, cb := hs.start(t)
+	loc, _ := hs.callback(t, cb, cookie)
+	if strings.Contains(loc, "sso_error") {
+		t.Fatalf("login failed: %q", loc)
+	}
+	if hs.users.created != 0 {
+		t.Fatal("a second account was created instead of linking")
+	}
+	link, err := hs.idents.ByProviderSubject(context.Background(), "stub", "stub-user-1")
+	if err != nil || link.UserID != existing {
+		t.Fatalf("identity not linked to the existing account: %+v %v", link, err)
+	}
+}
+
+func TestOIDCLoginRefusesUnverifiedLocalAccount(t *testing.T) {
+	hs := newOIDCHarness(t)
+	hs.users.add("sso.user@example.org", false)
+	cookie, cb := hs.start(t)
+	if loc, _ := hs.callback(t, cb, cookie); loc != "http://app.test/login?sso_error=email_not_verified" {
+		t.Fatalf("landed on %q", loc)
+	}
+	if _, err := hs.idents.ByProviderSubject(context.Background(), "stub", "stub-user-1"); err == nil {
+		t.Fatal("identity was linked to an unverified account")
+	}
+}
+
+func TestOIDCLoginRequiresPKCEVerifier(t *testing.T) {
+	hs := newOIDCHarness(t)
+	cookie, cb := hs.start(t)
+	hs.states.corruptVerifiers()
+	if loc, cookies := hs.callback(t, cb, cookie); loc != "http://app.test/login?sso_error=failed" || hasCookie(cookies, "access_token") {
+		t.Fatalf("login with a wrong code_verifier succeeded: %q", loc)
+	}
+}
+
+func TestOIDCLoginRejectsForeignState(t *testing.T) {
+	hs := newOIDCHarness(t)
+	// The attacker completes a login in their own browser but hands the
+	// callback URL to the victim, whose browser holds a different state.
+	_, attackerCallback := hs.start(t)
+	victimCookie, _ := hs.start(t)
+	for name, ck := range map[string]*http.Cookie{"other browser": victimCookie, "no cookie": nil} {
+		if loc, cookies := hs.callback(t, attackerCallback, ck); loc != "http://app.test/login?sso_error=failed" || hasCookie(cookies, "access_token") {
+			t.Fatalf("%s: callback with a foreign state signed in: %q", name, loc)
+		}
+	}
+	if hs.users.created != 0 {
+		t.Fatal("an account was created from a foreign state")
+	}
+}
+
+func TestOIDCLoginRejectsReplayedState(t *testing.T) {
+	hs := newOIDCHarness(t)
+	cookie, cb := hs.start(t)
+	if loc, _ := hs.callback(t, cb, cookie); strings.Contains(loc, "sso_error") {
+		t.Fatalf("first callback failed: %q", loc)
+	}
+	if loc, cookies := hs.callback(t, cb, cookie); loc != "http://app.test/login?sso_error=failed" || hasCookie(cookies, "access_token") {
+		t.Fatalf("replayed callback signed in: %q", loc)
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"confsite/backend/internal/adapters/http/dto"
@@ -78,7 +79,7 @@ func CreateTalk(s *services.TalkService) gin.HandlerFunc {
 			return
 		}
 		authors, _ := json.Marshal(req.Authors)
-		id, err := s.Create(c, uid, domain.Talk{
+		id, warnings, err := s.Create(c, uid, domain.Talk{
 			SectionID:   req.SectionID,
 			Title:       req.Title,
 			Affiliation: req.Affiliation,
@@ -90,7 +91,7 @@ func CreateTalk(s *services.TalkService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"id": id})
+		c.JSON(http.StatusOK, gin.H{"id": id, "possibleDuplicates": warnings})
 	}
 }
 
@@ -127,7 +128,7 @@ func UpdateTalk(s *services.TalkService) gin.HandlerFunc {
 			return
 		}
 		authors, _ := json.Marshal(req.Authors)
-		err := s.Update(c, uid, domain.Talk{
+		warnings, err := s.Update(c, uid, domain.Talk{
 			ID:          tid,
 			SectionID:   req.SectionID,
 			Title:       req.Title,
@@ -140,7 +141,7 @@ func UpdateTalk(s *services.TalkService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"ok": true})
+		c.JSON(http.StatusOK, gin.H{"ok": true, "possibleDuplicates": warnings})
 	}
 }
 
@@ -149,9 +150,48 @@ func DeleteTalk(s *services.TalkService) gin.HandlerFunc {
 		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
 		tid := uuid.MustParse(c.Param("id"))
 		if err := s.Delete(c, uid, tid); err != nil {
+			if errors.Is(err, domain.ErrInvalidState) {
+				// approved talks are withdrawn, not deleted
+				c.JSON(http.StatusConflict, gin.H{"error": "withdraw_required"})
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": "bad"})
 			return
 		}
 		c.JSON(http.StatusOK, gin.H{"ok": true})
 	}
 }
+
+func WithdrawTalk(s *services.TalkService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		tid, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid talk id"})
+			return
+		}
+		var req dto.ReasonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.Withdraw(c, uid, tid, req.Reason); err != nil {
+			writeWithdrawalError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "status": domain.TalkStatusWithdrawn})
+	}
+}
+
+func writeWithdrawalError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason_required"})
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, domain.ErrInvalidState):
+		c.JSON(http.StatusConflict, gin.H{"error": "already_withdrawn"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
@@ -4,66 +4,84 @@ import (
 	"net/http"
 
 	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
 	"confsite/backend/internal/ports"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-func PublicPage(s *services.PageService) gin.HandlerFunc {
+// PublicPage serves the best translation for the request's content locale;
+// locale tells which one was used.
+func PublicPage(s *services.PageService, tr *services.TranslationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		lang := ctxLang(c)
 		slug := c.Param("slug")
 		p, err := s.Get(c, slug)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 			return
 		}
-		if lang == "en" {
-			c.JSON(http.StatusOK, gin.H{"title": p.TitleEn, "body": p.BodyEn})
-		} else {
-			c.JSON(http.StatusOK, gin.H{"title": p.TitleRu, "body": p.BodyRu})
+		loc, err := tr.Resolve(c, domain.ContentPage, ctxContentLocale(c), []services.TranslatableItem{
+			{Key: slug, TitleRu: p.TitleRu, BodyRu: p.BodyRu, TitleEn: p.TitleEn, BodyEn: p.BodyEn},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
 		}
+		l := loc[slug]
+		c.JSON(http.StatusOK, gin.H{"title": l.Title, "body": l.Body, "locale": l.Locale})
+	}
+}
+
+func newsItems(rows []domain.News) []services.TranslatableItem {
+	items := make([]services.TranslatableItem, 0, len(rows))
+	for _, n := range rows {
+		items = append(items, services.TranslatableItem{Key: n.ID.String(), TitleRu: n.TitleRu, BodyRu: n.BodyRu, TitleEn: n.TitleEn, BodyEn: n.BodyEn})
 	}
+	return items
 }
 
-func PublicNewsList(s *services.NewsService) gin.HandlerFunc {
+func PublicNewsList(s *services.NewsService, tr *services.TranslationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		lang := ctxLang(c)
 		rows, err := s.List(c)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
 			return
 		}
+		loc, err := tr.Resolve(c, domain.ContentNews, ctxContentLocale(c), newsItems(rows))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
 		out := make([]gin.H, 0, len(rows))
 		for _, n := range rows {
-			if lang == "en" {
-				out = append(out, gin.H{"id": n.ID, "title": n.TitleEn, "body": n.BodyEn, "pinned": n.Pinned, "publishedAt": n.PublishedAt})
-			} else {
-				out = append(out, gin.H{"id": n.ID, "title": n.TitleRu, "body": n.BodyRu, "pinned": n.Pinned, "publishedAt": n.PublishedAt})
-			}
+			out = append(out, newsJSON(n, loc[n.ID.String()]))
 		}
 		c.JSON(http.StatusOK, out)
 	}
 }
 
-func PublicNewsGet(s *services.NewsService) gin.HandlerFunc {
+func PublicNewsGet(s *services.NewsService, tr *services.TranslationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		lang := ctxLang(c)
 		id := uuid.MustParse(c.Param("id"))
 		n, err := s.Get(c, id)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 			return
 		}
-		if lang == "en" {
-			c.JSON(http.StatusOK, gin.H{"id": n.ID, "title": n.TitleEn, "body": n.BodyEn, "pinned": n.Pinned, "publishedAt": n.PublishedAt})
-		} else {
-			c.JSON(http.StatusOK, gin.H{"id": n.ID, "title": n.TitleRu, "body": n.BodyRu, "pinned": n.Pinned, "publishedAt": n.PublishedAt})
+		loc, err := tr.Resolve(c, domain.ContentNews, ctxContentLocale(c), newsItems([]domain.News{*n}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
 		}
+		c.JSON(http.StatusOK, newsJSON(*n, loc[n.ID.String()]))
 	}
 }
 
+func newsJSON(n domain.News, l services.Localized) gin.H {
+	return gin.H{"id": n.ID, "title": l.Title, "body": l.Body, "locale": l.Locale, "pinned": n.Pinned, "publishedAt": n.PublishedAt}
+}
+
 func PublicParticipants(p ports.ProfileRepo) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rows, err := p.ListApprovedPublic(c)
@@ -79,21 +97,26 @@ func PublicParticipants(p ports.ProfileRepo) gin.HandlerFunc {
 	}
 }
 
-func PublicSections(s ports.SectionRepo) gin.HandlerFunc {
+func PublicSections(s ports.SectionRepo, tr *services.TranslationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		lang := ctxLang(c)
 		rows, err := s.List(c)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
 			return
 		}
+		items := make([]services.TranslatableItem, 0, len(rows))
+		for _, r := range rows {
+			items = append(items, services.TranslatableItem{Key: r.ID.String(), TitleRu: r.TitleRu, TitleEn: r.TitleEn})
+		}
+		loc, err := tr.Resolve(c, domain.ContentSection, ctxContentLocale(c), items)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
 		out := make([]gin.H, 0, len(rows))
 		for _, r := range rows {
-			title := r.TitleRu
-			if lang == "en" {
-				title = r.TitleEn
-			}
-			out = append(out, gin.H{"id": r.ID, "title": title, "titleRu": r.TitleRu, "titleEn": r.TitleEn})
+			l := loc[r.ID.String()]
+			out = append(out, gin.H{"id": r.ID, "title": l.Title, "locale": l.Locale, "titleRu": r.TitleRu, "titleEn": r.TitleEn})
 		}
 		c.JSON(http.StatusOK, out)
 	}
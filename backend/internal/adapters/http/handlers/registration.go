@@ -1,6 +1,7 @@
 ﻿package http
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -45,10 +46,37 @@ func SubmitRegistration(s *services.RegistrationService) gin.HandlerFunc {
 			ConsentDataTransfer:      req.ConsentDataTransfer,
 		}
 
-		if err := s.Submit(c, uid, p, ctxLang(c)); err != nil {
+		if err := s.Submit(c, uid, p, req.Answers, ctxLang(c)); err != nil {
+			var fe *services.FieldValidationError
+			if errors.As(err, &fe) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_field", "field": fe.Key, "reason": fe.Reason})
+				return
+			}
+			var de *services.FieldDefinitionError
+			if errors.As(err, &de) {
+				println("Warning: registration of", uid.String(), "failed:", err.Error())
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		c.JSON(http.StatusOK, gin.H{"ok": true})
 	}
 }
+
+func CancelRegistration(s *services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		var req dto.ReasonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.Cancel(c, uid, req.Reason); err != nil {
+			writeWithdrawalError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "status": domain.StatusCancelled})
+	}
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/lib/files"
+	"confsite/backend/internal/middleware"
+	"confsite/backend/internal/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RegistrationFields lists the active extra fields for rendering the form.
+func RegistrationFields(s *services.RegistrationService) gin.HandlerFunc {
+	return listRegistrationFields(s, true)
+}
+
+func AdminListRegistrationFields(s *services.RegistrationService) gin.HandlerFunc {
+	return listRegistrationFields(s, false)
+}
+
+func listRegistrationFields(s *services.RegistrationService, activeOnly bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.ListFields(c, activeOnly)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		out := make([]dto.RegistrationFieldResponse, 0, len(items))
+		for _, f := range items {
+			out = append(out, registrationFieldResponse(f))
+		}
+		c.JSON(http.StatusOK, gin.H{"items": out})
+	}
+}
+
+func MyRegistrationAnswers(s *services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		answers, err := s.Answers(c, uid)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"answers": answers})
+	}
+}
+
+// UploadRegistrationFile stores a file for a "file" field; the returned URL is
+// then sent as the field's answer on submit.
+func UploadRegistrationFile(st ports.Storage, s *services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		f, err := s.FieldByKey(c, c.Param("key"))
+		if err != nil || f.Type != domain.FieldFile {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file required"})
+			return
+		}
+		src, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad file"})
+			return
+		}
+		defer src.Close()
+
+		data, _ := io.ReadAll(io.LimitReader(src, 6<<20)) // 6MB hard cap
+		mimeType, err := files.SniffAndValidate(fh.Filename, data, files.FileCheck{
+			MaxBytes: 5 << 20,
+			Allowed: map[string]bool{
+				"application/pdf":    true,
+				"application/msword": true,
+				"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+				"image/jpeg": true,
+				"image/png":  true,
+			},
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		key := services.RegistrationFileKeyPrefix(uid, f.Key) + safeName(fh.Filename)
+		if err := st.Put(c, key, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "upload failed"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "key": key, "url": st.PublicURL(key)})
+	}
+}
+
+func AdminCreateRegistrationField(s *services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.RegistrationFieldUpsertRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := s.CreateField(c, registrationFieldFromRequest(req))
+		if err != nil {
+			writeRegistrationFieldError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": id.String()})
+	}
+}
+
+func AdminUpdateRegistrationField(s *services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid field id"})
+			return
+		}
+		var req dto.RegistrationFieldUpsertRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		f := registrationFieldFromRequest(req)
+		f.ID = id
+		if err := s.UpdateField(c, f); err != nil {
+			writeRegistrationFieldError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func AdminDeleteRegistrationField(s *services.RegistrationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid field id"})
+			return
+		}
+		if err := s.DeleteField(c, id); err != nil {
+			writeRegistrationFieldError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func writeRegistrationFieldError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_field_definition"})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
+
+func registrationFieldFromRequest(req dto.RegistrationFieldUpsertRequest) domain.RegistrationField {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	opts := make([]domain.RegistrationFieldOption, 0, len(req.Options))
+	for _, o := range req.Options {
+		opts = append(opts, domain.RegistrationFieldOption{Value: o.Value, LabelRu: o.LabelRu, LabelEn: o.LabelEn})
+	}
+	v := req.Validation
+	return domain.RegistrationField{
+		Key:      req.Key,
+		Type:     domain.RegistrationFieldType(req.Type),
+		LabelRu:  req.LabelRu,
+		LabelEn:  req.LabelEn,
+		HelpRu:   req.HelpRu,
+		HelpEn:   req.HelpEn,
+		Options:  opts,
+		Required: req.Required,
+		Validation: domain.RegistrationFieldValidation{
+			MinLength: v.MinLength, MaxLength: v.MaxLength, Pattern: v.Pattern, MinDate: v.MinDate, MaxDate: v.MaxDate,
+		},
+		SortOrder: req.SortOrder,
+		Active:    active,
+	}
+}
+
+func registrationFieldResponse(f domain.RegistrationField) dto.RegistrationFieldResponse {
+	opts := make([]dto.RegistrationFieldOptionDTO, 0, len(f.Options))
+	for _, o := range f.Options {
+		opts = append(opts, dto.RegistrationFieldOptionDTO{Value: o.Value, LabelRu: o.LabelRu, LabelEn: o.LabelEn})
+	}
+	v := f.Validation
+	return dto.RegistrationFieldResponse{
+		ID:       f.ID.String(),
+		Key:      f.Key,
+		Type:     string(f.Type),
+		LabelRu:  f.LabelRu,
+		LabelEn:  f.LabelEn,
+		HelpRu:   f.HelpRu,
+		HelpEn:   f.HelpEn,
+		Options:  opts,
+		Required: f.Required,
+		Validation: dto.RegistrationFieldValidationDTO{
+			MinLength: v.MinLength, MaxLength: v.MaxLength, Pattern: v.Pattern, MinDate: v.MinDate, MaxDate: v.MaxDate,
+		},
+		SortOrder: f.SortOrder,
+		Active:    f.Active,
+	}
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+
+	"confsite/backend/internal/app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRemindersReport lists who was reminded about which missing items,
+// optionally limited to ?from= and ?to=.
+func AdminRemindersReport(s *services.ReminderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := listParams{c: c}
+		from, to := p.time("from"), p.time("to")
+		if !p.ok() {
+			return
+		}
+		items, err := s.Report(c, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": len(items), "items": items})
+	}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicSearchTalks searches the approved program.
+func PublicSearchTalks(tr ports.TalkRepo) gin.HandlerFunc {
+	return searchTalks(tr, false)
+}
+
+// AdminSearchTalks searches talks in any status; ?status= narrows it.
+func AdminSearchTalks(tr ports.TalkRepo) gin.HandlerFunc {
+	return searchTalks(tr, true)
+}
+
+func searchTalks(tr ports.TalkRepo, admin bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := listParams{c: c}
+		f := ports.TalkSearchFilter{
+			ListQuery: p.query(),
+			Lang:      ctxLang(c),
+			SectionID: p.uuid("sectionId"),
+		}
+		if v := p.str("kind"); v != nil {
+			kind := domain.TalkKind(*v)
+			f.Kind = &kind
+		}
+		approved := domain.TalkStatusApproved
+		f.Status = &approved
+		if admin {
+			f.Status = nil
+			if v := p.str("status"); v != nil {
+				st := domain.TalkStatus(*v)
+				f.Status = &st
+			}
+		}
+		if !p.ok() {
+			return
+		}
+		if strings.TrimSpace(f.Search) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query_required"})
+			return
+		}
+		hits, total, err := tr.Search(c, f)
+		if err != nil {
+			writeListError(c, err)
+			return
+		}
+		out := make([]gin.H, 0, len(hits))
+		for _, h := range hits {
+			item := gin.H{
+				"id":              h.ID,
+				"title":           h.Title,
+				"kind":            h.Kind,
+				"sectionId":       h.SectionID,
+				"sectionTitleRu":  h.SectionTitleRu,
+				"sectionTitleEn":  h.SectionTitleEn,
+				"speakerFullName": h.SpeakerFullName,
+				"authors":         json.RawMessage(h.AuthorsJSON),
+				"rank":            h.Rank,
+				"titleHighlight":  h.TitleHighlight,
+				"abstractSnippet": h.AbstractSnippet,
+			}
+			if admin {
+				item["status"] = h.Status
+			}
+			out = append(out, item)
+		}
+		writeListPage(c, out, total, f.ListQuery)
+	}
+}
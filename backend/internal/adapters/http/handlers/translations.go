@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminTranslationLocales(s *services.TranslationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"locales": s.Locales(), "fallback": s.Fallback()})
+	}
+}
+
+// AdminTranslationsList returns every locale of one page (by slug), news
+// item, section or material (by id).
+func AdminTranslationsList(s *services.TranslationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.List(c, domain.ContentEntity(c.Param("entity")), c.Param("key"))
+		if err != nil {
+			writeTranslationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func AdminTranslationPut(s *services.TranslationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.TranslationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		err := s.Put(c, domain.Translation{
+			Entity: domain.ContentEntity(c.Param("entity")),
+			Key:    c.Param("key"),
+			Locale: c.Param("locale"),
+			Title:  req.Title,
+			Body:   req.Body,
+		})
+		if err != nil {
+			writeTranslationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func AdminTranslationDelete(s *services.TranslationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.Delete(c, domain.ContentEntity(c.Param("entity")), c.Param("key"), c.Param("locale")); err != nil {
+			writeTranslationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func writeTranslationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_translation"})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
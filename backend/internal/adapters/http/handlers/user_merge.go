@@ -0,0 +1,51 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminMergeUsers merges the account sourceId into targetId and disables it.
+func AdminMergeUsers(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uid := c.MustGet(middleware.CtxUserIDKey).(uuid.UUID)
+		var req dto.MergeUsersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sourceID, errS := uuid.Parse(req.SourceID)
+		targetID, errT := uuid.Parse(req.TargetID)
+		if errS != nil || errT != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		res, err := s.MergeUsers(c, uid, sourceID, targetID, req.ProfileFromSource)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrInvalidInput):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_merge"})
+			case errors.Is(err, domain.ErrNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			case errors.Is(err, domain.ErrInvalidState):
+				c.JSON(http.StatusConflict, gin.H{"error": "already_disabled"})
+			case errors.Is(err, domain.ErrTalkLimitReached):
+				c.JSON(http.StatusConflict, gin.H{"error": "talk_limit_exceeded"})
+			case errors.Is(err, domain.ErrCapacityFull):
+				c.JSON(http.StatusConflict, gin.H{"error": "capacity_exceeded"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "moved": res})
+	}
+}
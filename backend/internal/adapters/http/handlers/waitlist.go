@@ -0,0 +1,111 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"confsite/backend/internal/adapters/http/dto"
+	"confsite/backend/internal/app/services"
+	"confsite/backend/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func AdminGetCapacity(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l, err := s.Capacity(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": l.Total, "categories": l.Categories})
+	}
+}
+
+func AdminSetCapacity(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.CapacityRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.SetCapacity(c, domain.CapacityLimits{Total: req.Total, Categories: req.Categories}); err != nil {
+			if errors.Is(err, domain.ErrInvalidInput) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_capacity"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func AdminListWaitlist(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := s.Waitlist(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+			return
+		}
+		out := make([]gin.H, 0, len(items))
+		for _, e := range items {
+			out = append(out, gin.H{
+				"userId":   e.UserID,
+				"email":    e.Email,
+				"fullName": e.FullName,
+				"category": e.Category,
+				"position": e.Position,
+				"queuedAt": e.QueuedAt,
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{"items": out})
+	}
+}
+
+func AdminPromoteWaitlisted(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		if err := s.PromoteFromWaitlist(c, id); err != nil {
+			writeWaitlistError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true, "status": domain.StatusApproved})
+	}
+}
+
+func AdminMoveWaitlisted(s *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		var req dto.MoveWaitlistRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.MoveInWaitlist(c, id, req.Position); err != nil {
+			writeWaitlistError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func writeWaitlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusConflict, gin.H{"error": "not_waitlisted"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error"})
+	}
+}
@@ -1,16 +1,22 @@
 package http
 
 import (
+	"context"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"confsite/backend/internal/adapters/db"
 	"confsite/backend/internal/adapters/db/repos"
 	h "confsite/backend/internal/adapters/http/handlers"
 	"confsite/backend/internal/adapters/mail"
+	"confsite/backend/internal/adapters/oidc"
 	"confsite/backend/internal/adapters/storage"
+	"confsite/backend/internal/app/scheduler"
 	"confsite/backend/internal/app/services"
 	"confsite/backend/internal/config"
+	"confsite/backend/internal/lib/auth"
 	"confsite/backend/internal/middleware"
 	"confsite/backend/internal/ports"
 
@@ -21,7 +27,7 @@ func NewRouter(cfg config.Config, database *db.DB) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Recovery())
 	r.Use(middleware.SecureHeaders())
-	r.Use(middleware.Locale())
+	r.Use(middleware.Locale(append([]string{"ru", "en"}, cfg.ContentLocales...)))
 	r.Use(middleware.Audit())
 	origins := []string{}
 	if env := os.Getenv("CORS_ORIGINS"); env != "" {
@@ -37,6 +43,7 @@ func NewRouter(cfg config.Config, database *db.DB) *gin.Engine {
 	// rate limit for sensitive routes
 	authRL := middleware.NewRateLimiter(30, 10) // 30/min burst 10
 	regRL := middleware.NewRateLimiter(10, 5)
+	resendRL := middleware.NewRateLimiter(5, 3)
 
 	// repos
 	usersRepo := repos.NewUsersRepo(database.Pool)
@@ -52,6 +59,19 @@ func NewRouter(cfg config.Config, database *db.DB) *gin.Engine {
 	exportsRepo := repos.NewExportsRepo(database.Pool)
 	consentRepo := repos.NewConsentFileRepo(database.Pool)
 	documentsRepo := repos.NewDocumentsRepo(database.Pool)
+	loginAttemptsRepo := repos.NewLoginAttemptsRepo(database.Pool)
+	userDevicesRepo := repos.NewUserDevicesRepo(database.Pool)
+	oidcStatesRepo := repos.NewOIDCStatesRepo(database.Pool)
+	identitiesRepo := repos.NewUserIdentitiesRepo(database.Pool)
+	apiTokensRepo := repos.NewAPITokensRepo(database.Pool)
+	emailChangeRepo := repos.NewEmailChangeRepo(database.Pool)
+	regFieldsRepo := repos.NewRegistrationFieldsRepo(database.Pool)
+	regAnswersRepo := repos.NewRegistrationAnswersRepo(database.Pool)
+	waitlistRepo := repos.NewWaitlistRepo(database.Pool)
+	moderationRepo := repos.NewModerationRepo(database.Pool)
+	duplicatesRepo := repos.NewDuplicatesRepo(database.Pool)
+	campaignsRepo := repos.NewCampaignsRepo(database.Pool)
+	remindersRepo := repos.NewRemindersRepo(database.Pool)
 
 	// storage
 	var st ports.Storage
@@ -72,35 +92,153 @@ func NewRouter(cfg config.Config, database *db.DB) *gin.Engine {
 		st = storage.NewLocal(cfg.Storage.LocalDir, publicURL)
 	}
 
-	// email templates + mailer
+	// email templates (admin overrides in the database) + mailer
 	templateRoot := "./templates/email"
-	tpl := mail.NewTemplates(templateRoot)
+	emailTemplatesRepo := repos.NewEmailTemplatesRepo(database.Pool)
+	tpl := mail.NewTemplates(templateRoot, emailTemplatesRepo)
 	tplSvc := mail.NewTemplatesService(tpl)
 
-	mailerSvc := mail.NewSMTPMailer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.From, cfg.SMTP.User, cfg.SMTP.Pass)
+	// single sign-on providers
+	identityProviders := make([]ports.IdentityProvider, 0, len(cfg.OIDC.Providers))
+	for _, p := range cfg.OIDC.Providers {
+		identityProviders = append(identityProviders, oidc.New(oidc.Config{
+			ID:           p.ID,
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			Scopes:       p.Scopes,
+		}))
+	}
+
+	// outgoing mail: transport per MAIL_TRANSPORT, DKIM-signed when a key is configured
+	var mailTransport ports.MailTransport
+	switch cfg.Mail.Transport {
+	case "sendmail":
+		mailTransport = mail.NewSendmailTransport(cfg.Mail.SendmailPath)
+	case "file":
+		mailTransport = mail.NewFileTransport(cfg.Mail.FileDir)
+	case "smtp":
+		mailTransport = mail.NewSMTPTransport(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.User, cfg.SMTP.Pass)
+		if fb := cfg.Mail.Fallback; fb.Host != "" {
+			mailTransport = mail.NewFailoverTransport(mailTransport, mail.NewSMTPTransport(fb.Host, fb.Port, fb.User, fb.Pass))
+		}
+	default:
+		panic("unknown MAIL_TRANSPORT " + strconv.Quote(cfg.Mail.Transport) + " (want smtp, sendmail or file)")
+	}
+	var dkim *mail.DKIMSigner
+	if cfg.Mail.DKIMKeyFile != "" {
+		dkimDomain := cfg.Mail.DKIMDomain
+		if dkimDomain == "" {
+			if _, d, ok := strings.Cut(cfg.SMTP.From, "@"); ok {
+				dkimDomain = strings.Trim(d, "> ")
+			}
+		}
+		signer, err := mail.LoadDKIMSigner(dkimDomain, cfg.Mail.DKIMSelector, cfg.Mail.DKIMKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		dkim = signer
+	}
+	mailerSvc := mail.NewMailer(cfg.SMTP.From, mailTransport, dkim)
+
+	// bounces: hard-bounced addresses get no notifications; sign-in mail still goes out
+	bouncesRepo := repos.NewBouncesRepo(database.Pool)
+	notifyMailer := mail.NewSuppressingMailer(mailerSvc, bouncesRepo)
+	var bounceSource ports.BounceSource
+	switch cfg.Bounces.Source {
+	case "maildir":
+		bounceSource = mail.NewMaildirBounces(cfg.Bounces.MaildirPath)
+	case "fake":
+		bounceSource = mail.NewFakeBounces()
+	}
 
 	// services
 	appCfg := services.AppConfig{
-		AppURL:         cfg.AppURL,
-		AccessTTL:      cfg.JWT.AccessTTL,
-		RefreshTTL:     cfg.JWT.RefreshTTL,
-		VerifyEmailTTL: 24 * 60 * 60 * 1e9, // 24h default; лучше вынести в env позже
-		CookieSecure:   cfg.Env == "prod",
-		CookieDomain:   "",
-
-		OrganizerEmails: cfg.OrganizerEmails,
+		AppURL:           cfg.AppURL,
+		AccessTTL:        cfg.JWT.AccessTTL,
+		RefreshTTL:       cfg.JWT.RefreshTTL,
+		ImpersonationTTL: cfg.JWT.ImpersonationTTL,
+		VerifyEmailTTL:   24 * 60 * 60 * 1e9, // 24h default; лучше вынести в env позже
+		CookieSecure:     cfg.Env == "prod",
+		CookieDomain:     "",
+
+		OrganizerEmails:       cfg.OrganizerEmails,
+		UnverifiedMaxAge:      cfg.UnverifiedMaxAge,
+		CapacityCategoryField: cfg.CapacityCategoryField,
+		DuplicateThreshold:    cfg.DuplicateThreshold,
+		CampaignBatchSize:     cfg.CampaignBatchSize,
+		ReminderInterval:      cfg.ReminderInterval,
+		ReminderCutoff:        cfg.ReminderCutoff,
+		DigestHour:            cfg.DigestHour,
+		ContentLocales:        cfg.ContentLocales,
+		LocaleFallback:        cfg.LocaleFallback,
+		Login: services.LoginPolicy{
+			MaxFailures:   cfg.Login.MaxFailures,
+			FailureWindow: cfg.Login.FailureWindow,
+			Lockout:       cfg.Login.Lockout,
+			DelayAfter:    cfg.Login.DelayAfter,
+			DelayBase:     cfg.Login.DelayBase,
+			DelayMax:      cfg.Login.DelayMax,
+		},
+		OIDCRedirectBase: cfg.OIDC.RedirectBase,
 	}
 	clock := services.SystemClock{}
 
-	authSvc := services.NewAuthService(appCfg, cfg.JWT.Secret, usersRepo, sessionsRepo, emailTokensRepo, profilesRepo, mailerSvc, tplSvc, clock)
-	regSvc := services.NewRegistrationService(appCfg, usersRepo, profilesRepo, mailerSvc, tplSvc)
-	talkSvc := services.NewTalkService(appCfg, talksRepo, profilesRepo, sectionsRepo, usersRepo, mailerSvc, tplSvc)
+	// access token keys: RS256/EdDSA from JWT_KEYS_DIR, HS256 with JWT_SECRET otherwise
+	var jwtKeys *auth.KeySet
+	var err error
+	if cfg.JWT.KeysDir != "" {
+		jwtKeys, err = auth.LoadKeySet(cfg.JWT.KeysDir, cfg.JWT.ActiveKID, cfg.JWT.Issuer, cfg.JWT.Audience)
+	} else {
+		jwtKeys, err = auth.NewHMACKeySet(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.Audience)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	authSvc := services.NewAuthService(appCfg, jwtKeys, usersRepo, sessionsRepo, emailTokensRepo, emailChangeRepo, profilesRepo, loginAttemptsRepo, userDevicesRepo, identityProviders, oidcStatesRepo, identitiesRepo, auditRepo, mailerSvc, tplSvc, clock)
+	regSvc := services.NewRegistrationService(appCfg, usersRepo, profilesRepo, regFieldsRepo, regAnswersRepo, talksRepo, sectionsRepo, waitlistRepo, notifyMailer, tplSvc, clock)
+	talkSvc := services.NewTalkService(appCfg, talksRepo, profilesRepo, sectionsRepo, usersRepo, duplicatesRepo, notifyMailer, tplSvc, clock)
 	pageSvc := services.NewPageService(pagesRepo)
-	newsSvc := services.NewNewsService(newsRepo)
-	expSvc := services.NewExportService(exportsRepo)
-	adminSvc := services.NewAdminService(appCfg, usersRepo, profilesRepo, talksRepo, sectionsRepo, newsRepo, pagesRepo, auditRepo, mailerSvc, tplSvc)
+	translationSvc := services.NewTranslationService(repos.NewTranslationsRepo(database.Pool), appCfg)
+	expSvc := services.NewExportService(exportsRepo, regFieldsRepo)
+	adminSvc := services.NewAdminService(appCfg, usersRepo, profilesRepo, talksRepo, sectionsRepo, newsRepo, pagesRepo, auditRepo, waitlistRepo, moderationRepo, notifyMailer, tplSvc)
+	emailTplSvc := services.NewEmailTemplateService(emailTemplatesRepo, tpl)
+	campaignSvc := services.NewCampaignService(campaignsRepo, usersRepo, profilesRepo, auditRepo, notifyMailer, clock, appCfg)
+	newsSvc := services.NewNewsService(newsRepo, campaignSvc, clock, appCfg)
+	sitemapSvc := services.NewSitemapService(pagesRepo, newsSvc, repos.NewSitemapRepo(database.Pool))
+	reminderSvc := services.NewReminderService(remindersRepo, notifyMailer, tplSvc, clock, appCfg)
+	digestSvc := services.NewDigestService(sectionsRepo, notifyMailer, tplSvc, clock, appCfg)
+	bounceSvc := services.NewBounceService(bouncesRepo, bounceSource, clock)
+	apiTokenSvc := services.NewAPITokenService(apiTokensRepo, usersRepo, auditRepo, clock)
+
+	// cookie session or personal API token (Authorization: Bearer); routes
+	// taking tokens are registered through routes.Scoped
+	routes := middleware.NewRoutes()
+	requireAuth := middleware.Auth(middleware.AuthConfig{Keys: jwtKeys, Bearer: apiTokenSvc, Impersonation: authSvc, Routes: routes})
+
+	// verification keys for other services; also under /api for the reverse proxy
+	r.GET("/.well-known/jwks.json", h.JWKS(jwtKeys))
+	r.GET("/sitemap.xml", h.Sitemap(sitemapSvc, appCfg.AppURL))
+
+	// background jobs
+	jobs := scheduler.New(repos.NewJobLocks(database.Pool))
+	jobs.Every("purge_unverified_accounts", time.Hour, authSvc.PurgeUnverified)
+	jobs.Every("purge_login_attempts", time.Hour, authSvc.PurgeLoginAttempts)
+	jobs.Every("deliver_campaigns", cfg.CampaignInterval, campaignSvc.Deliver)
+	jobs.Every("send_checklist_reminders", time.Hour, reminderSvc.SendDue)
+	jobs.Every("send_responsible_digests", time.Hour, digestSvc.SendDue)
+	jobs.Every("publish_scheduled_news", time.Minute, newsSvc.PublishDue)
+	jobs.Every("deliver_bulk_mail", time.Minute, adminSvc.DeliverBulkMail)
+	if bounceSource != nil {
+		jobs.Every("poll_mail_bounces", cfg.Bounces.PollInterval, bounceSvc.Poll)
+	}
+	jobs.Start(context.Background())
 
 	api := r.Group("/api")
+	api.GET("/.well-known/jwks.json", h.JWKS(jwtKeys))
+	api.GET("/sitemap.xml", h.Sitemap(sitemapSvc, appCfg.AppURL))
 
 	// auth
 	api.POST("/auth/register", authRL.Middleware(), h.Register(authSvc))
@@ -108,99 +246,183 @@ func NewRouter(cfg config.Config, database *db.DB) *gin.Engine {
 	api.POST("/auth/refresh", h.Refresh(authSvc, appCfg))
 	api.POST("/auth/logout", h.Logout(authSvc, appCfg))
 	api.GET("/auth/verify-email", h.VerifyEmail(authSvc))
+	api.POST("/auth/resend-verification", resendRL.Middleware(), h.ResendVerification(authSvc))
+	api.POST("/auth/email-change/confirm", authRL.Middleware(), h.ConfirmEmailChange(authSvc))
+	api.POST("/auth/email-change/cancel", authRL.Middleware(), h.CancelEmailChange(authSvc))
+	routes.ImpersonationExit(api).POST("/auth/impersonation/stop", requireAuth, h.StopImpersonation(authSvc, appCfg))
+	api.GET("/auth/oidc/providers", h.OIDCProviders(authSvc))
+	api.GET("/auth/oidc/:provider/start", authRL.Middleware(), h.OIDCStart(authSvc, appCfg))
+	api.GET("/auth/oidc/:provider/callback", authRL.Middleware(), h.OIDCCallback(authSvc, appCfg))
 
 	// public
 	pub := api.Group("/public")
-	pub.GET("/pages/:slug", h.PublicPage(pageSvc))
-	pub.GET("/news", h.PublicNewsList(newsSvc))
-	pub.GET("/news/:id", h.PublicNewsGet(newsSvc))
+	pub.GET("/pages/:slug", h.PublicPage(pageSvc, translationSvc))
+	pub.GET("/news", h.PublicNewsList(newsSvc, translationSvc))
+	pub.GET("/news/:id", h.PublicNewsGet(newsSvc, translationSvc))
+	pub.GET("/feeds/news.atom", h.PublicNewsAtom(newsSvc, translationSvc, appCfg.AppURL))
+	pub.GET("/feeds/news.rss", h.PublicNewsRSS(newsSvc, translationSvc, appCfg.AppURL))
 	pub.GET("/participants", h.PublicParticipants(profilesRepo))
-	pub.GET("/sections", h.PublicSections(sectionsRepo))
+	pub.GET("/sections", h.PublicSections(sectionsRepo, translationSvc))
 	pub.GET("/program", h.PublicProgram(talksRepo))
+	pub.GET("/talks/search", h.PublicSearchTalks(talksRepo))
 	pub.GET("/program-file", h.PublicProgramFile(database.Pool))
-	pub.GET("/materials", h.PublicMaterialsList(materialsRepo))
+	pub.GET("/materials", h.PublicMaterialsList(materialsRepo, translationSvc))
+	pub.POST("/unsubscribe", authRL.Middleware(), h.PublicUnsubscribe(campaignSvc))
+
+	// mail provider delivery reports
+	api.POST("/webhooks/mail-bounces", h.MailBounceWebhook(bounceSvc, cfg.Bounces.WebhookSecret))
 
 	// me
-	api.GET("/me", middleware.Auth(middleware.AuthConfig{JWTSecret: cfg.JWT.Secret}), h.Me(usersRepo))
+	routes.Scoped(api, "profile").GET("/me", requireAuth, h.Me(usersRepo))
+	routes.Scoped(api, "profile").PUT("/me/language", requireAuth, middleware.DenyImpersonation(), h.SetPreferredLang(authSvc))
+
+	// personal API tokens; managed from a browser session only
+	myTokens := api.Group("/me/tokens", requireAuth, middleware.RequireSession(), middleware.DenyImpersonation())
+	myTokens.GET("", h.ListMyAPITokens(apiTokenSvc))
+	myTokens.POST("", h.CreateAPIToken(apiTokenSvc))
+	myTokens.DELETE("/:id", h.RevokeMyAPIToken(apiTokenSvc))
+
+	api.POST("/me/email", authRL.Middleware(), requireAuth, middleware.RequireSession(), middleware.DenyImpersonation(), h.RequestEmailChange(authSvc))
 
 	// files (participant)
-	api.POST("/files/consent",
+	routes.Scoped(api, "profile").POST("/files/consent",
 		regRL.Middleware(),
-		middleware.Auth(middleware.AuthConfig{JWTSecret: cfg.JWT.Secret}),
+		requireAuth,
+		middleware.DenyImpersonation(),
 		h.UploadConsent(st, consentRepo),
 	)
 
 	participant := api.Group("/participant",
-		middleware.Auth(middleware.AuthConfig{JWTSecret: cfg.JWT.Secret}),
+		requireAuth,
 	)
-	participant.GET("/profile", h.GetProfile(profilesRepo))
-	participant.PUT("/profile", h.PutProfile(profilesRepo))
-	participant.GET("/talks", h.ListMyTalks(talkSvc))
-	participant.POST("/talks", h.CreateTalk(talkSvc))
-	participant.GET("/talks/:id", h.GetMyTalk(talkSvc))
-	participant.PUT("/talks/:id", h.UpdateTalk(talkSvc))
-	participant.DELETE("/talks/:id", h.DeleteTalk(talkSvc))
-	participant.POST("/talks/:id/file", h.UploadTalkFile(st, talkSvc))
+	routes.Scoped(participant, "profile").GET("/profile", h.GetProfile(profilesRepo))
+	routes.Scoped(participant, "profile").PUT("/profile", h.PutProfile(profilesRepo))
+	routes.Scoped(participant, "talks").GET("/talks", h.ListMyTalks(talkSvc))
+	routes.Scoped(participant, "talks").POST("/talks", h.CreateTalk(talkSvc))
+	routes.Scoped(participant, "talks").GET("/talks/:id", h.GetMyTalk(talkSvc))
+	routes.Scoped(participant, "talks").PUT("/talks/:id", h.UpdateTalk(talkSvc))
+	routes.Scoped(participant, "talks").DELETE("/talks/:id", h.DeleteTalk(talkSvc))
+	routes.Scoped(participant, "talks").POST("/talks/:id/withdraw", middleware.DenyImpersonation(), h.WithdrawTalk(talkSvc))
+	routes.Scoped(participant, "talks").POST("/talks/:id/file", h.UploadTalkFile(st, talkSvc))
 
 	// registration submit
-	api.POST("/registration/submit",
+	routes.Scoped(api, "profile").POST("/registration/submit",
 		regRL.Middleware(),
-		middleware.Auth(middleware.AuthConfig{JWTSecret: cfg.JWT.Secret}),
+		requireAuth,
+		middleware.DenyImpersonation(),
 		h.SubmitRegistration(regSvc),
 	)
+	routes.Scoped(api, "profile").POST("/registration/cancel", requireAuth, middleware.DenyImpersonation(), h.CancelRegistration(regSvc))
+	api.GET("/registration/fields", h.RegistrationFields(regSvc))
+	routes.Scoped(api, "profile").GET("/registration/answers", requireAuth, h.MyRegistrationAnswers(regSvc))
+	routes.Scoped(api, "profile").POST("/registration/files/:key",
+		regRL.Middleware(),
+		requireAuth,
+		middleware.DenyImpersonation(),
+		h.UploadRegistrationFile(st, regSvc),
+	)
 
 	// admin
 	admin := api.Group("/admin",
-		middleware.Auth(middleware.AuthConfig{JWTSecret: cfg.JWT.Secret}),
+		requireAuth,
 		middleware.RequireRole("ADMIN"),
 	)
 
-	admin.GET("/users", h.AdminListUsers(adminSvc))
-	admin.PATCH("/users/:id/status", h.AdminSetUserStatus(adminSvc))
-	admin.POST("/users/reset-password", h.ResetUserPasswordHandler(usersRepo))
-	admin.GET("/users/:id/consents", h.AdminGetUserConsents(consentRepo))
-
-	admin.GET("/sections", h.AdminSectionsList(sectionsRepo))
-	admin.POST("/sections", h.AdminSectionsCreate(sectionsRepo))
-
-	admin.GET("/news", h.AdminNewsList(newsSvc))
-	admin.POST("/news", h.AdminNewsCreate(newsSvc))
-	admin.PUT("/news/:id", h.AdminNewsUpdate(newsSvc))
-	admin.DELETE("/news/:id", h.AdminNewsDelete(newsSvc))
-
-	admin.GET("/pages", h.AdminPagesList(pageSvc))
-	admin.PUT("/pages/:slug", h.AdminPagesUpsert(pageSvc))
-
-	admin.GET("/talks", h.AdminTalksList(talksRepo))
-	admin.PUT("/talks/:id", h.AdminUpdateTalk(talksRepo))
-	admin.PATCH("/talks/:id/status", h.AdminSetTalkStatus(adminSvc))
-
-	admin.GET("/section-responsibles", h.AdminListSectionResponsibles(sectionsRepo))
-	admin.PUT("/sections/:id/responsibles", h.AdminSetSectionResponsibles(sectionsRepo))
-
-	admin.POST("/program/file", h.AdminUploadProgramFile(st, database.Pool))
-	admin.DELETE("/program/file", h.AdminDeleteProgramFile(database.Pool))
-
-	admin.GET("/materials", h.AdminListMaterials(materialsRepo))
-	admin.POST("/materials", h.AdminUploadMaterial(st, materialsRepo))
-	admin.PUT("/materials/:id", h.AdminUpdateMaterial(materialsRepo))
-	admin.DELETE("/materials/:id", h.AdminDeleteMaterial(st, materialsRepo))
-
-	admin.GET("/audit", h.AdminAuditList(auditRepo))
-
-	admin.POST("/documents/template", h.AdminUploadDocumentTemplate(st, documentsRepo))
-	admin.GET("/documents/templates", h.AdminListDocumentTemplates(documentsRepo))
+	routes.Scoped(admin, "users").GET("/users", h.AdminListUsers(adminSvc))
+	routes.Scoped(admin, "users").PATCH("/users/:id/status", h.AdminSetUserStatus(adminSvc))
+	routes.Scoped(admin, "users").POST("/users/bulk-status", h.AdminBulkUserStatus(adminSvc))
+	routes.Scoped(admin, "users").POST("/users/merge", h.AdminMergeUsers(adminSvc))
+	routes.Scoped(admin, "users").POST("/users/reset-password", h.ResetUserPasswordHandler(usersRepo))
+	admin.POST("/users/:id/impersonate", middleware.RequireSession(), h.AdminImpersonate(authSvc, appCfg))
+	routes.Scoped(admin, "users").POST("/users/:id/unlock", h.AdminUnlockUser(authSvc))
+	routes.Scoped(admin, "users").GET("/users/:id/consents", h.AdminGetUserConsents(consentRepo))
+
+	routes.Scoped(admin, "users").GET("/capacity", h.AdminGetCapacity(adminSvc))
+	routes.Scoped(admin, "users").PUT("/capacity", h.AdminSetCapacity(adminSvc))
+	routes.Scoped(admin, "users").GET("/waitlist", h.AdminListWaitlist(adminSvc))
+	routes.Scoped(admin, "users").POST("/waitlist/:id/promote", h.AdminPromoteWaitlisted(adminSvc))
+	routes.Scoped(admin, "users").PUT("/waitlist/:id/position", h.AdminMoveWaitlisted(adminSvc))
+
+	routes.Scoped(admin, "content").GET("/registration-fields", h.AdminListRegistrationFields(regSvc))
+	routes.Scoped(admin, "content").POST("/registration-fields", h.AdminCreateRegistrationField(regSvc))
+	routes.Scoped(admin, "content").PUT("/registration-fields/:id", h.AdminUpdateRegistrationField(regSvc))
+	routes.Scoped(admin, "content").DELETE("/registration-fields/:id", h.AdminDeleteRegistrationField(regSvc))
+
+	routes.Scoped(admin, "content").GET("/sections", h.AdminSectionsList(sectionsRepo))
+	routes.Scoped(admin, "content").POST("/sections", h.AdminSectionsCreate(sectionsRepo))
+
+	routes.Scoped(admin, "content").GET("/news", h.AdminNewsList(newsSvc))
+	routes.Scoped(admin, "content").GET("/news/:id/preview", h.AdminNewsPreview(newsSvc, translationSvc))
+	routes.Scoped(admin, "content").POST("/news", h.AdminNewsCreate(newsSvc))
+	routes.Scoped(admin, "content").PUT("/news/:id", h.AdminNewsUpdate(newsSvc))
+	routes.Scoped(admin, "content").DELETE("/news/:id", h.AdminNewsDelete(newsSvc))
+
+	routes.Scoped(admin, "content").GET("/pages", h.AdminPagesList(pageSvc))
+	routes.Scoped(admin, "content").PUT("/pages/:slug", h.AdminPagesUpsert(pageSvc))
+
+	// content translations: entity is page (key = slug), news, section or material (key = id)
+	routes.Scoped(admin, "content").GET("/translations/locales", h.AdminTranslationLocales(translationSvc))
+	routes.Scoped(admin, "content").GET("/translations/:entity/:key", h.AdminTranslationsList(translationSvc))
+	routes.Scoped(admin, "content").PUT("/translations/:entity/:key/:locale", h.AdminTranslationPut(translationSvc))
+	routes.Scoped(admin, "content").DELETE("/translations/:entity/:key/:locale", h.AdminTranslationDelete(translationSvc))
+
+	routes.Scoped(admin, "content").GET("/email-templates", h.AdminListEmailTemplates(emailTplSvc))
+	routes.Scoped(admin, "content").GET("/email-templates/:name/:lang", h.AdminGetEmailTemplate(emailTplSvc))
+	routes.Scoped(admin, "content").PUT("/email-templates/:name/:lang", h.AdminSaveEmailTemplate(emailTplSvc))
+	routes.Scoped(admin, "content").DELETE("/email-templates/:name/:lang", h.AdminResetEmailTemplate(emailTplSvc))
+	routes.Scoped(admin, "content").POST("/email-templates/:name/:lang/preview", h.AdminPreviewEmailTemplate(emailTplSvc))
+	routes.Scoped(admin, "content").GET("/email-templates/:name/:lang/versions", h.AdminEmailTemplateVersions(emailTplSvc))
+	routes.Scoped(admin, "content").POST("/email-templates/:name/:lang/rollback", h.AdminRollbackEmailTemplate(emailTplSvc))
+
+	routes.Scoped(admin, "users").GET("/campaigns", h.AdminListCampaigns(campaignSvc))
+	routes.Scoped(admin, "users").POST("/campaigns", h.AdminCreateCampaign(campaignSvc))
+	routes.Scoped(admin, "users").GET("/campaigns/:id", h.AdminGetCampaign(campaignSvc))
+	routes.Scoped(admin, "users").PUT("/campaigns/:id", h.AdminUpdateCampaign(campaignSvc))
+	routes.Scoped(admin, "users").DELETE("/campaigns/:id", h.AdminDeleteCampaign(campaignSvc))
+	routes.Scoped(admin, "users").GET("/campaigns/:id/recipients", h.AdminCampaignRecipients(campaignSvc))
+	routes.Scoped(admin, "users").POST("/campaigns/:id/test", h.AdminTestCampaign(campaignSvc))
+	routes.Scoped(admin, "users").POST("/campaigns/:id/send", h.AdminStartCampaign(campaignSvc))
+	routes.Scoped(admin, "users").POST("/campaigns/:id/cancel", h.AdminCancelCampaign(campaignSvc))
+	routes.Scoped(admin, "users").GET("/reminders", h.AdminRemindersReport(reminderSvc))
+
+	routes.Scoped(admin, "talks").GET("/talks", h.AdminTalksList(talksRepo))
+	routes.Scoped(admin, "talks").GET("/talks/search", h.AdminSearchTalks(talksRepo))
+	routes.Scoped(admin, "talks").GET("/duplicates", h.AdminDuplicatesReport(duplicatesRepo))
+	routes.Scoped(admin, "talks").POST("/duplicates/talks/dismiss", h.AdminDismissDuplicateTalks(duplicatesRepo))
+	routes.Scoped(admin, "talks").PUT("/talks/:id", h.AdminUpdateTalk(talksRepo))
+	routes.Scoped(admin, "talks").PATCH("/talks/:id/status", h.AdminSetTalkStatus(adminSvc))
+	routes.Scoped(admin, "talks").POST("/talks/bulk-status", h.AdminBulkTalkStatus(adminSvc))
+
+	routes.Scoped(admin, "content").GET("/section-responsibles", h.AdminListSectionResponsibles(sectionsRepo))
+	routes.Scoped(admin, "content").PUT("/sections/:id/responsibles", h.AdminSetSectionResponsibles(sectionsRepo))
+	routes.Scoped(admin, "content").PUT("/section-responsibles/preferences", h.AdminSetResponsibleMode(digestSvc))
+
+	routes.Scoped(admin, "content").POST("/program/file", h.AdminUploadProgramFile(st, database.Pool))
+	routes.Scoped(admin, "content").DELETE("/program/file", h.AdminDeleteProgramFile(database.Pool))
+
+	routes.Scoped(admin, "content").GET("/materials", h.AdminListMaterials(materialsRepo))
+	routes.Scoped(admin, "content").POST("/materials", h.AdminUploadMaterial(st, materialsRepo))
+	routes.Scoped(admin, "content").PUT("/materials/:id", h.AdminUpdateMaterial(materialsRepo))
+	routes.Scoped(admin, "content").DELETE("/materials/:id", h.AdminDeleteMaterial(st, materialsRepo))
+
+	routes.Scoped(admin, "audit").GET("/audit", h.AdminAuditList(auditRepo))
+
+	routes.Scoped(admin, "content").POST("/documents/template", h.AdminUploadDocumentTemplate(st, documentsRepo))
+	routes.Scoped(admin, "content").GET("/documents/templates", h.AdminListDocumentTemplates(documentsRepo))
+
+	admin.GET("/api-tokens", middleware.RequireSession(), h.AdminListAPITokens(apiTokenSvc))
+	admin.DELETE("/api-tokens/:id", middleware.RequireSession(), h.AdminRevokeAPIToken(apiTokenSvc))
 
 	// exports
-	admin.GET("/exports/participants.csv", h.ExportParticipantsCSV(expSvc))
-	admin.GET("/exports/participants.xlsx", h.ExportParticipantsXLSX(expSvc))
-	admin.GET("/exports/talks_by_section.xlsx", h.ExportTalksBySectionXLSX(expSvc))
+	routes.Scoped(admin, "exports").GET("/exports/participants.csv", h.ExportParticipantsCSV(expSvc))
+	routes.Scoped(admin, "exports").GET("/exports/participants.xlsx", h.ExportParticipantsXLSX(expSvc))
+	routes.Scoped(admin, "exports").GET("/exports/talks_by_section.xlsx", h.ExportTalksBySectionXLSX(expSvc))
 
 	// public document templates
 	pub.GET("/documents/templates", h.PublicListDocumentTemplates(documentsRepo))
 
 	// participant signed documents
-	participant.POST("/documents/signed", h.UploadSignedDocument(st, documentsRepo))
+	routes.Scoped(participant, "profile").POST("/documents/signed", middleware.DenyImpersonation(), h.UploadSignedDocument(st, documentsRepo))
 
 	// static files - serve uploaded files (consents, talks, etc)
 	localDir := cfg.Storage.LocalDir
@@ -0,0 +1,136 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"confsite/backend/internal/domain"
+)
+
+// ParseBounceReport extracts the failed recipients of an RFC 3464 delivery
+// status notification or the complained-about address of an RFC 5965 abuse
+// report. Other messages (auto-replies, delivered/relayed notices) yield no
+// bounces. Source and ReceivedAt are left to the caller.
+func ParseBounceReport(r io.Reader) ([]domain.MailBounce, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, nil
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var out []domain.MailBounce
+	var complaint *domain.MailBounce
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			out = append(out, parseDeliveryStatus(body)...)
+		case "message/feedback-report":
+			fields := readFieldBlocks(body)
+			if len(fields) == 0 {
+				continue
+			}
+			complaint = &domain.MailBounce{
+				Email:  addressOf(fields[0].Get("Original-Rcpt-To")),
+				Kind:   domain.BounceComplaint,
+				Reason: "complaint: " + fields[0].Get("Feedback-Type"),
+			}
+		case "message/rfc822", "text/rfc822-headers":
+			// the original message: its To header names the complainant when
+			// the feedback report does not
+			if complaint != nil && complaint.Email == "" {
+				if orig, err := mail.ReadMessage(bytes.NewReader(append(body, '\n', '\n'))); err == nil {
+					complaint.Email = addressOf(orig.Header.Get("To"))
+				}
+			}
+		}
+	}
+	if complaint != nil && complaint.Email != "" {
+		out = append(out, *complaint)
+	}
+	return out, nil
+}
+
+// parseDeliveryStatus reads the per-recipient blocks that follow the
+// per-message block of a delivery-status part.
+func parseDeliveryStatus(body []byte) []domain.MailBounce {
+	blocks := readFieldBlocks(body)
+	var out []domain.MailBounce
+	for i, f := range blocks {
+		if i == 0 && f.Get("Final-Recipient") == "" {
+			continue
+		}
+		email := addressOf(f.Get("Final-Recipient"))
+		if email == "" {
+			email = addressOf(f.Get("Original-Recipient"))
+		}
+		if email == "" {
+			continue
+		}
+		action := strings.ToLower(strings.TrimSpace(f.Get("Action")))
+		status := strings.TrimSpace(f.Get("Status"))
+		var kind domain.BounceKind
+		switch {
+		case action == "failed" && strings.HasPrefix(status, "5"):
+			kind = domain.BounceHard
+		case action == "failed" || action == "delayed":
+			kind = domain.BounceSoft
+		default:
+			continue
+		}
+		reason := status
+		if diag := strings.TrimSpace(f.Get("Diagnostic-Code")); diag != "" {
+			reason = fmt.Sprintf("%s %s", status, diag)
+		}
+		out = append(out, domain.MailBounce{Email: email, Kind: kind, Reason: reason})
+	}
+	return out
+}
+
+func readFieldBlocks(body []byte) []textproto.MIMEHeader {
+	tr := textproto.NewReader(bufio.NewReader(bytes.NewReader(body)))
+	var out []textproto.MIMEHeader
+	for {
+		h, err := tr.ReadMIMEHeader()
+		if len(h) > 0 {
+			out = append(out, h)
+		}
+		if err != nil {
+			return out
+		}
+	}
+}
+
+// addressOf turns "rfc822; user@example.org" or "<user@example.org>" into
+// a lower-case bare address.
+func addressOf(v string) string {
+	if i := strings.Index(v, ";"); i >= 0 {
+		v = v[i+1:]
+	}
+	v = strings.TrimSpace(v)
+	if a, err := mail.ParseAddress(v); err == nil {
+		v = a.Address
+	}
+	return strings.ToLower(strings.Trim(v, "<> "))
+}
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+
+	"confsite/backend/internal/domain"
+)
+
+// report wraps parts into a multipart/report message; each part is its
+// Content-Type followed by the body.
+func report(reportType string, parts ...[2]string) string {
+	var b strings.Builder
+	b.WriteString("From: MAILER-DAEMON@mx.example.net\r\n")
+	b.WriteString("To: bounces@conf.example.org\r\n")
+	b.WriteString("Subject: Undelivered Mail Returned to Sender\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: multipart/report; report-type=" + reportType + "; boundary=\"b1\"\r\n\r\n")
+	for _, p := range parts {
+		b.WriteString("--b1\r\nContent-Type: " + p[0] + "\r\n\r\n" + p[1] + "\r\n")
+	}
+	b.WriteString("--b1--\r\n")
+	return b.String()
+}
+
+var humanPart = [2]string{"text/plain", "This is the mail system at host mx.example.net."}
+
+func TestParseBounceReport(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want []domain.MailBounce
+	}{
+		{
+			name: "hard bounce",
+			msg: report("delivery-status", humanPart, [2]string{"message/delivery-status",
+				"Reporting-MTA: dns; mx.example.net\r\n\r\n" +
+					"Final-Recipient: rfc822; Ivan.Petrov@Example.org\r\n" +
+					"Action: failed\r\nStatus: 5.1.1\r\n" +
+					"Diagnostic-Code: smtp; 550 5.1.1 User unknown\r\n"}),
+			want: []domain.MailBounce{{Email: "ivan.petrov@example.org", Kind: domain.BounceHard, Reason: "5.1.1 smtp; 550 5.1.1 User unknown"}},
+		},
+		{
+			name: "soft bounce and delay for several recipients",
+			msg: report("delivery-status", humanPart, [2]string{"message/delivery-status",
+				"Reporting-MTA: dns; mx.example.net\r\n\r\n" +
+					"Final-Recipient: rfc822; full@example.org\r\nAction: failed\r\nStatus: 4.2.2\r\n\r\n" +
+					"Final-Recipient: rfc822; slow@example.org\r\nAction: delayed\r\nStatus: 4.4.1\r\n\r\n" +
+					"Final-Recipient: rfc822; fine@example.org\r\nAction: delivered\r\nStatus: 2.0.0\r\n"}),
+			want: []domain.MailBounce{
+				{Email: "full@example.org", Kind: domain.BounceSoft, Reason: "4.2.2"},
+				{Email: "slow@example.org", Kind: domain.BounceSoft, Reason: "4.4.1"},
+			},
+		},
+		{
+			name: "original recipient when final is missing",
+			msg: report("delivery-status", [2]string{"message/delivery-status",
+				"Reporting-MTA: dns; mx.example.net\r\n\r\n" +
+					"Original-Recipient: rfc822; <old@example.org>\r\nAction: failed\r\nStatus: 5.0.0\r\n"}),
+			want: []domain.MailBounce{{Email: "old@example.org", Kind: domain.BounceHard, Reason: "5.0.0"}},
+		},
+		{
+			name: "complaint with original recipient",
+			msg: report("feedback-report", humanPart, [2]string{"message/feedback-report",
+				"Feedback-Type: abuse\r\nUser-Agent: ExampleFBL/1.0\r\nVersion: 1\r\n" +
+					"Original-Rcpt-To: <reader@example.org>\r\n"}),
+			want: []domain.MailBounce{{Email: "reader@example.org", Kind: domain.BounceComplaint, Reason: "complaint: abuse"}},
+		},
+		{
+			name: "complaint addressed from the original message",
+			msg: report("feedback-report", humanPart,
+				[2]string{"message/feedback-report", "Feedback-Type: abuse\r\nVersion: 1\r\n"},
+				[2]string{"text/rfc822-headers", "From: conf@conf.example.org\r\nTo: Reader <Reader@Example.org>\r\nSubject: News"}),
+			want: []domain.MailBounce{{Email: "reader@example.org", Kind: domain.BounceComplaint, Reason: "complaint: abuse"}},
+		},
+		{
+			name: "complaint without any address",
+			msg: report("feedback-report", humanPart,
+				[2]string{"message/feedback-report", "Feedback-Type: abuse\r\nVersion: 1\r\n"}),
+		},
+		{
+			name: "auto-reply",
+			msg:  "From: reader@example.org\r\nSubject: Out of office\r\nContent-Type: text/plain\r\n\r\nBack on Monday.\r\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseBounceReport(strings.NewReader(tc.msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("bounces = %+v, want %+v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("bounce %d = %+v, want %+v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseBounceReportMalformed(t *testing.T) {
+	if _, err := ParseBounceReport(strings.NewReader("no headers here")); err == nil {
+		t.Error("want an error for a message without a header block")
+	}
+}
@@ -0,0 +1,97 @@
+package mail
+
+import "confsite/backend/internal/domain"
+
+// catalog lists every notification with the variables its templates receive.
+// A new email needs an entry here and files in both languages.
+var catalog = []domain.EmailTemplateDef{
+	{Name: "verify_email", Description: "Link to confirm the address after sign-up", Variables: []domain.EmailTemplateVariable{
+		{Name: "VerifyURL", Description: "Confirmation link", Sample: "https://example.org/verify-email?token=sample"},
+	}},
+	{Name: "welcome_email", Description: "Sent once the email address is confirmed", Variables: []domain.EmailTemplateVariable{}},
+	{Name: "registration_received", Description: "Participant submitted the registration form", Variables: []domain.EmailTemplateVariable{}},
+	{Name: "status_approved", Description: "Registration approved", Variables: []domain.EmailTemplateVariable{}},
+	{Name: "status_rejected", Description: "Registration rejected", Variables: []domain.EmailTemplateVariable{}},
+	{Name: "waitlisted", Description: "Approval did not fit the capacity", Variables: []domain.EmailTemplateVariable{
+		{Name: "Position", Description: "Place on the waitlist", Sample: 3},
+	}},
+	{Name: "waitlist_promoted", Description: "Waitlisted participant got a place", Variables: []domain.EmailTemplateVariable{}},
+	{Name: "talk_approved", Description: "Talk approved by the program committee", Variables: []domain.EmailTemplateVariable{
+		{Name: "TalkTitle", Description: "Title of the talk", Sample: "Plasma diagnostics with laser scattering"},
+	}},
+	{Name: "talk_rejected", Description: "Talk rejected by the program committee", Variables: []domain.EmailTemplateVariable{
+		{Name: "TalkTitle", Description: "Title of the talk", Sample: "Plasma diagnostics with laser scattering"},
+	}},
+	{Name: "talk_file_uploaded", Description: "Speaker uploaded the thesis file", Variables: []domain.EmailTemplateVariable{
+		{Name: "TalkTitle", Description: "Title of the talk", Sample: "Plasma diagnostics with laser scattering"},
+	}},
+	{Name: "missing_items_reminder", Description: "Scheduled reminder about missing consents, license agreements and talk files", Variables: []domain.EmailTemplateVariable{
+		{Name: "MissingConsents", Description: "Consent scans not uploaded", Sample: true},
+		{Name: "LicenseTalks", Description: "Approved talks without a signed license agreement", Sample: []string{"Plasma diagnostics with laser scattering"}},
+		{Name: "FileTalks", Description: "Approved talks without a thesis file", Sample: []string{"Plasma diagnostics with laser scattering"}},
+		{Name: "CabinetURL", Description: "Link to the personal account", Sample: "https://example.org/cabinet"},
+	}},
+	{Name: "org_new_registration", Description: "Organizers: new registration", Variables: []domain.EmailTemplateVariable{
+		{Name: "FullName", Description: "Participant's full name", Sample: "Ivanov Ivan Ivanovich"},
+		{Name: "Affiliation", Description: "Organization", Sample: "Institute of Physics"},
+		{Name: "City", Description: "City", Sample: "Kazan"},
+		{Name: "Email", Description: "Participant's email", Sample: "ivanov@example.org"},
+	}},
+	{Name: "org_registration_cancelled", Description: "Organizers: participant cancelled the registration", Variables: []domain.EmailTemplateVariable{
+		{Name: "FullName", Description: "Participant's full name", Sample: "Ivanov Ivan Ivanovich"},
+		{Name: "Email", Description: "Participant's email", Sample: "ivanov@example.org"},
+		{Name: "Reason", Description: "Reason given by the participant", Sample: "Cannot travel"},
+	}},
+	{Name: "org_talk_withdrawn", Description: "Organizers: speaker withdrew a talk", Variables: []domain.EmailTemplateVariable{
+		{Name: "Title", Description: "Title of the talk", Sample: "Plasma diagnostics with laser scattering"},
+		{Name: "Section", Description: "Section title", Sample: "Plasma diagnostics"},
+		{Name: "Speaker", Description: "Speaker's full name", Sample: "Ivanov Ivan Ivanovich"},
+		{Name: "Reason", Description: "Reason given by the speaker", Sample: "Results not ready"},
+	}},
+	{Name: "org_talk_file_uploaded", Description: "Section responsibles: talk thesis ready for review", Variables: []domain.EmailTemplateVariable{
+		{Name: "SpeakerFullName", Description: "Speaker's full name", Sample: "Ivanov Ivan Ivanovich"},
+		{Name: "SpeakerAffiliation", Description: "Speaker's organization", Sample: "Institute of Physics"},
+		{Name: "SpeakerCity", Description: "Speaker's city", Sample: "Kazan"},
+		{Name: "Title", Description: "Title of the talk", Sample: "Plasma diagnostics with laser scattering"},
+		{Name: "AuthorsLine", Description: "Authors with affiliations", Sample: "I. Ivanov (Institute of Physics); P. Petrov (University)"},
+		{Name: "Abstract", Description: "Abstract", Sample: "We report on..."},
+		{Name: "Kind", Description: "PLENARY, ORAL or POSTER", Sample: "ORAL"},
+		{Name: "Section", Description: "Section title", Sample: "Plasma diagnostics"},
+		{Name: "FileNoteOrURL", Description: "Link to the thesis file", Sample: "https://example.org/files/thesis.pdf"},
+	}},
+	{Name: "responsible_digest", Description: "Section responsibles on the daily digest: new, revised and pending talks", Variables: []domain.EmailTemplateVariable{
+		{Name: "Since", Description: "Start of the period, UTC", Sample: "2026-01-01 08:00 UTC"},
+		{Name: "NewTalks", Description: "Talks submitted in the period; each has Title, Speaker, Section, Kind and URL", Sample: sampleDigestTalks},
+		{Name: "RevisedTalks", Description: "Talks edited or with a new file in the period", Sample: sampleDigestTalks},
+		{Name: "PendingTalks", Description: "Talks waiting for a decision", Sample: sampleDigestTalks},
+		{Name: "AdminURL", Description: "Link to the talks in the admin panel", Sample: "https://example.org/admin/talks"},
+	}},
+	{Name: "account_locked", Description: "Too many failed sign-ins", Variables: []domain.EmailTemplateVariable{
+		{Name: "LockedUntil", Description: "End of the lockout, UTC", Sample: "2026-01-01 12:00 UTC"},
+	}},
+	{Name: "new_device_login", Description: "Sign-in from a new device", Variables: []domain.EmailTemplateVariable{
+		{Name: "LoginAt", Description: "Time of the sign-in, UTC", Sample: "2026-01-01 12:00 UTC"},
+		{Name: "IP", Description: "Client IP address", Sample: "203.0.113.7"},
+		{Name: "UserAgent", Description: "Browser user agent", Sample: "Mozilla/5.0"},
+	}},
+	{Name: "email_change_confirm", Description: "Sent to the new address to confirm a change", Variables: []domain.EmailTemplateVariable{
+		{Name: "NewEmail", Description: "Requested address", Sample: "new@example.org"},
+		{Name: "ConfirmURL", Description: "Confirmation link", Sample: "https://example.org/email-change/confirm?token=sample"},
+	}},
+	{Name: "email_change_notice", Description: "Sent to the old address when a change is requested", Variables: []domain.EmailTemplateVariable{
+		{Name: "NewEmail", Description: "Requested address", Sample: "new@example.org"},
+		{Name: "CancelURL", Description: "Link that cancels the change", Sample: "https://example.org/email-change/cancel?token=sample"},
+	}},
+}
+
+var sampleDigestTalks = []map[string]any{{
+	"Title":   "Plasma diagnostics with laser scattering",
+	"Speaker": "Ivanov Ivan Ivanovich",
+	"Section": "Plasma diagnostics",
+	"Kind":    "ORAL",
+	"URL":     "https://example.org/admin/talks?section=sample",
+}}
+
+func (t *Templates) Catalog() []domain.EmailTemplateDef {
+	return catalog
+}
@@ -0,0 +1,174 @@
+package mail
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// dkimHeaders are signed when present in the message.
+var dkimHeaders = []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type"}
+
+// DKIMSigner adds an RFC 6376 DKIM-Signature (relaxed/relaxed) with an RSA
+// (rsa-sha256) or Ed25519 (ed25519-sha256, RFC 8463) key. The public key is
+// published as a TXT record at <selector>._domainkey.<domain>.
+type DKIMSigner struct {
+	domain   string
+	selector string
+	key      crypto.Signer
+	algo     string
+	hash     crypto.Hash
+}
+
+func NewDKIMSigner(domain, selector string, key crypto.Signer) (*DKIMSigner, error) {
+	if domain == "" || selector == "" {
+		return nil, errors.New("dkim: domain and selector are required")
+	}
+	s := &DKIMSigner{domain: strings.ToLower(domain), selector: selector, key: key}
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		s.algo, s.hash = "rsa-sha256", crypto.SHA256
+	case ed25519.PrivateKey:
+		s.algo = "ed25519-sha256"
+	default:
+		return nil, fmt.Errorf("dkim: unsupported key type %T", key)
+	}
+	return s, nil
+}
+
+// LoadDKIMSigner reads a PKCS#1 or PKCS#8 PEM private key.
+func LoadDKIMSigner(domain, selector, keyFile string) (*DKIMSigner, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("dkim: no PEM block in " + keyFile)
+	}
+	var parsed any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("dkim: unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("dkim: unsupported key type %T", parsed)
+	}
+	return NewDKIMSigner(domain, selector, key)
+}
+
+// Sign returns msg with a DKIM-Signature header prepended. msg must use CRLF
+// line endings, as buildMessage produces.
+func (s *DKIMSigner) Sign(msg []byte, now time.Time) ([]byte, error) {
+	head, body, ok := bytes.Cut(msg, []byte("\r\n\r\n"))
+	if !ok {
+		return nil, errors.New("message has no header/body separator")
+	}
+	bodyHash := sha256.Sum256(relaxedBody(body))
+
+	fields := headerFields(string(head) + "\r\n")
+	h := sha256.New()
+	var signed []string
+	for _, name := range dkimHeaders {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fieldName(fields[i]) == strings.ToLower(name) {
+				h.Write([]byte(relaxedHeader(fields[i]) + "\r\n"))
+				signed = append(signed, strings.ToLower(name))
+				break
+			}
+		}
+	}
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		s.algo, s.domain, s.selector, now.Unix(), strings.Join(signed, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+	h.Write([]byte(relaxedHeader("DKIM-Signature: " + value)))
+
+	sig, err := s.key.Sign(rand.Reader, h.Sum(nil), s.hash)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(msg)+512)
+	out = append(out, "DKIM-Signature: "+value+base64.StdEncoding.EncodeToString(sig)+"\r\n"...)
+	return append(out, msg...), nil
+}
+
+// headerFields splits a header block into fields, keeping folded
+// continuation lines with their field.
+func headerFields(head string) []string {
+	var fields []string
+	for _, line := range strings.SplitAfter(head, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	return fields
+}
+
+func fieldName(field string) string {
+	name, _, _ := strings.Cut(field, ":")
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// relaxedHeader is the "relaxed" header canonicalization without the
+// trailing CRLF (RFC 6376 section 3.4.2).
+func relaxedHeader(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	value = strings.NewReplacer("\r\n", "").Replace(value)
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.TrimLeft(collapseWSP(value), " ")
+}
+
+// relaxedBody is the "relaxed" body canonicalization (RFC 6376 section 3.4.4).
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = collapseWSP(line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func collapseWSP(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,182 @@
+package mail
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Example message, keys and signature of RFC 8463 appendix A.
+const (
+	rfc8463Seed   = "nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A="
+	rfc8463Public = "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="
+	rfc8463BH     = "2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8="
+
+	rfc8463Headers = "From: Joe SixPack <joe@football.example.com>\r\n" +
+		"To: Suzie Q <suzie@shopping.example.net>\r\n" +
+		"Subject: Is dinner ready?\r\n" +
+		"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)\r\n" +
+		"Message-ID: <20030712040037.46341.5F8J@football.example.com>\r\n"
+	rfc8463Body = "Hi.\r\n\r\nWe lost the game.  Are you hungry yet?\r\n\r\nJoe.\r\n"
+
+	rfc8463Signature = "DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;\r\n" +
+		" d=football.example.com; i=@football.example.com;\r\n" +
+		" q=dns/txt; s=brisbane; t=1528637909; h=from : to :\r\n" +
+		" subject : date : message-id : from : subject : date;\r\n" +
+		" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;\r\n" +
+		" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus\r\n" +
+		" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw==\r\n"
+)
+
+func rfc8463Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	seed, err := base64.StdEncoding.DecodeString(rfc8463Seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func TestRelaxedCanonicalizationRFC6376Example(t *testing.T) {
+	// RFC 6376 section 3.4.5
+	fields := headerFields("A: X\r\nB : Y\t\r\n\tZ  \r\n")
+	var got []string
+	for _, f := range fields {
+		got = append(got, relaxedHeader(f))
+	}
+	if want := []string{"a:X", "b:Y Z"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("headers = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name, body, want string
+	}{
+		{"rfc example", " C \r\nD \t E\r\n\r\n\r\n", " C\r\nD E\r\n"},
+		{"empty", "", ""},
+		{"only blank lines", "\r\n\r\n", ""},
+		{"missing final crlf", "a  b", "a b\r\n"},
+	}
+	for _, tc := range tests {
+		if got := string(relaxedBody([]byte(tc.body))); got != tc.want {
+			t.Errorf("%s: body = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDKIMBodyHashRFC8463(t *testing.T) {
+	sum := sha256.Sum256(relaxedBody([]byte(rfc8463Body)))
+	if got := base64.StdEncoding.EncodeToString(sum[:]); got != rfc8463BH {
+		t.Errorf("bh = %s, want %s", got, rfc8463BH)
+	}
+}
+
+func TestDKIMVerifiesRFC8463Signature(t *testing.T) {
+	key := rfc8463Key(t)
+	pub := key.Public().(ed25519.PublicKey)
+	if got := base64.StdEncoding.EncodeToString(pub); got != rfc8463Public {
+		t.Fatalf("public key = %s, want %s", got, rfc8463Public)
+	}
+	msg := []byte(rfc8463Signature + rfc8463Headers + "\r\n" + rfc8463Body)
+	if err := verifyDKIM(msg, pub); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDKIMSignRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey := rfc8463Key(t)
+	msg := []byte(rfc8463Headers + "MIME-Version: 1.0\r\n\r\n" + rfc8463Body)
+	for _, key := range []crypto.Signer{rsaKey, edKey} {
+		s, err := NewDKIMSigner("Football.Example.com", "brisbane", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed, err := s.Sign(msg, time.Unix(1528637909, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := verifyDKIM(signed, key.Public()); err != nil {
+			t.Errorf("%s: %v", s.algo, err)
+		}
+		// a changed body or signed header must break the signature
+		tampered := strings.Replace(string(signed), "hungry", "thirsty", 1)
+		if verifyDKIM([]byte(tampered), key.Public()) == nil {
+			t.Errorf("%s: tampered body verified", s.algo)
+		}
+		tampered = strings.Replace(string(signed), "Is dinner ready?", "Is lunch ready?", 1)
+		if verifyDKIM([]byte(tampered), key.Public()) == nil {
+			t.Errorf("%s: tampered subject verified", s.algo)
+		}
+	}
+}
+
+var bTag = regexp.MustCompile(`(;\s*b=)[^;]*`)
+
+// verifyDKIM checks the first DKIM-Signature of msg following RFC 6376
+// section 3.7 (relaxed/relaxed only), independently of Sign.
+func verifyDKIM(msg []byte, pub crypto.PublicKey) error {
+	head, body, ok := strings.Cut(string(msg), "\r\n\r\n")
+	if !ok {
+		return errors.New("no header/body separator")
+	}
+	fields := headerFields(head + "\r\n")
+	if len(fields) == 0 || fieldName(fields[0]) != "dkim-signature" {
+		return errors.New("no DKIM-Signature first")
+	}
+	sigField := strings.TrimSuffix(fields[0], "\r\n")
+	tags := map[string]string{}
+	_, value, _ := strings.Cut(sigField, ":")
+	for _, tag := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(tag, "=")
+		tags[strings.TrimSpace(k)] = strings.Join(strings.Fields(v), "")
+	}
+	if tags["c"] != "relaxed/relaxed" {
+		return errors.New("unexpected canonicalization " + tags["c"])
+	}
+
+	bh := sha256.Sum256(relaxedBody([]byte(body)))
+	if base64.StdEncoding.EncodeToString(bh[:]) != tags["bh"] {
+		return errors.New("body hash mismatch")
+	}
+
+	// h= picks header instances from the bottom up; a name listed more often
+	// than present signs nothing for the extra entries.
+	h := sha256.New()
+	used := map[int]bool{}
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(fields) - 1; i > 0; i-- {
+			if !used[i] && fieldName(fields[i]) == strings.ToLower(name) {
+				used[i] = true
+				h.Write([]byte(relaxedHeader(fields[i]) + "\r\n"))
+				break
+			}
+		}
+	}
+	h.Write([]byte(relaxedHeader(bTag.ReplaceAllString(sigField, "$1"))))
+
+	sig, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return err
+	}
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, h.Sum(nil), sig) {
+			return errors.New("ed25519 signature mismatch")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h.Sum(nil), sig)
+	}
+	return errors.New("unsupported key")
+}
@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"confsite/backend/internal/ports"
+)
+
+// FailoverTransport tries its transports in order, e.g. the primary SMTP
+// relay and then a secondary one, and fails only when all of them do.
+type FailoverTransport struct {
+	transports []ports.MailTransport
+}
+
+func NewFailoverTransport(transports ...ports.MailTransport) ports.MailTransport {
+	return &FailoverTransport{transports: transports}
+}
+
+func (t *FailoverTransport) Name() string { return "failover" }
+
+func (t *FailoverTransport) Deliver(ctx context.Context, from string, to []string, msg []byte) error {
+	var errs []error
+	for i, tr := range t.transports {
+		err := tr.Deliver(ctx, from, to, msg)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", tr.Name(), err))
+		if i < len(t.transports)-1 {
+			println("Warning: mail transport", tr.Name(), "failed, trying the next one:", err.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
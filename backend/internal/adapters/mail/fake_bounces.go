@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// FakeBounces is an in-memory bounce source for local development and tests:
+// reports queued with Add are returned by the next Fetch.
+type FakeBounces struct {
+	mu    sync.Mutex
+	queue []domain.MailBounce
+}
+
+func NewFakeBounces() *FakeBounces {
+	return &FakeBounces{}
+}
+
+func (f *FakeBounces) Name() string { return "fake" }
+
+func (f *FakeBounces) Add(b ...domain.MailBounce) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queue = append(f.queue, b...)
+}
+
+func (f *FakeBounces) Fetch(ctx context.Context) ([]domain.MailBounce, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := f.queue
+	f.queue = nil
+	for i := range out {
+		out[i].Source = f.Name()
+	}
+	return out, nil
+}
+
+var _ ports.BounceSource = (*FakeBounces)(nil)
@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+
+	"confsite/backend/internal/ports"
+)
+
+// FileTransport writes each message as an .eml file instead of sending it,
+// for development and staging. Envelope recipients are recorded in an
+// X-Envelope-To header.
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) ports.MailTransport {
+	return &FileTransport{dir: dir}
+}
+
+func (t *FileTransport) Name() string { return "file " + t.dir }
+
+func (t *FileTransport) Deliver(ctx context.Context, from string, to []string, msg []byte) error {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	name := time.Now().UTC().Format("20060102T150405.000000000") + "-" + hex.EncodeToString(b) + ".eml"
+	out := []byte("Return-Path: <" + from + ">\r\n")
+	for _, rcpt := range to {
+		out = append(out, "X-Envelope-To: "+rcpt+"\r\n"...)
+	}
+	out = append(out, msg...)
+	// write under a temporary name so readers never see a partial file
+	tmp := filepath.Join(t.dir, "."+name)
+	if err := os.WriteFile(tmp, out, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(t.dir, name))
+}
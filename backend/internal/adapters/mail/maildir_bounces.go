@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// MaildirBounces polls a Maildir that receives mail for the envelope sender
+// (Return-Path) address. Processed messages are moved from new/ to cur/ so
+// each report is read once.
+type MaildirBounces struct {
+	dir string
+}
+
+func NewMaildirBounces(dir string) ports.BounceSource {
+	return &MaildirBounces{dir: dir}
+}
+
+func (m *MaildirBounces) Name() string { return "maildir" }
+
+func (m *MaildirBounces) Fetch(ctx context.Context) ([]domain.MailBounce, error) {
+	entries, err := os.ReadDir(filepath.Join(m.dir, "new"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	var out []domain.MailBounce
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(m.dir, "new", e.Name())
+		bounces, err := m.read(path)
+		if err != nil {
+			println("Warning: failed to parse bounce message", path, ":", err.Error())
+		}
+		out = append(out, bounces...)
+		if err := os.Rename(path, filepath.Join(m.dir, "cur", e.Name()+":2,S")); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+func (m *MaildirBounces) read(path string) ([]domain.MailBounce, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	bounces, err := ParseBounceReport(f)
+	for i := range bounces {
+		bounces[i].Source = m.Name()
+		bounces[i].ReceivedAt = info.ModTime()
+	}
+	return bounces, err
+}
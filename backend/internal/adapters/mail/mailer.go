@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/ports"
+)
+
+// Mailer composes outgoing messages, signs them with DKIM when a signer is
+// configured and hands them to the transport.
+type Mailer struct {
+	from      string
+	transport ports.MailTransport
+	dkim      *DKIMSigner
+}
+
+// NewMailer: dkim may be nil to send unsigned mail.
+func NewMailer(from string, transport ports.MailTransport, dkim *DKIMSigner) ports.Mailer {
+	return &Mailer{from: from, transport: transport, dkim: dkim}
+}
+
+func (m *Mailer) Send(ctx context.Context, to, subject, html, text string) error {
+	if m.from == "" {
+		return fmt.Errorf("mail sender is not configured")
+	}
+	// A template that failed to render leaves everything empty.
+	if strings.TrimSpace(subject) == "" || strings.TrimSpace(html+text) == "" {
+		return fmt.Errorf("refusing to send an empty message")
+	}
+	now := time.Now()
+	msg, err := buildMessage(m.from, to, subject, html, text, now)
+	if err != nil {
+		return err
+	}
+	if m.dkim != nil {
+		if msg, err = m.dkim.Sign(msg, now); err != nil {
+			return fmt.Errorf("dkim: %w", err)
+		}
+	}
+	return m.transport.Deliver(ctx, envelopeAddress(m.from), []string{to}, msg)
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/ports"
+)
+
+// SendmailTransport pipes messages to a sendmail-compatible binary
+// (sendmail, msmtp, postfix's sendmail).
+type SendmailTransport struct {
+	path string
+}
+
+func NewSendmailTransport(path string) ports.MailTransport {
+	if path == "" {
+		path = "/usr/sbin/sendmail"
+	}
+	return &SendmailTransport{path: path}
+}
+
+func (t *SendmailTransport) Name() string { return "sendmail " + t.path }
+
+func (t *SendmailTransport) Deliver(ctx context.Context, from string, to []string, msg []byte) error {
+	_ = ctx // like SMTP, delivery outlives the HTTP request
+
+	runCtx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+	args := append([]string{"-i", "-f", from, "--"}, to...)
+	cmd := exec.CommandContext(runCtx, t.path, args...)
+	cmd.Stdin = bytes.NewReader(msg)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("sendmail error: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
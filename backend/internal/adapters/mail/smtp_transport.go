@@ -11,32 +11,28 @@ import (
 	"confsite/backend/internal/ports"
 )
 
-type SMTPMailer struct {
+// SMTPTransport submits messages to an SMTP relay: implicit TLS on port 465,
+// STARTTLS when offered otherwise.
+type SMTPTransport struct {
 	host string
 	port int
-	from string
 	user string
 	pass string
 }
 
-func NewSMTPMailer(host string, port int, from, user, pass string) ports.Mailer {
-	return &SMTPMailer{host, port, from, user, pass}
+func NewSMTPTransport(host string, port int, user, pass string) ports.MailTransport {
+	return &SMTPTransport{host, port, user, pass}
 }
 
-func (m *SMTPMailer) Send(ctx context.Context, to, subject, html, text string) error {
+func (m *SMTPTransport) Name() string { return fmt.Sprintf("smtp %s:%d", m.host, m.port) }
+
+func (m *SMTPTransport) Deliver(ctx context.Context, from string, to []string, msg []byte) error {
 	_ = ctx // do not bind SMTP lifetime to HTTP request context
 
-	if m.host == "" || m.port == 0 || m.from == "" {
+	if m.host == "" || m.port == 0 {
 		return fmt.Errorf("smtp is not configured")
 	}
-	if text == "" {
-		text = html
-	}
 
-	msg := []byte(fmt.Sprintf(
-		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
-		m.from, to, subject, text,
-	))
 	addr := fmt.Sprintf("%s:%d", m.host, m.port)
 
 	// Keep bounded deadline for SMTP operations even when caller provides no deadline.
@@ -99,11 +95,13 @@ func (m *SMTPMailer) Send(ctx context.Context, to, subject, html, text string) e
 		}
 	}
 
-	if err = client.Mail(m.from); err != nil {
+	if err = client.Mail(from); err != nil {
 		return fmt.Errorf("mail error: %w", err)
 	}
-	if err = client.Rcpt(to); err != nil {
-		return fmt.Errorf("rcpt error: %w", err)
+	for _, rcpt := range to {
+		if err = client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("rcpt error: %w", err)
+		}
 	}
 	w, err := client.Data()
 	if err != nil {
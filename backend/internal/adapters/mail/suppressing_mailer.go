@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"context"
+
+	"confsite/backend/internal/ports"
+)
+
+// SuppressingMailer drops mail to addresses that hard-bounced. It wraps the
+// mailer of non-critical notifications; sign-in, verification and email
+// change messages use the plain mailer so users can still recover.
+type SuppressingMailer struct {
+	next    ports.Mailer
+	bounces ports.BounceRepo
+}
+
+func NewSuppressingMailer(next ports.Mailer, bounces ports.BounceRepo) ports.Mailer {
+	return &SuppressingMailer{next: next, bounces: bounces}
+}
+
+func (m *SuppressingMailer) Send(ctx context.Context, to, subject, html, text string) error {
+	undeliverable, err := m.bounces.Undeliverable(ctx, to)
+	if err != nil {
+		println("Warning: failed to check bounce status of", to, ":", err.Error())
+	}
+	if undeliverable {
+		println("Warning: suppressed email to undeliverable address", to, ":", subject)
+		return nil
+	}
+	return m.next.Send(ctx, to, subject, html, text)
+}
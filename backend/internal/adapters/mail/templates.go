@@ -2,53 +2,129 @@
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"html/template"
 	"os"
 	"path/filepath"
 	"strings"
 	texttmpl "text/template"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
 )
 
+// Templates renders notification emails. Templates edited by admins are read
+// from the store; anything not overridden comes from the files under root:
+// <lang>/<name>.subject, .html and .txt.
 type Templates struct {
-	root string // e.g. /app/templates/email
+	root  string // e.g. /app/templates/email
+	store ports.EmailTemplateRepo
 }
 
-func NewTemplates(root string) *Templates {
-	return &Templates{root: root}
+func NewTemplates(root string, store ports.EmailTemplateRepo) *Templates {
+	return &Templates{root: root, store: store}
 }
 
-func (t *Templates) renderHTML(lang, name string, data any) (string, error) {
-	p := filepath.Join(t.root, lang, name+".html")
-	b, err := os.ReadFile(p)
-	if err != nil {
-		return "", err
+// File returns the built-in template shipped with the application.
+func (t *Templates) File(name, lang string) (domain.EmailTemplate, error) {
+	out := domain.EmailTemplate{Name: name, Lang: lang}
+	parts := []struct {
+		ext string
+		dst *string
+	}{{".subject", &out.Subject}, {".html", &out.HTML}, {".txt", &out.Text}}
+	for _, p := range parts {
+		b, err := os.ReadFile(filepath.Join(t.root, lang, name+p.ext))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return out, domain.ErrNotFound
+			}
+			return out, err
+		}
+		*p.dst = strings.TrimPrefix(string(b), "\ufeff")
+	}
+	return out, nil
+}
+
+// Current returns the active stored version, or the file when there is none.
+func (t *Templates) Current(ctx context.Context, name, lang string) (domain.EmailTemplate, error) {
+	if t.store != nil {
+		tpl, err := t.store.Active(ctx, name, lang)
+		if err == nil {
+			return *tpl, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			println("Warning: failed to load email template", name, lang, ":", err.Error())
+		}
 	}
-	tpl, err := template.New(name).Parse(string(b))
+	return t.File(name, lang)
+}
+
+// Render executes tpl strictly: a variable missing from data is an error.
+func (t *Templates) Render(tpl domain.EmailTemplate, data map[string]any) (string, string, string, error) {
+	return render(tpl, data, "missingkey=error")
+}
+
+// render is used for sending. A stored template that fails is logged and
+// replaced by the file so the message still goes out. When the file fails too
+// all parts are empty, which the Mailer refuses to send.
+func (t *Templates) render(lang, name string, data map[string]any) (string, string, string) {
+	if data == nil {
+		data = map[string]any{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tpl, err := t.Current(ctx, name, lang)
+	if err == nil {
+		var subj, html, text string
+		subj, html, text, err = render(tpl, data, "missingkey=default")
+		if err == nil {
+			return subj, html, text
+		}
+	}
+	println("Warning: email template", name, lang, "failed:", err.Error())
+	if tpl.Version == 0 {
+		return "", "", ""
+	}
+	file, err := t.File(name, lang)
 	if err != nil {
-		return "", err
+		println("Warning: email template", name, lang, "has no file fallback:", err.Error())
+		return "", "", ""
 	}
-	var out bytes.Buffer
-	if err := tpl.Execute(&out, data); err != nil {
-		return "", err
+	subj, html, text, err := render(file, data, "missingkey=default")
+	if err != nil {
+		println("Warning: email template", name, lang, "file fallback failed:", err.Error())
+		return "", "", ""
 	}
-	return out.String(), nil
+	return subj, html, text
 }
 
-func (t *Templates) renderText(lang, name string, data any) (string, error) {
-	p := filepath.Join(t.root, lang, name+".txt")
-	b, err := os.ReadFile(p)
+func render(tpl domain.EmailTemplate, data map[string]any, missingKey string) (string, string, string, error) {
+	var subj, txt, html bytes.Buffer
+	st, err := texttmpl.New(tpl.Name + ".subject").Option(missingKey).Parse(tpl.Subject)
 	if err != nil {
-		return "", err
+		return "", "", "", err
+	}
+	if err := st.Execute(&subj, data); err != nil {
+		return "", "", "", err
+	}
+	ht, err := template.New(tpl.Name + ".html").Option(missingKey).Parse(tpl.HTML)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := ht.Execute(&html, data); err != nil {
+		return "", "", "", err
 	}
-	tpl, err := texttmpl.New(name).Parse(string(b))
+	tt, err := texttmpl.New(tpl.Name + ".txt").Option(missingKey).Parse(tpl.Text)
 	if err != nil {
-		return "", err
+		return "", "", "", err
 	}
-	var out bytes.Buffer
-	if err := tpl.Execute(&out, data); err != nil {
-		return "", err
+	if err := tt.Execute(&txt, data); err != nil {
+		return "", "", "", err
 	}
-	return out.String(), nil
+	// subjects are single-line
+	return strings.Join(strings.Fields(subj.String()), " "), html.String(), txt.String(), nil
 }
 
 func safeLang(lang string) string {
@@ -1,7 +1,7 @@
 package mail
 
 import (
-	"fmt"
+	"time"
 
 	"confsite/backend/internal/app/services"
 )
@@ -15,90 +15,90 @@ func NewTemplatesService(t *Templates) *TemplatesService {
 }
 
 func (s *TemplatesService) VerifyEmail(lang string, verifyURL string) (string, string, string) {
-	lang = safeLang(lang)
 	data := map[string]any{"VerifyURL": verifyURL}
-	html, _ := s.t.renderHTML(lang, "verify_email", data)
-	txt, _ := s.t.renderText(lang, "verify_email", data)
-
-	subj := "Подтверждение email"
-	if lang == "en" {
-		subj = "Verify your email"
-	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "verify_email", data)
 }
 
 func (s *TemplatesService) WelcomeEmail(lang string) (string, string, string) {
-	lang = safeLang(lang)
-	html, _ := s.t.renderHTML(lang, "welcome_email", nil)
-	txt, _ := s.t.renderText(lang, "welcome_email", nil)
-	subj := "Добро пожаловать!"
-	if lang == "en" {
-		subj = "Welcome!"
-	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "welcome_email", nil)
 }
 
 func (s *TemplatesService) RegistrationReceived(lang string) (string, string, string) {
-	lang = safeLang(lang)
-	html, _ := s.t.renderHTML(lang, "registration_received", nil)
-	txt, _ := s.t.renderText(lang, "registration_received", nil)
-	subj := "Заявка получена"
-	if lang == "en" {
-		subj = "Application received"
-	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "registration_received", nil)
 }
 
 func (s *TemplatesService) StatusApproved(lang string) (string, string, string) {
-	lang = safeLang(lang)
-	html, _ := s.t.renderHTML(lang, "status_approved", nil)
-	txt, _ := s.t.renderText(lang, "status_approved", nil)
-	subj := "Заявка одобрена"
-	if lang == "en" {
-		subj = "Application approved"
-	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "status_approved", nil)
 }
 
 func (s *TemplatesService) StatusRejected(lang string) (string, string, string) {
-	lang = safeLang(lang)
-	html, _ := s.t.renderHTML(lang, "status_rejected", nil)
-	txt, _ := s.t.renderText(lang, "status_rejected", nil)
-	subj := "Заявка отклонена"
-	if lang == "en" {
-		subj = "Application rejected"
-	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "status_rejected", nil)
+}
+
+func (s *TemplatesService) Waitlisted(lang string, position int) (string, string, string) {
+	data := map[string]any{"Position": position}
+	return s.t.render(safeLang(lang), "waitlisted", data)
+}
+
+func (s *TemplatesService) WaitlistPromoted(lang string) (string, string, string) {
+	return s.t.render(safeLang(lang), "waitlist_promoted", nil)
 }
 
 func (s *TemplatesService) OrgNewRegistration(lang string, fullName, affiliation, city, email string) (string, string, string) {
-	lang = safeLang(lang)
 	data := map[string]any{
 		"FullName": fullName, "Affiliation": affiliation, "City": city, "Email": email,
 	}
-	html, _ := s.t.renderHTML(lang, "org_new_registration", data)
-	txt, _ := s.t.renderText(lang, "org_new_registration", data)
-	subj := "Новая заявка"
-	if lang == "en" {
-		subj = "New application"
+	return s.t.render(safeLang(lang), "org_new_registration", data)
+}
+
+func (s *TemplatesService) OrgRegistrationCancelled(lang string, fullName, email, reason string) (string, string, string) {
+	data := map[string]any{"FullName": fullName, "Email": email, "Reason": reason}
+	return s.t.render(safeLang(lang), "org_registration_cancelled", data)
+}
+
+func (s *TemplatesService) OrgTalkWithdrawn(lang string, title, section, speaker, reason string) (string, string, string) {
+	data := map[string]any{"Title": title, "Section": section, "Speaker": speaker, "Reason": reason}
+	return s.t.render(safeLang(lang), "org_talk_withdrawn", data)
+}
+
+func (s *TemplatesService) AccountLocked(lang string, lockedUntil time.Time) (string, string, string) {
+	data := map[string]any{"LockedUntil": lockedUntil.UTC().Format("2006-01-02 15:04 UTC")}
+	return s.t.render(safeLang(lang), "account_locked", data)
+}
+
+func (s *TemplatesService) NewDeviceLogin(lang string, loginAt time.Time, ip, userAgent string) (string, string, string) {
+	data := map[string]any{
+		"LoginAt": loginAt.UTC().Format("2006-01-02 15:04 UTC"), "IP": ip, "UserAgent": userAgent,
 	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "new_device_login", data)
+}
+
+func (s *TemplatesService) EmailChangeConfirm(lang string, newEmail, confirmURL string) (string, string, string) {
+	data := map[string]any{"NewEmail": newEmail, "ConfirmURL": confirmURL}
+	return s.t.render(safeLang(lang), "email_change_confirm", data)
+}
+
+func (s *TemplatesService) EmailChangeNotice(lang string, newEmail, cancelURL string) (string, string, string) {
+	data := map[string]any{"NewEmail": newEmail, "CancelURL": cancelURL}
+	return s.t.render(safeLang(lang), "email_change_notice", data)
+}
+
+func (s *TemplatesService) TalkApproved(lang string, talkTitle string) (string, string, string) {
+	data := map[string]any{"TalkTitle": talkTitle}
+	return s.t.render(safeLang(lang), "talk_approved", data)
+}
+
+func (s *TemplatesService) TalkRejected(lang string, talkTitle string) (string, string, string) {
+	data := map[string]any{"TalkTitle": talkTitle}
+	return s.t.render(safeLang(lang), "talk_rejected", data)
 }
 
 func (s *TemplatesService) TalkFileUploadedToUser(lang string, talkTitle string) (string, string, string) {
-	lang = safeLang(lang)
 	data := map[string]any{"TalkTitle": talkTitle}
-	html, _ := s.t.renderHTML(lang, "talk_file_uploaded", data)
-	txt, _ := s.t.renderText(lang, "talk_file_uploaded", data)
-	subj := "Загружены тезисы"
-	if lang == "en" {
-		subj = "Thesis uploaded"
-	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "talk_file_uploaded", data)
 }
 
 func (s *TemplatesService) OrgTalkFileUploaded(lang string, payload services.OrgTalkUploadedPayload) (string, string, string) {
-	lang = safeLang(lang)
 	data := map[string]any{
 		"SpeakerFullName":    payload.SpeakerFullName,
 		"SpeakerAffiliation": payload.SpeakerAffiliation,
@@ -110,15 +110,31 @@ func (s *TemplatesService) OrgTalkFileUploaded(lang string, payload services.Org
 		"Section":            payload.Section,
 		"FileNoteOrURL":      payload.FileNoteOrURL,
 	}
-	html, _ := s.t.renderHTML(lang, "org_talk_file_uploaded", data)
-	txt, _ := s.t.renderText(lang, "org_talk_file_uploaded", data)
-	subj := "Тезисы загружены (доклад)"
-	if lang == "en" {
-		subj = "Thesis uploaded (talk)"
+	return s.t.render(safeLang(lang), "org_talk_file_uploaded", data)
+}
+
+func (s *TemplatesService) MissingItemsReminder(lang string, payload services.ReminderPayload) (string, string, string) {
+	data := map[string]any{
+		"MissingConsents": payload.MissingConsents,
+		"LicenseTalks":    payload.LicenseTalks,
+		"FileTalks":       payload.FileTalks,
+		"CabinetURL":      payload.CabinetURL,
 	}
-	return subj, html, txt
+	return s.t.render(safeLang(lang), "missing_items_reminder", data)
 }
 
-var _ services.EmailTemplates = (*TemplatesService)(nil)
+func (s *TemplatesService) ResponsibleDigest(lang string, payload services.DigestPayload) (string, string, string) {
+	data := map[string]any{
+		"Since":        payload.Since.UTC().Format("2006-01-02 15:04 UTC"),
+		"NewTalks":     payload.NewTalks,
+		"RevisedTalks": payload.RevisedTalks,
+		"PendingTalks": payload.PendingTalks,
+		"AdminURL":     payload.AdminURL,
+	}
+	return s.t.render(safeLang(lang), "responsible_digest", data)
+}
 
-func fmtStr(s string) string { return fmt.Sprintf("%v", s) }
+var (
+	_ services.EmailTemplates      = (*TemplatesService)(nil)
+	_ services.EmailTemplateEngine = (*Templates)(nil)
+)
@@ -0,0 +1,79 @@
+// Package scheduler runs periodic background jobs inside the API process.
+// Each run takes a named lock so that only one instance executes a job at a time.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"confsite/backend/internal/ports"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	locks ports.JobLocker
+	jobs  []job
+}
+
+func New(locks ports.JobLocker) *Scheduler {
+	return &Scheduler{locks: locks}
+}
+
+// Every registers run to be called once per interval. Jobs with a zero or
+// negative interval are disabled.
+func (s *Scheduler) Every(name string, interval time.Duration, run func(ctx context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start launches all jobs; they stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		go s.loop(ctx, j)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	// first run shortly after start-up, not in the middle of boot
+	timer := time.NewTimer(time.Minute)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		s.runOnce(ctx, j)
+		timer.Reset(j.interval)
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			println("Warning: job", j.name, "panicked")
+		}
+	}()
+	release, ok, err := s.locks.TryLock(ctx, "job:"+j.name)
+	if err != nil {
+		println("Warning: job", j.name, "lock failed:", err.Error())
+		return
+	}
+	if !ok {
+		return // another instance is running it
+	}
+	defer release()
+
+	runCtx, cancel := context.WithTimeout(ctx, j.interval)
+	defer cancel()
+	if err := j.run(runCtx); err != nil {
+		println("Warning: job", j.name, "failed:", err.Error())
+	}
+}
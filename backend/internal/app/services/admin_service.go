@@ -3,8 +3,6 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
-	"html"
 
 	"confsite/backend/internal/domain"
 	"confsite/backend/internal/ports"
@@ -13,16 +11,18 @@ import (
 )
 
 type AdminService struct {
-	cfg       AppConfig
-	users     ports.UserRepo
-	profiles  ports.ProfileRepo
-	talks     ports.TalkRepo
-	sections  ports.SectionRepo
-	news      ports.NewsRepo
-	pages     ports.PageRepo
-	audit     ports.AuditRepo
-	mailer    ports.Mailer
-	templates EmailTemplates
+	cfg        AppConfig
+	users      ports.UserRepo
+	profiles   ports.ProfileRepo
+	talks      ports.TalkRepo
+	sections   ports.SectionRepo
+	news       ports.NewsRepo
+	pages      ports.PageRepo
+	audit      ports.AuditRepo
+	waitlist   ports.WaitlistRepo
+	moderation ports.ModerationRepo
+	mailer     ports.Mailer
+	templates  EmailTemplates
 }
 
 func NewAdminService(
@@ -34,88 +34,74 @@ func NewAdminService(
 	nr ports.NewsRepo,
 	pr ports.PageRepo,
 	ar ports.AuditRepo,
+	wr ports.WaitlistRepo,
+	mr ports.ModerationRepo,
 	m ports.Mailer,
 	t EmailTemplates,
 ) *AdminService {
-	return &AdminService{cfg: cfg, users: u, profiles: p, talks: tr, sections: sr, news: nr, pages: pr, audit: ar, mailer: m, templates: t}
+	return &AdminService{cfg: cfg, users: u, profiles: p, talks: tr, sections: sr, news: nr, pages: pr, audit: ar, waitlist: wr, moderation: mr, mailer: m, templates: t}
 }
 
-func (s *AdminService) ListUsers(ctx context.Context) ([]domain.UserWithRoles, error) {
-	return s.users.ListUsers(ctx)
+// ListUsersDetailed returns one page of users with roles and profiles, and
+// the total number of users matching the filter.
+func (s *AdminService) ListUsersDetailed(ctx context.Context, f ports.UserListFilter) ([]domain.AdminUserRow, int, error) {
+	return s.users.ListAdmin(ctx, f)
 }
 
-// UserWithProfile is a lightweight composite used by admin APIs.
-type UserWithProfile struct {
-	User    domain.User
-	Roles   []domain.RoleAssignment
-	Profile *domain.Profile
-}
-
-func (s *AdminService) ListUsersDetailed(ctx context.Context) ([]UserWithProfile, error) {
-	users, err := s.users.ListUsers(ctx)
-	if err != nil {
-		return nil, err
-	}
-	out := make([]UserWithProfile, 0, len(users))
-	for _, u := range users {
-		var p *domain.Profile
-		// attempt to load profile; if missing, continue with nil profile
-		if s.profiles != nil {
-			prof, err := s.profiles.Get(ctx, u.ID)
-			if err == nil {
-				p = prof
-			}
-		}
-		out = append(out, UserWithProfile{User: u.User, Roles: u.Roles, Profile: p})
-	}
-	return out, nil
-}
-
-func (s *AdminService) SetUserStatus(ctx context.Context, userID uuid.UUID, status domain.UserStatus, _ string) error {
-	if status != domain.StatusApproved && status != domain.StatusRejected && status != domain.StatusWaiting {
-		return domain.ErrInvalidInput
+// SetUserStatus applies a moderation decision and returns the resulting
+// status: an approval beyond capacity puts the user on the waitlist instead.
+// Whenever an approved participant leaves, the waitlist is promoted.
+func (s *AdminService) SetUserStatus(ctx context.Context, userID uuid.UUID, status domain.UserStatus, _ string) (domain.UserStatus, error) {
+	if status != domain.StatusApproved && status != domain.StatusRejected && status != domain.StatusWaiting && status != domain.StatusWaitlisted {
+		return "", domain.ErrInvalidInput
 	}
 	u, _, err := s.users.ByID(ctx, userID)
 	if err != nil {
-		return err
+		return "", err
 	}
 	prevStatus := u.Status
+	if prevStatus == status {
+		return status, nil
+	}
 
-	if err := s.users.SetStatus(ctx, userID, status); err != nil {
-		return err
+	position := 0
+	switch status {
+	case domain.StatusApproved:
+		status, position, err = s.waitlist.Admit(ctx, userID, s.cfg.CapacityCategoryField)
+	case domain.StatusWaitlisted:
+		position, err = s.waitlist.Enqueue(ctx, userID)
+	default:
+		err = s.waitlist.Leave(ctx, userID, status)
+	}
+	if err != nil {
+		return "", err
 	}
 
-	// Notify user only when moderation decision is made for the first time.
-	if prevStatus == domain.StatusWaiting {
+	// Notify user only when moderation decision is made for the first time
+	// (leaving the waitlist counts as the decision for waitlisted users).
+	if prevStatus == domain.StatusWaiting || prevStatus == domain.StatusWaitlisted {
 		switch status {
 		case domain.StatusApproved:
-			subj, html, text := s.templates.StatusApproved("ru")
-			_, htmlEn, textEn := s.templates.StatusApproved("en")
-			if err := s.mailer.Send(
-				ctx,
-				u.Email,
-				subj+" / Application approved",
-				html+"<hr><p><strong>English</strong></p>"+htmlEn,
-				text+"\n\n--- English ---\n"+textEn,
-			); err != nil {
-				println("Warning: failed to send user status approved email to", u.Email, ":", err.Error())
+			if prevStatus == domain.StatusWaitlisted {
+				sendLocalized(ctx, s.mailer, u, s.templates.WaitlistPromoted)
+			} else {
+				sendLocalized(ctx, s.mailer, u, s.templates.StatusApproved)
 			}
 		case domain.StatusRejected:
-			subj, html, text := s.templates.StatusRejected("ru")
-			_, htmlEn, textEn := s.templates.StatusRejected("en")
-			if err := s.mailer.Send(
-				ctx,
-				u.Email,
-				subj+" / Application rejected",
-				html+"<hr><p><strong>English</strong></p>"+htmlEn,
-				text+"\n\n--- English ---\n"+textEn,
-			); err != nil {
-				println("Warning: failed to send user status rejected email to", u.Email, ":", err.Error())
+			sendLocalized(ctx, s.mailer, u, s.templates.StatusRejected)
+		case domain.StatusWaitlisted:
+			if prevStatus == domain.StatusWaiting {
+				sendLocalized(ctx, s.mailer, u, func(lang string) (string, string, string) {
+					return s.templates.Waitlisted(lang, position)
+				})
 			}
 		}
 	}
 
-	return nil
+	if prevStatus == domain.StatusApproved && status != domain.StatusApproved {
+		s.promoteWaitlist(ctx)
+	}
+	return status, nil
 }
 
 func (s *AdminService) AssignSectionAdmin(ctx context.Context, userID uuid.UUID, sectionID uuid.UUID) error {
@@ -158,46 +144,35 @@ func (s *AdminService) SetTalkStatus(ctx context.Context, talkID uuid.UUID, stat
 	if prev == domain.TalkStatusWaiting {
 		u, _, err := s.users.ByID(ctx, t.SpeakerUserID)
 		if err == nil {
-			sendTalkStatusEmail(ctx, s.mailer, u.Email, t.Title, status)
+			sendTalkStatusEmail(ctx, s.mailer, s.templates, u, t.Title, status)
 		}
 	}
 
 	return nil
 }
 
-func sendTalkStatusEmail(ctx context.Context, mailer ports.Mailer, email, talkTitle string, status domain.TalkStatus) {
-	var subj string
-	var text string
-	var htmlBody string
+func sendTalkStatusEmail(ctx context.Context, mailer ports.Mailer, templates EmailTemplates, u *domain.User, talkTitle string, status domain.TalkStatus) {
+	m, ok := talkStatusEmail(templates, u, talkTitle, status)
+	if !ok {
+		return
+	}
+	if err := mailer.Send(ctx, m.to, m.subject, m.html, m.text); err != nil {
+		println("Warning: failed to send talk status email to", u.Email, ":", err.Error())
+	}
+}
 
+// talkStatusEmail builds the speaker notification; ok is false for statuses
+// that are not announced.
+func talkStatusEmail(templates EmailTemplates, u *domain.User, talkTitle string, status domain.TalkStatus) (outgoingMail, bool) {
 	switch status {
 	case domain.TalkStatusApproved:
-		subj = "Доклад одобрен / Talk approved"
-		safeTitle := html.EscapeString(talkTitle)
-		text = fmt.Sprintf(
-			"Ваш доклад «%s» одобрен программным комитетом.\n\nYour talk \"%s\" was approved by the program committee.",
-			talkTitle, talkTitle,
-		)
-		htmlBody = fmt.Sprintf(
-			"<p>Ваш доклад «%s» одобрен программным комитетом.</p><hr><p>Your talk \"%s\" was approved by the program committee.</p>",
-			safeTitle, safeTitle,
-		)
+		return localized(u, func(lang string) (string, string, string) {
+			return templates.TalkApproved(lang, talkTitle)
+		}), true
 	case domain.TalkStatusRejected:
-		subj = "Доклад отклонен / Talk rejected"
-		safeTitle := html.EscapeString(talkTitle)
-		text = fmt.Sprintf(
-			"Ваш доклад «%s» отклонен программным комитетом.\n\nYour talk \"%s\" was rejected by the program committee.",
-			talkTitle, talkTitle,
-		)
-		htmlBody = fmt.Sprintf(
-			"<p>Ваш доклад «%s» отклонен программным комитетом.</p><hr><p>Your talk \"%s\" was rejected by the program committee.</p>",
-			safeTitle, safeTitle,
-		)
-	default:
-		return
-	}
-
-	if err := mailer.Send(ctx, email, subj, htmlBody, text); err != nil {
-		println("Warning: failed to send talk status email to", email, ":", err.Error())
+		return localized(u, func(lang string) (string, string, string) {
+			return templates.TalkRejected(lang, talkTitle)
+		}), true
 	}
+	return outgoingMail{}, false
 }
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiTokenPrefix     = "cst_"
+	apiTokenDefaultTTL = 90 * 24 * time.Hour
+	apiTokenMaxTTL     = 365 * 24 * time.Hour
+)
+
+type APITokenService struct {
+	tokens ports.APITokenRepo
+	users  ports.UserRepo
+	audit  ports.AuditRepo
+	clock  ports.Clock
+}
+
+func NewAPITokenService(tokens ports.APITokenRepo, users ports.UserRepo, audit ports.AuditRepo, clock ports.Clock) *APITokenService {
+	return &APITokenService{tokens: tokens, users: users, audit: audit, clock: clock}
+}
+
+// Create issues a new token for the user. The raw secret is returned exactly once.
+func (s *APITokenService) Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, ttl time.Duration) (string, *domain.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return "", nil, domain.ErrInvalidInput
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+	if ttl == 0 {
+		ttl = apiTokenDefaultTTL
+	}
+	if ttl < 0 || ttl > apiTokenMaxTTL {
+		return "", nil, domain.ErrInvalidInput
+	}
+
+	secret, hash, err := newTokenPair()
+	if err != nil {
+		return "", nil, err
+	}
+	raw := apiTokenPrefix + secret
+	t := domain.APIToken{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:len(apiTokenPrefix)+8],
+		TokenHash: hash,
+		Scopes:    scopes,
+		ExpiresAt: s.clock.Now().Add(ttl),
+	}
+	id, err := s.tokens.Create(ctx, t)
+	if err != nil {
+		return "", nil, err
+	}
+	t.ID = id
+	t.CreatedAt = s.clock.Now()
+	s.logAudit(ctx, userID, "api_token.create", id)
+	return raw, &t, nil
+}
+
+func (s *APITokenService) List(ctx context.Context, userID uuid.UUID) ([]domain.APIToken, error) {
+	return s.tokens.ListByUser(ctx, userID)
+}
+
+func (s *APITokenService) ListAll(ctx context.Context) ([]domain.APIToken, error) {
+	return s.tokens.ListAll(ctx)
+}
+
+// Revoke disables a token. Unless asAdmin is set, only the owner may revoke it.
+func (s *APITokenService) Revoke(ctx context.Context, actorID, tokenID uuid.UUID, asAdmin bool) error {
+	t, err := s.tokens.Get(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if !asAdmin && t.UserID != actorID {
+		return domain.ErrNotFound
+	}
+	if err := s.tokens.Revoke(ctx, tokenID, s.clock.Now()); err != nil {
+		return err
+	}
+	s.logAudit(ctx, actorID, "api_token.revoke", tokenID)
+	return nil
+}
+
+// AuthenticateBearer resolves a raw bearer token to its owner, the owner's
+// current roles and the token scopes. It records the last use.
+func (s *APITokenService) AuthenticateBearer(ctx context.Context, raw, ip string) (uuid.UUID, []string, []string, error) {
+	if !strings.HasPrefix(raw, apiTokenPrefix) {
+		return uuid.Nil, nil, nil, domain.ErrUnauthorized
+	}
+	t, err := s.tokens.ByHash(ctx, hashToken(strings.TrimPrefix(raw, apiTokenPrefix)))
+	if err != nil {
+		return uuid.Nil, nil, nil, domain.ErrUnauthorized
+	}
+	now := s.clock.Now()
+	if t.RevokedAt != nil || now.After(t.ExpiresAt) {
+		return uuid.Nil, nil, nil, domain.ErrUnauthorized
+	}
+	u, roles, err := s.users.ByID(ctx, t.UserID)
+	if err != nil || u.DisabledAt != nil {
+		return uuid.Nil, nil, nil, domain.ErrUnauthorized
+	}
+	if err := s.tokens.TouchUsage(ctx, t.ID, ip, now); err != nil {
+		println("Warning: failed to record api token usage", t.ID.String(), ":", err.Error())
+	}
+	return u.ID, rolesToStrings(roles, u.Status), t.Scopes, nil
+}
+
+func (s *APITokenService) logAudit(ctx context.Context, actorID uuid.UUID, action string, tokenID uuid.UUID) {
+	if err := s.audit.Insert(ctx, domain.AuditLog{
+		ActorUserID: &actorID,
+		Action:      action,
+		Entity:      "api_token",
+		EntityID:    &tokenID,
+	}); err != nil {
+		println("Warning: failed to write audit entry", action, ":", err.Error())
+	}
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	known := make(map[string]bool, len(domain.APIScopes))
+	for _, sc := range domain.APIScopes {
+		known[sc] = true
+	}
+	seen := map[string]bool{}
+	out := make([]string, 0, len(scopes))
+	for _, sc := range scopes {
+		sc = strings.ToLower(strings.TrimSpace(sc))
+		if !known[sc] {
+			return nil, domain.ErrInvalidInput
+		}
+		if !seen[sc] {
+			seen[sc] = true
+			out = append(out, sc)
+		}
+	}
+	if len(out) == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return out, nil
+}
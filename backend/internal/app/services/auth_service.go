@@ -15,15 +15,23 @@ import (
 )
 
 type AuthService struct {
-	cfg       AppConfig
-	users     ports.UserRepo
-	sessions  ports.SessionRepo
-	tokens    ports.EmailTokenRepo
-	profiles  ports.ProfileRepo
-	mailer    ports.Mailer
-	templates EmailTemplates
-	clock     ports.Clock
-	jwtSecret []byte
+	cfg          AppConfig
+	users        ports.UserRepo
+	sessions     ports.SessionRepo
+	tokens       ports.EmailTokenRepo
+	emailChanges ports.EmailChangeRepo
+	profiles     ports.ProfileRepo
+	attempts     ports.LoginAttemptRepo
+	devices      ports.UserDeviceRepo
+	oidc         map[string]ports.IdentityProvider
+	oidcOrder    []string
+	oidcState    ports.OIDCStateRepo
+	idents       ports.UserIdentityRepo
+	audit        ports.AuditRepo
+	mailer       ports.Mailer
+	templates    EmailTemplates
+	clock        ports.Clock
+	keys         *auth.KeySet
 }
 
 type IssuedTokens struct {
@@ -35,24 +43,40 @@ type IssuedTokens struct {
 	Roles        []string
 	Status       domain.UserStatus
 	Email        string
+	// EmailUndeliverable asks the client to prompt for a working address.
+	EmailUndeliverable bool
 }
 
 func NewAuthService(
 	cfg AppConfig,
-	jwtSecret []byte,
+	keys *auth.KeySet,
 	users ports.UserRepo,
 	sessions ports.SessionRepo,
 	tokens ports.EmailTokenRepo,
+	emailChanges ports.EmailChangeRepo,
 	profiles ports.ProfileRepo,
+	attempts ports.LoginAttemptRepo,
+	devices ports.UserDeviceRepo,
+	identityProviders []ports.IdentityProvider,
+	oidcState ports.OIDCStateRepo,
+	idents ports.UserIdentityRepo,
+	audit ports.AuditRepo,
 	mailer ports.Mailer,
 	templates EmailTemplates,
 	clock ports.Clock,
 ) *AuthService {
-	return &AuthService{
-		cfg: cfg, jwtSecret: jwtSecret,
-		users: users, sessions: sessions, tokens: tokens, profiles: profiles,
+	s := &AuthService{
+		cfg: cfg, keys: keys,
+		users: users, sessions: sessions, tokens: tokens, emailChanges: emailChanges, profiles: profiles,
+		attempts: attempts, devices: devices,
+		oidc: map[string]ports.IdentityProvider{}, oidcState: oidcState, idents: idents, audit: audit,
 		mailer: mailer, templates: templates, clock: clock,
 	}
+	for _, p := range identityProviders {
+		s.oidc[p.ID()] = p
+		s.oidcOrder = append(s.oidcOrder, p.ID())
+	}
+	return s
 }
 
 func (s *AuthService) Register(ctx context.Context, email, password, lang string) error {
@@ -69,8 +93,22 @@ func (s *AuthService) Register(ctx context.Context, email, password, lang string
 	}
 	// default role USER
 	_ = s.users.AssignRole(ctx, userID, domain.RoleUser, nil)
+	if err := s.users.SetPreferredLang(ctx, userID, SafeLang(lang)); err != nil {
+		println("Warning: failed to store preferred language for user", userID.String(), ":", err.Error())
+	}
+
+	// create profile (required for talks and other features)
+	if err := s.profiles.Upsert(ctx, domain.Profile{UserID: userID, Name: "", Surname: "", Patronymic: ""}); err != nil {
+		// Log error but don't fail registration
+		println("Warning: failed to create profile for user", userID.String(), ":", err.Error())
+	}
+
+	return s.sendVerification(ctx, userID, email, lang)
+}
 
-	// create verify token and persist its hash
+// sendVerification persists a fresh verify token and mails the link
+// asynchronously to avoid blocking the API.
+func (s *AuthService) sendVerification(ctx context.Context, userID uuid.UUID, email, lang string) error {
 	rawVerifyToken, verifyTokenHash, err := newTokenPair()
 	if err != nil {
 		return err
@@ -79,13 +117,6 @@ func (s *AuthService) Register(ctx context.Context, email, password, lang string
 		return err
 	}
 
-	// create profile (required for talks and other features)
-	if err := s.profiles.Upsert(ctx, domain.Profile{UserID: userID, Name: "", Surname: "", Patronymic: ""}); err != nil {
-		// Log error but don't fail registration
-		println("Warning: failed to create profile for user", userID.String(), ":", err.Error())
-	}
-
-	// Send verification email asynchronously to avoid blocking registration API.
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
 		defer cancel()
@@ -123,7 +154,7 @@ func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
 		go func(email string) {
 			ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
 			defer cancel()
-			subj, html, text := s.templates.WelcomeEmail("ru")
+			subj, html, text := s.templates.WelcomeEmail(UserLang(u, ""))
 			if err := s.mailer.Send(ctx, email, subj, html, text); err != nil {
 				println("Warning: failed to send welcome email to", email, ":", err.Error())
 			}
@@ -133,34 +164,67 @@ func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
 	return nil
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password, lang string) (*IssuedTokens, error) {
+func (s *AuthService) Login(ctx context.Context, email, password, lang string, client ClientInfo) (*IssuedTokens, error) {
+	// Throttling goes by the address before the account is looked up, so an
+	// unknown email is delayed and locked exactly like a registered one.
+	key := loginKey(email)
+	now := s.clock.Now()
+	st, err := s.attempts.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkThrottle(st, now); err != nil {
+		return nil, err
+	}
 	u, roles, err := s.users.ByEmail(ctx, email)
 	if err != nil {
+		s.registerFailure(ctx, key, nil, now, lang)
 		return nil, domain.ErrUnauthorized
 	}
 	if !auth.CheckPassword(u.PasswordHash, password) {
+		s.registerFailure(ctx, key, u, now, lang)
 		return nil, domain.ErrUnauthorized
 	}
+	if st.FailedCount > 0 || st.LockedUntil != nil {
+		if err := s.attempts.Reset(ctx, key); err != nil {
+			println("Warning: failed to reset login attempts for", u.ID.String(), ":", err.Error())
+		}
+	}
+	s.trackDevice(ctx, u, client, now, lang)
+	s.rememberLang(ctx, u, lang)
 
-	roleCodes := rolesToStrings(roles, u.Status)
-	access, accessExp, err := s.issueAccess(u.ID, roleCodes)
-	if err != nil {
-		return nil, err
+	return s.issueSession(ctx, u, roles)
+}
+
+// rememberLang stores the request language for accounts that have no
+// preference yet, so later background emails use it too.
+func (s *AuthService) rememberLang(ctx context.Context, u *domain.User, lang string) {
+	if u.PreferredLang != nil || lang == "" {
+		return
 	}
-	rawRefresh, refreshHash, err := newTokenPair()
-	if err != nil {
-		return nil, err
+	l := SafeLang(lang)
+	if err := s.users.SetPreferredLang(ctx, u.ID, l); err != nil {
+		println("Warning: failed to store preferred language for user", u.ID.String(), ":", err.Error())
+		return
 	}
-	refreshExp := s.clock.Now().Add(s.cfg.RefreshTTL)
-	if err := s.sessions.Create(ctx, u.ID, refreshHash, refreshExp); err != nil {
-		return nil, err
+	u.PreferredLang = &l
+}
+
+// SetPreferredLang changes the language of the user's emails.
+func (s *AuthService) SetPreferredLang(ctx context.Context, userID uuid.UUID, lang string) error {
+	if lang != "ru" && lang != "en" {
+		return domain.ErrInvalidInput
 	}
+	return s.users.SetPreferredLang(ctx, userID, lang)
+}
 
-	return &IssuedTokens{
-		AccessToken: access, AccessExp: accessExp,
-		RefreshToken: rawRefresh, RefreshExp: refreshExp,
-		UserID: u.ID, Roles: roleCodes, Status: u.Status, Email: u.Email,
-	}, nil
+// UnlockAccount clears a lockout and the failed-attempt counter.
+func (s *AuthService) UnlockAccount(ctx context.Context, userID uuid.UUID) error {
+	u, _, err := s.users.ByID(ctx, userID)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	return s.attempts.Reset(ctx, loginKey(u.Email))
 }
 
 func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*IssuedTokens, error) {
@@ -185,13 +249,31 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*Issued
 	if err != nil {
 		return nil, domain.ErrUnauthorized
 	}
-	roleCodes := rolesToStrings(roles, u.Status)
+	return s.issueSession(ctx, u, roles)
+}
+
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+	hash := hashToken(refreshToken)
+	sess, err := s.sessions.ByTokenHash(ctx, hash)
+	if err != nil {
+		return nil
+	}
+	return s.sessions.Revoke(ctx, sess.ID)
+}
 
+// issueSession creates a refresh session and a matching access token for the user.
+func (s *AuthService) issueSession(ctx context.Context, u *domain.User, roles []domain.RoleAssignment) (*IssuedTokens, error) {
+	if u.DisabledAt != nil {
+		return nil, domain.ErrUnauthorized
+	}
+	roleCodes := rolesToStrings(roles, u.Status)
 	access, accessExp, err := s.issueAccess(u.ID, roleCodes)
 	if err != nil {
 		return nil, err
 	}
-
 	rawRefresh, refreshHash, err := newTokenPair()
 	if err != nil {
 		return nil, err
@@ -205,24 +287,13 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*Issued
 		AccessToken: access, AccessExp: accessExp,
 		RefreshToken: rawRefresh, RefreshExp: refreshExp,
 		UserID: u.ID, Roles: roleCodes, Status: u.Status, Email: u.Email,
+		EmailUndeliverable: u.EmailUndeliverableAt != nil,
 	}, nil
 }
 
-func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	if refreshToken == "" {
-		return nil
-	}
-	hash := hashToken(refreshToken)
-	sess, err := s.sessions.ByTokenHash(ctx, hash)
-	if err != nil {
-		return nil
-	}
-	return s.sessions.Revoke(ctx, sess.ID)
-}
-
 func (s *AuthService) issueAccess(userID uuid.UUID, roles []string) (string, time.Time, error) {
 	now := s.clock.Now()
-	token, err := auth.SignAccessJWT(s.jwtSecret, userID, roles, s.cfg.AccessTTL)
+	token, err := s.keys.SignAccess(userID, roles, s.cfg.AccessTTL)
 	return token, now.Add(s.cfg.AccessTTL), err
 }
 
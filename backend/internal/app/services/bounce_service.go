@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// BounceService records bounces and complaints reported by the mail provider
+// webhook or found by polling the bounce mailbox. A hard bounce marks the
+// account's address undeliverable; a complaint unsubscribes it from campaigns.
+type BounceService struct {
+	repo   ports.BounceRepo
+	source ports.BounceSource
+	clock  ports.Clock
+}
+
+// NewBounceService: source may be nil when no mailbox is polled.
+func NewBounceService(repo ports.BounceRepo, source ports.BounceSource, clock ports.Clock) *BounceService {
+	return &BounceService{repo: repo, source: source, clock: clock}
+}
+
+func (s *BounceService) Process(ctx context.Context, b domain.MailBounce) error {
+	b.Email = strings.ToLower(strings.TrimSpace(b.Email))
+	if b.Email == "" {
+		return domain.ErrInvalidInput
+	}
+	switch b.Kind {
+	case domain.BounceHard, domain.BounceSoft, domain.BounceComplaint:
+	default:
+		return domain.ErrInvalidInput
+	}
+	if b.ReceivedAt.IsZero() {
+		b.ReceivedAt = s.clock.Now()
+	}
+	return s.repo.Record(ctx, b)
+}
+
+// Poll fetches new reports from the configured source. A report that cannot
+// be recorded is logged and dropped, since the source does not return it again.
+func (s *BounceService) Poll(ctx context.Context) error {
+	if s.source == nil {
+		return nil
+	}
+	bounces, err := s.source.Fetch(ctx)
+	for _, b := range bounces {
+		if perr := s.Process(ctx, b); perr != nil {
+			println("Warning: failed to record bounce for", b.Email, ":", perr.Error())
+		}
+	}
+	return err
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxBulkItems   = 2000
+	mailBatchSize  = 50
+	mailBatchPause = time.Second
+)
+
+// outgoingMail is one notification composed by a status change.
+type outgoingMail struct {
+	to, subject, html, text string
+}
+
+// BulkRequest selects the rows of a bulk moderation call. Without IDs every
+// row matching Filter is affected, so at least one of them is required. Key
+// is the client's idempotency key ("" to disable replay).
+type BulkRequest struct {
+	Key    string
+	IDs    []uuid.UUID
+	Filter domain.BulkFilter
+}
+
+func (r BulkRequest) validate() error {
+	if len(r.IDs) == 0 && r.Filter.Empty() {
+		return domain.ErrInvalidInput
+	}
+	if len(r.IDs) > maxBulkItems || len(r.Key) > 200 {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
+// BulkSetUserStatus moderates many participants at once. Per-user outcomes
+// follow SetUserStatus, including the waitlist for approvals over capacity.
+// Notifications are queued only for rows changed by this call, so a replayed
+// request sends nothing.
+func (s *AdminService) BulkSetUserStatus(ctx context.Context, actorID uuid.UUID, req BulkRequest, status domain.UserStatus) ([]domain.BulkItemResult, bool, error) {
+	if status != domain.StatusApproved && status != domain.StatusRejected && status != domain.StatusWaiting && status != domain.StatusWaitlisted {
+		return nil, false, domain.ErrInvalidInput
+	}
+	if err := req.validate(); err != nil {
+		return nil, false, err
+	}
+	results, replayed, err := s.moderation.SetUserStatuses(ctx, req.Key, actorID, req.IDs, req.Filter, status, s.cfg.CapacityCategoryField)
+	if err != nil || replayed {
+		return results, replayed, err
+	}
+
+	var positions map[uuid.UUID]int
+	mails := []outgoingMail{}
+	promote := false
+	for _, res := range results {
+		if res.Result != domain.BulkUpdated {
+			continue
+		}
+		prev, next := domain.UserStatus(res.Previous), domain.UserStatus(res.Status)
+		if prev == domain.StatusApproved {
+			promote = true
+		}
+		if prev != domain.StatusWaiting && prev != domain.StatusWaitlisted {
+			continue
+		}
+		u, _, err := s.users.ByID(ctx, res.ID)
+		if err != nil {
+			continue
+		}
+		switch next {
+		case domain.StatusApproved:
+			if prev == domain.StatusWaitlisted {
+				mails = append(mails, localized(u, s.templates.WaitlistPromoted))
+			} else {
+				mails = append(mails, localized(u, s.templates.StatusApproved))
+			}
+		case domain.StatusRejected:
+			mails = append(mails, localized(u, s.templates.StatusRejected))
+		case domain.StatusWaitlisted:
+			if prev != domain.StatusWaiting {
+				continue
+			}
+			if positions == nil {
+				positions = s.waitlistPositions(ctx)
+			}
+			position := positions[res.ID]
+			mails = append(mails, localized(u, func(lang string) (string, string, string) {
+				return s.templates.Waitlisted(lang, position)
+			}))
+		}
+	}
+	s.enqueueBatch(ctx, mails)
+	if promote {
+		s.promoteWaitlist(ctx)
+	}
+	return results, false, nil
+}
+
+// BulkSetTalkStatus moderates many talks at once; withdrawn talks are skipped.
+func (s *AdminService) BulkSetTalkStatus(ctx context.Context, actorID uuid.UUID, req BulkRequest, status domain.TalkStatus) ([]domain.BulkItemResult, bool, error) {
+	if status != domain.TalkStatusApproved && status != domain.TalkStatusRejected && status != domain.TalkStatusWaiting {
+		return nil, false, domain.ErrInvalidInput
+	}
+	if err := req.validate(); err != nil {
+		return nil, false, err
+	}
+	results, replayed, err := s.moderation.SetTalkStatuses(ctx, req.Key, actorID, req.IDs, req.Filter, status)
+	if err != nil || replayed {
+		return results, replayed, err
+	}
+
+	mails := []outgoingMail{}
+	for _, res := range results {
+		// Notify speaker only for first moderation decision.
+		if res.Result != domain.BulkUpdated || domain.TalkStatus(res.Previous) != domain.TalkStatusWaiting {
+			continue
+		}
+		t, err := s.talks.Get(ctx, res.ID)
+		if err != nil {
+			continue
+		}
+		u, _, err := s.users.ByID(ctx, t.SpeakerUserID)
+		if err != nil {
+			continue
+		}
+		if m, ok := talkStatusEmail(s.templates, u, t.Title, status); ok {
+			mails = append(mails, m)
+		}
+	}
+	s.enqueueBatch(ctx, mails)
+	return results, false, nil
+}
+
+func (s *AdminService) waitlistPositions(ctx context.Context) map[uuid.UUID]int {
+	out := map[uuid.UUID]int{}
+	entries, err := s.waitlist.List(ctx, s.cfg.CapacityCategoryField)
+	if err != nil {
+		println("Warning: failed to load waitlist positions:", err.Error())
+		return out
+	}
+	for _, e := range entries {
+		out[e.UserID] = e.Position
+	}
+	return out
+}
+
+// enqueueBatch stores the notifications of a committed bulk change for
+// DeliverBulkMail, so they survive a restart and do not hold the response.
+func (s *AdminService) enqueueBatch(ctx context.Context, mails []outgoingMail) {
+	if len(mails) == 0 {
+		return
+	}
+	batchID := uuid.New()
+	queued := make([]domain.QueuedMail, 0, len(mails))
+	for _, m := range mails {
+		queued = append(queued, domain.QueuedMail{BatchID: batchID, To: m.to, Subject: m.subject, HTML: m.html, Text: m.text})
+	}
+	if err := s.moderation.EnqueueMail(context.WithoutCancel(ctx), queued); err != nil {
+		println("Warning: failed to queue bulk moderation batch", batchID.String(), ":", err.Error())
+	}
+}
+
+// DeliverBulkMail sends queued bulk moderation notifications in chunks,
+// pausing between chunks so a few hundred messages do not flood the relay.
+// Each message is tried once; a failed recipient is logged with its batch id.
+// Messages left when ctx ends are sent by the next run.
+func (s *AdminService) DeliverBulkMail(ctx context.Context) error {
+	for {
+		mails, err := s.moderation.PendingMail(ctx, mailBatchSize)
+		if err != nil || len(mails) == 0 {
+			return err
+		}
+		for _, m := range mails {
+			err := s.mailer.Send(ctx, m.To, m.Subject, m.HTML, m.Text)
+			if ctx.Err() != nil {
+				return nil
+			}
+			var errMsg *string
+			if err != nil {
+				msg := err.Error()
+				errMsg = &msg
+				println("Warning: bulk moderation batch", m.BatchID.String(), "failed to email", m.To, ":", msg)
+			}
+			if err := s.moderation.MarkMail(ctx, m.ID, errMsg); err != nil {
+				return err
+			}
+		}
+		if len(mails) < mailBatchSize {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(mailBatchPause):
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"html"
+	"strings"
+	"text/template"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// CampaignService manages announcement mailings to participant segments.
+// Starting a campaign snapshots its recipients; Deliver, run by the scheduler,
+// sends them in batches so large segments do not hit the SMTP rate limits.
+type CampaignService struct {
+	repo     ports.CampaignRepo
+	users    ports.UserRepo
+	profiles ports.ProfileRepo
+	audit    ports.AuditRepo
+	mailer   ports.Mailer
+	clock    ports.Clock
+	cfg      AppConfig
+}
+
+func NewCampaignService(repo ports.CampaignRepo, users ports.UserRepo, profiles ports.ProfileRepo,
+	audit ports.AuditRepo, mailer ports.Mailer, clock ports.Clock, cfg AppConfig) *CampaignService {
+	return &CampaignService{repo: repo, users: users, profiles: profiles, audit: audit, mailer: mailer, clock: clock, cfg: cfg}
+}
+
+// CampaignData is what campaign subjects and bodies can reference.
+type CampaignData struct {
+	FullName       string
+	Name           string
+	Email          string
+	Status         string
+	UnsubscribeURL string
+}
+
+var sampleCampaignData = CampaignData{
+	FullName:       "Ivanov Ivan Ivanovich",
+	Name:           "Ivan",
+	Email:          "ivanov@example.org",
+	Status:         string(domain.StatusApproved),
+	UnsubscribeURL: "https://example.org/unsubscribe?token=sample",
+}
+
+func UnsubscribeURL(appURL, token string) string {
+	return joinURL(appURL, "/unsubscribe?token="+token)
+}
+
+func (s *CampaignService) List(ctx context.Context) ([]domain.Campaign, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *CampaignService) Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *CampaignService) Create(ctx context.Context, actorID uuid.UUID, c domain.Campaign) (uuid.UUID, error) {
+	if err := validateCampaign(c); err != nil {
+		return uuid.Nil, err
+	}
+	c.CreatedBy = &actorID
+	return s.repo.Create(ctx, c)
+}
+
+func (s *CampaignService) Update(ctx context.Context, c domain.Campaign) error {
+	if err := validateCampaign(c); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, c)
+}
+
+func (s *CampaignService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Recipients previews the segment of a draft; for a started campaign it
+// lists the snapshot with delivery status, optionally filtered by status.
+func (s *CampaignService) Recipients(ctx context.Context, id uuid.UUID, status string) ([]domain.CampaignRecipient, error) {
+	c, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == domain.CampaignDraft {
+		return s.repo.SegmentRecipients(ctx, c.Segment)
+	}
+	return s.repo.Recipients(ctx, id, status)
+}
+
+// SendTest mails the campaign to the actor, personalized with their own data.
+// An empty lang sends both language versions.
+func (s *CampaignService) SendTest(ctx context.Context, actorID, id uuid.UUID, lang string) error {
+	c, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	u, _, err := s.users.ByID(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	data := CampaignData{Email: u.Email, Status: string(u.Status), UnsubscribeURL: UnsubscribeURL(s.cfg.AppURL, "test")}
+	if p, err := s.profiles.Get(ctx, actorID); err == nil {
+		data.FullName = strings.TrimSpace(p.Surname + " " + p.Name + " " + p.Patronymic)
+		data.Name = p.Name
+	}
+	langs := []string{"ru", "en"}
+	if lang != "" {
+		langs = []string{SafeLang(lang)}
+	}
+	for _, l := range langs {
+		subject, htmlBody, text, err := renderCampaign(*c, l, data)
+		if err != nil {
+			return &TemplateError{Err: err}
+		}
+		if err := s.mailer.Send(ctx, u.Email, "[TEST] "+subject, htmlBody, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start snapshots the segment and queues the campaign for delivery. It
+// returns the number of recipients.
+func (s *CampaignService) Start(ctx context.Context, actorID, id uuid.UUID) (int, error) {
+	c, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if c.Status != domain.CampaignDraft {
+		return 0, domain.ErrInvalidState
+	}
+	if err := validateCampaign(*c); err != nil {
+		return 0, err
+	}
+	return s.start(ctx, &actorID, *c)
+}
+
+// Announce creates and starts a campaign in one step, for mailings sent on
+// behalf of another feature. actorID is nil when the scheduler triggers it.
+// A campaign that fails to start is deleted so a retry does not leave drafts.
+func (s *CampaignService) Announce(ctx context.Context, actorID *uuid.UUID, c domain.Campaign) (uuid.UUID, int, error) {
+	if err := validateCampaign(c); err != nil {
+		return uuid.Nil, 0, err
+	}
+	c.CreatedBy = actorID
+	id, err := s.repo.Create(ctx, c)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	c.ID = id
+	n, err := s.start(ctx, actorID, c)
+	if err != nil {
+		if derr := s.repo.Delete(ctx, id); derr != nil {
+			println("Warning: failed to delete unstarted campaign", id.String(), ":", derr.Error())
+		}
+		return uuid.Nil, 0, err
+	}
+	return id, n, nil
+}
+
+func (s *CampaignService) start(ctx context.Context, actorID *uuid.UUID, c domain.Campaign) (int, error) {
+	id := c.ID
+	recipients, err := s.repo.SegmentRecipients(ctx, c.Segment)
+	if err != nil {
+		return 0, err
+	}
+	for i := range recipients {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return 0, err
+		}
+		recipients[i].UnsubscribeToken = hex.EncodeToString(b)
+	}
+	if err := s.repo.Start(ctx, id, recipients, s.clock.Now()); err != nil {
+		return 0, err
+	}
+	if err := s.audit.Insert(ctx, domain.AuditLog{
+		ActorUserID: actorID,
+		Action:      "campaign.start",
+		Entity:      "campaign",
+		EntityID:    &id,
+		Details:     map[string]any{"name": c.Name, "recipients": len(recipients)},
+	}); err != nil {
+		println("Warning: failed to write audit entry campaign.start:", err.Error())
+	}
+	return len(recipients), nil
+}
+
+func (s *CampaignService) Cancel(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Cancel(ctx, id, s.clock.Now())
+}
+
+// Unsubscribe opts the owner of an unsubscribe link out of all campaigns.
+func (s *CampaignService) Unsubscribe(ctx context.Context, token string) error {
+	if strings.TrimSpace(token) == "" {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.Unsubscribe(ctx, token)
+}
+
+// Deliver sends the next batch of pending campaign messages. Failed sends are
+// recorded per recipient and not retried.
+func (s *CampaignService) Deliver(ctx context.Context) error {
+	batch, err := s.repo.NextPending(ctx, s.cfg.CampaignBatchSize)
+	if err != nil {
+		return err
+	}
+	campaigns := map[uuid.UUID]*domain.Campaign{}
+	for _, rc := range batch {
+		c, ok := campaigns[rc.CampaignID]
+		if !ok {
+			if c, err = s.repo.Get(ctx, rc.CampaignID); err != nil {
+				return err
+			}
+			campaigns[rc.CampaignID] = c
+		}
+		status, errMsg := domain.RecipientSent, (*string)(nil)
+		if err := s.sendOne(ctx, *c, rc); err != nil {
+			msg := err.Error()
+			status, errMsg = domain.RecipientFailed, &msg
+		}
+		if err := s.repo.MarkRecipient(ctx, rc.CampaignID, rc.UserID, status, errMsg, s.clock.Now()); err != nil {
+			return err
+		}
+	}
+	return s.repo.FinishCompleted(ctx, s.clock.Now())
+}
+
+func (s *CampaignService) sendOne(ctx context.Context, c domain.Campaign, rc domain.CampaignRecipient) error {
+	subject, htmlBody, text, err := renderCampaign(c, rc.Lang, CampaignData{
+		FullName:       rc.FullName,
+		Name:           rc.FirstName,
+		Email:          rc.Email,
+		Status:         string(rc.UserStatus),
+		UnsubscribeURL: UnsubscribeURL(s.cfg.AppURL, rc.UnsubscribeToken),
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, rc.Email, subject, htmlBody, text)
+}
+
+func validateCampaign(c domain.Campaign) error {
+	if strings.TrimSpace(c.Name) == "" || strings.TrimSpace(c.SubjectRu) == "" || strings.TrimSpace(c.BodyRu) == "" ||
+		strings.TrimSpace(c.SubjectEn) == "" || strings.TrimSpace(c.BodyEn) == "" {
+		return domain.ErrInvalidInput
+	}
+	for _, lang := range []string{"ru", "en"} {
+		if _, _, _, err := renderCampaign(c, lang, sampleCampaignData); err != nil {
+			return &TemplateError{Err: err}
+		}
+	}
+	return nil
+}
+
+var unsubscribeFooter = map[string]string{
+	"ru": "Вы получили это письмо как участник конференции. Отписаться от рассылки: ",
+	"en": "You received this email as a conference participant. Unsubscribe: ",
+}
+
+// renderCampaign executes the language version of c. The plain-text body is
+// turned into HTML paragraphs and both get the unsubscribe footer.
+func renderCampaign(c domain.Campaign, lang string, data CampaignData) (subject, htmlBody, text string, err error) {
+	subjectSrc, bodySrc := c.SubjectRu, c.BodyRu
+	if lang == "en" {
+		subjectSrc, bodySrc = c.SubjectEn, c.BodyEn
+	}
+	if subject, err = execCampaignTemplate("subject", subjectSrc, data); err != nil {
+		return "", "", "", err
+	}
+	if text, err = execCampaignTemplate("body", bodySrc, data); err != nil {
+		return "", "", "", err
+	}
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	var b strings.Builder
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			b.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(para), "\n", "<br>") + "</p>\n")
+		}
+	}
+	footer := unsubscribeFooter[SafeLang(lang)]
+	b.WriteString(`<p style="color:#888;font-size:12px">` + html.EscapeString(footer) +
+		`<a href="` + html.EscapeString(data.UnsubscribeURL) + `">` + html.EscapeString(data.UnsubscribeURL) + "</a></p>\n")
+	text = strings.TrimRight(text, "\n") + "\n\n--\n" + footer + data.UnsubscribeURL + "\n"
+	return subject, b.String(), text, nil
+}
+
+func execCampaignTemplate(name, src string, data CampaignData) (string, error) {
+	tpl, err := template.New(name).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
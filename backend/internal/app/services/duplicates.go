@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DuplicateWarning tells the speaker that the talk resembles another
+// submission. Other speakers' talks are not disclosed: TalkID and Title are
+// only set when the similar talk is the speaker's own.
+type DuplicateWarning struct {
+	TalkID     *uuid.UUID `json:"talkId,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	OwnTalk    bool       `json:"ownTalk"`
+	Similarity float32    `json:"similarity"`
+}
+
+// checkDuplicates compares a saved talk with all others and records suspected
+// pairs for the admin report. Failures only cost the warning, never the save.
+func (s *TalkService) checkDuplicates(ctx context.Context, talkID, speakerID uuid.UUID) []DuplicateWarning {
+	out := []DuplicateWarning{}
+	if s.duplicates == nil || s.cfg.DuplicateThreshold <= 0 {
+		return out
+	}
+	candidates, err := s.duplicates.SimilarTalks(ctx, talkID, s.cfg.DuplicateThreshold)
+	if err != nil {
+		println("Warning: duplicate check failed for talk", talkID.String(), ":", err.Error())
+		return out
+	}
+	if err := s.duplicates.FlagTalks(ctx, talkID, candidates); err != nil {
+		println("Warning: failed to flag duplicate talks for", talkID.String(), ":", err.Error())
+	}
+	for _, c := range candidates {
+		w := DuplicateWarning{Similarity: max(c.TitleSimilarity, c.AbstractSimilarity)}
+		if c.SpeakerUserID == speakerID {
+			id := c.TalkID
+			w.TalkID, w.Title, w.OwnTalk = &id, c.Title, true
+		}
+		out = append(out, w)
+	}
+	return out
+}
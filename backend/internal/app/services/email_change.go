@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/lib/auth"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// emailChangeCancelTTL is how long the old address can still undo a change.
+const emailChangeCancelTTL = 7 * 24 * time.Hour
+
+// RequestEmailChange checks the password and mails a confirm link to the new
+// address and a cancel link to the current one. users.email is untouched until
+// the new address is confirmed.
+func (s *AuthService) RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail, lang string) error {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if newEmail == "" || !strings.Contains(newEmail, "@") {
+		return domain.ErrInvalidInput
+	}
+	u, _, err := s.users.ByID(ctx, userID)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	if !auth.CheckPassword(u.PasswordHash, password) {
+		return domain.ErrUnauthorized
+	}
+	if strings.EqualFold(u.Email, newEmail) {
+		return domain.ErrInvalidInput
+	}
+	if _, _, err := s.users.ByEmail(ctx, newEmail); err == nil {
+		return domain.ErrEmailTaken
+	}
+
+	rawConfirm, confirmHash, err := newTokenPair()
+	if err != nil {
+		return err
+	}
+	rawCancel, cancelHash, err := newTokenPair()
+	if err != nil {
+		return err
+	}
+	now := s.clock.Now()
+	if err := s.emailChanges.Create(ctx, ports.EmailChangeRequest{
+		UserID:           u.ID,
+		OldEmail:         u.Email,
+		NewEmail:         newEmail,
+		ConfirmTokenHash: confirmHash,
+		CancelTokenHash:  cancelHash,
+		ExpiresAt:        now.Add(s.cfg.VerifyEmailTTL),
+		CancelExpiresAt:  now.Add(emailChangeCancelTTL),
+	}); err != nil {
+		return err
+	}
+
+	oldEmail := u.Email
+	lang = UserLang(u, lang)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+		defer cancel()
+		subj, html, text := s.templates.EmailChangeConfirm(lang, newEmail, EmailChangeConfirmURL(s.cfg.AppURL, rawConfirm))
+		if err := s.mailer.Send(ctx, newEmail, subj, html, text); err != nil {
+			println("Warning: failed to send email change confirmation to", newEmail, ":", err.Error())
+		}
+		subj, html, text = s.templates.EmailChangeNotice(lang, newEmail, EmailChangeCancelURL(s.cfg.AppURL, rawCancel))
+		if err := s.mailer.Send(ctx, oldEmail, subj, html, text); err != nil {
+			println("Warning: failed to send email change notice to", oldEmail, ":", err.Error())
+		}
+	}()
+	return nil
+}
+
+// ConfirmEmailChange applies the change and signs the account out everywhere,
+// all in one transaction. It fails with domain.ErrEmailTaken when the address
+// was claimed by another account in the meantime.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, token string) error {
+	req, err := s.emailChanges.ByConfirmHash(ctx, hashToken(token))
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	now := s.clock.Now()
+	if req.ConfirmedAt != nil || req.CancelledAt != nil || now.After(req.ExpiresAt) {
+		return domain.ErrInvalidInput
+	}
+	if err := s.emailChanges.Confirm(ctx, req.ID, now); err != nil {
+		if errors.Is(err, domain.ErrInvalidState) {
+			return domain.ErrInvalidInput
+		}
+		return err
+	}
+	s.logAudit(ctx, req.UserID, "email_change.confirm", req.UserID, map[string]any{"from": req.OldEmail, "to": req.NewEmail})
+	return nil
+}
+
+// CancelEmailChange is used from the old address. A pending request is simply
+// dropped; an already confirmed one is reverted and all sessions are revoked,
+// since whoever changed the address may control the account. Both happen in
+// one transaction.
+func (s *AuthService) CancelEmailChange(ctx context.Context, token string) error {
+	req, err := s.emailChanges.ByCancelHash(ctx, hashToken(token))
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+	now := s.clock.Now()
+	if req.CancelledAt != nil || now.After(req.CancelExpiresAt) {
+		return domain.ErrInvalidInput
+	}
+	reverted, err := s.emailChanges.Cancel(ctx, req.ID, now)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidState) {
+			return domain.ErrInvalidInput
+		}
+		return err
+	}
+	s.logAudit(ctx, req.UserID, "email_change.cancel", req.UserID, map[string]any{"from": req.OldEmail, "to": req.NewEmail, "reverted": reverted})
+	return nil
+}
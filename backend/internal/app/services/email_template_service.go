@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplateService lets admins override the email template files. Saved
+// templates are validated against the documented variables of their email.
+type EmailTemplateService struct {
+	repo   ports.EmailTemplateRepo
+	engine EmailTemplateEngine
+}
+
+func NewEmailTemplateService(repo ports.EmailTemplateRepo, engine EmailTemplateEngine) *EmailTemplateService {
+	return &EmailTemplateService{repo: repo, engine: engine}
+}
+
+// EmailTemplateInfo is one catalog entry with the template in use per language.
+type EmailTemplateInfo struct {
+	domain.EmailTemplateDef
+	Templates []domain.EmailTemplate `json:"templates"`
+}
+
+var emailTemplateLangs = []string{"ru", "en"}
+
+func (s *EmailTemplateService) List(ctx context.Context) ([]EmailTemplateInfo, error) {
+	active, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]domain.EmailTemplate{}
+	for _, t := range active {
+		byKey[t.Name+"/"+t.Lang] = t
+	}
+	out := []EmailTemplateInfo{}
+	for _, def := range s.engine.Catalog() {
+		info := EmailTemplateInfo{EmailTemplateDef: def, Templates: []domain.EmailTemplate{}}
+		for _, lang := range emailTemplateLangs {
+			t, ok := byKey[def.Name+"/"+lang]
+			if !ok {
+				if t, err = s.engine.File(def.Name, lang); err != nil {
+					return nil, fmt.Errorf("template file %s/%s: %w", lang, def.Name, err)
+				}
+			}
+			info.Templates = append(info.Templates, t)
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+// Get returns the template in use: the active version or the file (version 0).
+func (s *EmailTemplateService) Get(ctx context.Context, name, lang string) (*domain.EmailTemplate, error) {
+	if _, err := s.def(name, lang); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.Active(ctx, name, lang)
+	if err == nil {
+		return t, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	file, err := s.engine.File(name, lang)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// Save validates t and stores it as the new active version.
+func (s *EmailTemplateService) Save(ctx context.Context, actorID uuid.UUID, t domain.EmailTemplate) (int, error) {
+	def, err := s.def(t.Name, t.Lang)
+	if err != nil {
+		return 0, err
+	}
+	if _, _, _, err := s.engine.Render(t, sampleData(def)); err != nil {
+		return 0, &TemplateError{Err: err}
+	}
+	return s.repo.Save(ctx, t, actorID)
+}
+
+// Reset drops the override so the file template is used again.
+func (s *EmailTemplateService) Reset(ctx context.Context, name, lang string) error {
+	if _, err := s.def(name, lang); err != nil {
+		return err
+	}
+	return s.repo.Deactivate(ctx, name, lang)
+}
+
+// Preview renders draft, or the template in use when draft is nil, with the
+// sample values of the email's variables.
+func (s *EmailTemplateService) Preview(ctx context.Context, name, lang string, draft *domain.EmailTemplate) (subject, html, text string, err error) {
+	def, err := s.def(name, lang)
+	if err != nil {
+		return "", "", "", err
+	}
+	t := draft
+	if t == nil {
+		if t, err = s.Get(ctx, name, lang); err != nil {
+			return "", "", "", err
+		}
+	}
+	t.Name, t.Lang = name, lang
+	subject, html, text, err = s.engine.Render(*t, sampleData(def))
+	if err != nil {
+		return "", "", "", &TemplateError{Err: err}
+	}
+	return subject, html, text, nil
+}
+
+func (s *EmailTemplateService) Versions(ctx context.Context, name, lang string) ([]domain.EmailTemplate, error) {
+	if _, err := s.def(name, lang); err != nil {
+		return nil, err
+	}
+	return s.repo.Versions(ctx, name, lang)
+}
+
+// Rollback saves a copy of an earlier version as the newest one, so the
+// history stays linear.
+func (s *EmailTemplateService) Rollback(ctx context.Context, actorID uuid.UUID, name, lang string, version int) (int, error) {
+	if _, err := s.def(name, lang); err != nil {
+		return 0, err
+	}
+	t, err := s.repo.Version(ctx, name, lang, version)
+	if err != nil {
+		return 0, err
+	}
+	return s.Save(ctx, actorID, *t)
+}
+
+func (s *EmailTemplateService) def(name, lang string) (domain.EmailTemplateDef, error) {
+	if lang != "ru" && lang != "en" {
+		return domain.EmailTemplateDef{}, domain.ErrNotFound
+	}
+	for _, d := range s.engine.Catalog() {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return domain.EmailTemplateDef{}, domain.ErrNotFound
+}
+
+func sampleData(def domain.EmailTemplateDef) map[string]any {
+	data := make(map[string]any, len(def.Variables))
+	for _, v := range def.Variables {
+		data[v.Name] = v.Sample
+	}
+	return data
+}
+
+// TemplateError reports a template that does not parse or uses an unknown
+// variable; Err carries the position from the template package.
+type TemplateError struct {
+	Err error
+}
+
+func (e *TemplateError) Error() string { return e.Err.Error() }
+
+func (e *TemplateError) Unwrap() error { return domain.ErrInvalidInput }
@@ -2,6 +2,9 @@ package services
 
 import (
 	"fmt"
+	"time"
+
+	"confsite/backend/internal/domain"
 )
 
 type EmailTemplates interface {
@@ -10,11 +13,36 @@ type EmailTemplates interface {
 	RegistrationReceived(lang string) (subject, html, text string)
 	StatusApproved(lang string) (subject, html, text string)
 	StatusRejected(lang string) (subject, html, text string)
+	Waitlisted(lang string, position int) (subject, html, text string)
+	WaitlistPromoted(lang string) (subject, html, text string)
 
 	OrgNewRegistration(lang string, fullName, affiliation, city, email string) (subject, html, text string)
+	OrgRegistrationCancelled(lang string, fullName, email, reason string) (subject, html, text string)
+	OrgTalkWithdrawn(lang string, title, section, speaker, reason string) (subject, html, text string)
+
+	AccountLocked(lang string, lockedUntil time.Time) (subject, html, text string)
+	NewDeviceLogin(lang string, loginAt time.Time, ip, userAgent string) (subject, html, text string)
+	EmailChangeConfirm(lang string, newEmail, confirmURL string) (subject, html, text string)
+	EmailChangeNotice(lang string, newEmail, cancelURL string) (subject, html, text string)
 
+	TalkApproved(lang string, talkTitle string) (subject, html, text string)
+	TalkRejected(lang string, talkTitle string) (subject, html, text string)
 	TalkFileUploadedToUser(lang string, talkTitle string) (subject, html, text string)
 	OrgTalkFileUploaded(lang string, payload OrgTalkUploadedPayload) (subject, html, text string)
+	ResponsibleDigest(lang string, payload DigestPayload) (subject, html, text string)
+
+	MissingItemsReminder(lang string, payload ReminderPayload) (subject, html, text string)
+}
+
+// EmailTemplateEngine gives the admin template editor access to the built-in
+// templates and the renderer used for sending.
+type EmailTemplateEngine interface {
+	Catalog() []domain.EmailTemplateDef
+	// File returns the built-in template; domain.ErrNotFound for unknown names.
+	File(name, lang string) (domain.EmailTemplate, error)
+	// Render fails when a template does not parse or uses a variable that is
+	// not in data.
+	Render(t domain.EmailTemplate, data map[string]any) (subject, html, text string, err error)
 }
 
 type OrgTalkUploadedPayload struct {
@@ -29,6 +57,31 @@ type OrgTalkUploadedPayload struct {
 	FileNoteOrURL      string
 }
 
+// DigestPayload is the daily summary for one section responsible.
+type DigestPayload struct {
+	Since        time.Time
+	NewTalks     []DigestItem
+	RevisedTalks []DigestItem
+	PendingTalks []DigestItem
+	AdminURL     string
+}
+
+type DigestItem struct {
+	Title   string
+	Speaker string
+	Section string
+	Kind    string
+	URL     string
+}
+
+// ReminderPayload lists the checklist items a reminder asks for.
+type ReminderPayload struct {
+	MissingConsents bool
+	LicenseTalks    []string
+	FileTalks       []string
+	CabinetURL      string
+}
+
 func joinURL(base, path string) string {
 	if len(base) == 0 {
 		return path
@@ -46,6 +99,14 @@ func VerifyURL(appURL, token string) string {
 	return joinURL(appURL, "/verify-email?token="+token)
 }
 
+func EmailChangeConfirmURL(appURL, token string) string {
+	return joinURL(appURL, "/email-change/confirm?token="+token)
+}
+
+func EmailChangeCancelURL(appURL, token string) string {
+	return joinURL(appURL, "/email-change/cancel?token="+token)
+}
+
 func SafeLang(lang string) string {
 	if lang == "en" {
 		return "en"
@@ -10,26 +10,39 @@ import (
 )
 
 type ExportService struct {
-	repo ports.ExportRepo
+	repo   ports.ExportRepo
+	fields ports.RegistrationFieldRepo
 }
 
-func NewExportService(r ports.ExportRepo) *ExportService { return &ExportService{repo: r} }
+func NewExportService(r ports.ExportRepo, f ports.RegistrationFieldRepo) *ExportService {
+	return &ExportService{repo: r, fields: f}
+}
 
 func (s *ExportService) ParticipantsCSV(ctx context.Context, includeEmail bool) ([]byte, error) {
 	rows, err := s.repo.Participants(ctx)
 	if err != nil {
 		return nil, err
 	}
-	header := []string{"FullName", "Affiliation", "City"}
+	fields, err := s.fields.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	header := []string{"FullName", "Affiliation", "City", "Status", "CancellationReason"}
 	if includeEmail {
 		header = append(header, "Email")
 	}
+	for _, f := range fields {
+		header = append(header, f.Key)
+	}
 	out := make([][]string, 0, len(rows))
 	for _, r := range rows {
-		row := []string{r.FullName, r.Affiliation, r.City}
+		row := []string{r.FullName, r.Affiliation, r.City, r.Status, r.CancellationReason}
 		if includeEmail {
 			row = append(row, r.Email)
 		}
+		for _, f := range fields {
+			row = append(row, formatAnswer(f, r.Answers[f.Key]))
+		}
 		out = append(out, row)
 	}
 	return files.BuildCSV(header, out)
@@ -40,18 +53,28 @@ func (s *ExportService) ParticipantsXLSX(ctx context.Context, includeEmail bool)
 	if err != nil {
 		return nil, err
 	}
-	header := []string{"FullName", "Affiliation", "City"}
+	fields, err := s.fields.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	header := []string{"FullName", "Affiliation", "City", "Status", "CancellationReason"}
 	if includeEmail {
 		header = append(header, "Email")
 	}
+	for _, fd := range fields {
+		header = append(header, fd.Key)
+	}
 	f, sheet := files.NewXLSX("Participants", header)
 
 	rn := 2
 	for _, r := range rows {
-		vals := []any{r.FullName, r.Affiliation, r.City}
+		vals := []any{r.FullName, r.Affiliation, r.City, r.Status, r.CancellationReason}
 		if includeEmail {
 			vals = append(vals, r.Email)
 		}
+		for _, fd := range fields {
+			vals = append(vals, formatAnswer(fd, r.Answers[fd.Key]))
+		}
 		files.XLSXSetRow(f, sheet, rn, vals)
 		rn++
 	}
@@ -67,14 +90,14 @@ func (s *ExportService) TalksBySectionXLSX(ctx context.Context) ([]byte, error)
 	if err != nil {
 		return nil, err
 	}
-	header := []string{"Section", "Title", "Kind", "Authors", "Speaker", "SpeakerAffiliation", "SpeakerCity", "Abstract"}
+	header := []string{"Section", "Title", "Kind", "Authors", "Speaker", "SpeakerAffiliation", "SpeakerCity", "Abstract", "Status", "WithdrawalReason"}
 	f, sheet := files.NewXLSX("Talks", header)
 
 	rn := 2
 	for _, r := range rows {
 		authors := authorsJSONToLine(r.AuthorsJSON)
 		files.XLSXSetRow(f, sheet, rn, []any{
-			r.Section, r.Title, r.Kind, authors, r.Speaker, r.SpeakerAff, r.SpeakerCity, r.Abstract,
+			r.Section, r.Title, r.Kind, authors, r.Speaker, r.SpeakerAff, r.SpeakerCity, r.Abstract, r.Status, r.WithdrawalReason,
 		})
 		rn++
 	}
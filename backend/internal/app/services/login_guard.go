@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+)
+
+// ClientInfo describes where a login request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginThrottledError is returned by Login while an account is delayed or locked.
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	if e.Locked {
+		return domain.ErrAccountLocked
+	}
+	return domain.ErrTooManyAttempts
+}
+
+// loginKey is the address login throttling is recorded under.
+func loginKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// checkThrottle rejects the attempt if the email is locked or still inside
+// its progressive delay. It does not depend on whether an account uses the
+// email, so the answer does not reveal that.
+func (s *AuthService) checkThrottle(st *domain.LoginAttempts, now time.Time) error {
+	if st.LockedUntil != nil && now.Before(*st.LockedUntil) {
+		return &LoginThrottledError{Locked: true, RetryAfter: st.LockedUntil.Sub(now)}
+	}
+	if st.LastFailedAt == nil {
+		return nil
+	}
+	if now.Sub(*st.LastFailedAt) > s.cfg.Login.FailureWindow {
+		return nil
+	}
+	next := st.LastFailedAt.Add(s.loginDelay(st.FailedCount))
+	if now.Before(next) {
+		return &LoginThrottledError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// loginDelay doubles from DelayBase for every failure past DelayAfter, capped at DelayMax.
+func (s *AuthService) loginDelay(failures int) time.Duration {
+	p := s.cfg.Login
+	if p.DelayBase <= 0 || failures <= p.DelayAfter {
+		return 0
+	}
+	d := p.DelayBase
+	for i := p.DelayAfter + 1; i < failures && (p.DelayMax <= 0 || d < p.DelayMax); i++ {
+		d *= 2
+	}
+	if p.DelayMax > 0 && d > p.DelayMax {
+		return p.DelayMax
+	}
+	return d
+}
+
+// registerFailure counts a failed login for key and locks it once
+// MaxFailures is reached. u is nil when no account uses the email; the lock
+// then applies all the same, only no notice is sent.
+func (s *AuthService) registerFailure(ctx context.Context, key string, u *domain.User, now time.Time, lang string) {
+	count, err := s.attempts.RecordFailure(ctx, key, now, now.Add(-s.cfg.Login.FailureWindow))
+	if err != nil {
+		println("Warning: failed to record login failure for", key, ":", err.Error())
+		return
+	}
+	if s.cfg.Login.MaxFailures <= 0 || count < s.cfg.Login.MaxFailures {
+		return
+	}
+	until := now.Add(s.cfg.Login.Lockout)
+	if err := s.attempts.Lock(ctx, key, until); err != nil {
+		println("Warning: failed to lock login for", key, ":", err.Error())
+		return
+	}
+	if u == nil {
+		return
+	}
+	go func(email string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+		defer cancel()
+		subj, html, text := s.templates.AccountLocked(UserLang(u, lang), until)
+		if err := s.mailer.Send(ctx, email, subj, html, text); err != nil {
+			println("Warning: failed to send account locked email to", email, ":", err.Error())
+		}
+	}(u.Email)
+}
+
+// PurgeLoginAttempts drops throttling records that neither lock nor delay
+// anything any more, which also keeps unknown addresses from piling up.
+func (s *AuthService) PurgeLoginAttempts(ctx context.Context) error {
+	now := s.clock.Now()
+	_, err := s.attempts.DeleteStale(ctx, now, now.Add(-s.cfg.Login.FailureWindow))
+	return err
+}
+
+// trackDevice remembers the device and warns the user when a login comes from an unknown one.
+// The very first device of an account is recorded silently.
+func (s *AuthService) trackDevice(ctx context.Context, u *domain.User, client ClientInfo, now time.Time, lang string) {
+	known, err := s.devices.CountByUser(ctx, u.ID)
+	if err != nil {
+		println("Warning: failed to count devices for", u.ID.String(), ":", err.Error())
+		return
+	}
+	ua := strings.TrimSpace(client.UserAgent)
+	isNew, err := s.devices.Touch(ctx, u.ID, hashToken(strings.ToLower(ua)), ua, client.IP, now)
+	if err != nil {
+		println("Warning: failed to record device for", u.ID.String(), ":", err.Error())
+		return
+	}
+	if !isNew || known == 0 {
+		return
+	}
+	go func(email string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+		defer cancel()
+		subj, html, text := s.templates.NewDeviceLogin(UserLang(u, lang), now, client.IP, ua)
+		if err := s.mailer.Send(ctx, email, subj, html, text); err != nil {
+			println("Warning: failed to send new device email to", email, ":", err.Error())
+		}
+	}(u.Email)
+}
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"strings"
 
 	"confsite/backend/internal/domain"
 	"confsite/backend/internal/ports"
@@ -9,28 +10,191 @@ import (
 	"github.com/google/uuid"
 )
 
+// NewsService keeps news through its lifecycle: drafts are only visible to
+// admins, scheduled items go public at PublishAt and published ones are
+// archived at ExpiresAt. PublishDue, run by the scheduler, moves items along;
+// the public list does not wait for it.
 type NewsService struct {
-	news ports.NewsRepo
+	news      ports.NewsRepo
+	campaigns *CampaignService
+	clock     ports.Clock
+	cfg       AppConfig
 }
 
-func NewNewsService(n ports.NewsRepo) *NewsService { return &NewsService{news: n} }
+func NewNewsService(n ports.NewsRepo, campaigns *CampaignService, clock ports.Clock, cfg AppConfig) *NewsService {
+	return &NewsService{news: n, campaigns: campaigns, clock: clock, cfg: cfg}
+}
 
+// List returns the news visible to the public, pinned first.
 func (s *NewsService) List(ctx context.Context) ([]domain.News, error) {
-	return s.news.List(ctx)
+	return s.news.ListVisible(ctx, s.clock.Now())
 }
 
+// Get returns a public item; drafts, items not yet due and expired ones are
+// domain.ErrNotFound.
 func (s *NewsService) Get(ctx context.Context, id uuid.UUID) (*domain.News, error) {
+	n, err := s.news.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	if n.Status == domain.NewsScheduled && !n.PublishAt.After(now) {
+		n.Status, n.PublishedAt = domain.NewsPublished, n.PublishAt
+	}
+	if n.Status != domain.NewsPublished || (n.ExpiresAt != nil && !n.ExpiresAt.After(now)) {
+		return nil, domain.ErrNotFound
+	}
+	return n, nil
+}
+
+// ListAll returns every item in any state for the admin.
+func (s *NewsService) ListAll(ctx context.Context) ([]domain.News, error) {
+	return s.news.List(ctx)
+}
+
+// Preview returns an item in any state, for the admin to check it before
+// it goes public.
+func (s *NewsService) Preview(ctx context.Context, id uuid.UUID) (*domain.News, error) {
 	return s.news.Get(ctx, id)
 }
 
-func (s *NewsService) Create(ctx context.Context, n domain.News) (uuid.UUID, error) {
-	return s.news.Create(ctx, n)
+func (s *NewsService) Create(ctx context.Context, actorID uuid.UUID, n domain.News) (uuid.UUID, error) {
+	if err := s.prepare(&n, nil); err != nil {
+		return uuid.Nil, err
+	}
+	id, err := s.news.Create(ctx, n)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	n.ID = id
+	if n.Status == domain.NewsPublished {
+		s.notify(ctx, &actorID, n)
+	}
+	return id, nil
 }
 
-func (s *NewsService) Update(ctx context.Context, n domain.News) error {
-	return s.news.Update(ctx, n)
+func (s *NewsService) Update(ctx context.Context, actorID uuid.UUID, n domain.News) error {
+	prev, err := s.news.Get(ctx, n.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.prepare(&n, prev); err != nil {
+		return err
+	}
+	if err := s.news.Update(ctx, n); err != nil {
+		return err
+	}
+	if n.Status == domain.NewsPublished {
+		n.NotifiedAt = prev.NotifiedAt
+		s.notify(ctx, &actorID, n)
+	}
+	return nil
 }
 
 func (s *NewsService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.news.Delete(ctx, id)
 }
+
+// PublishDue publishes scheduled items whose time has come, mails every
+// published item still waiting for its mailing (including earlier failed
+// attempts) and archives expired items.
+func (s *NewsService) PublishDue(ctx context.Context) error {
+	now := s.clock.Now()
+	if _, err := s.news.PublishDue(ctx, now); err != nil {
+		return err
+	}
+	pending, err := s.news.PendingNotifications(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range pending {
+		s.notify(ctx, nil, n)
+	}
+	_, err = s.news.ArchiveExpired(ctx, now)
+	return err
+}
+
+// prepare validates n and fills PublishedAt from its state. A scheduled item
+// that is already due is published right away with PublishAt as its date;
+// a republished item keeps its original date.
+func (s *NewsService) prepare(n *domain.News, prev *domain.News) error {
+	if strings.TrimSpace(n.TitleRu) == "" || strings.TrimSpace(n.TitleEn) == "" {
+		return domain.ErrInvalidInput
+	}
+	now := s.clock.Now()
+	if prev != nil {
+		n.PublishedAt = prev.PublishedAt
+	}
+	start := now
+	switch n.Status {
+	case domain.NewsDraft, domain.NewsArchived:
+		if n.PublishAt != nil {
+			start = *n.PublishAt
+		}
+	case domain.NewsScheduled:
+		if n.PublishAt == nil {
+			return domain.ErrInvalidInput
+		}
+		start = *n.PublishAt
+		if !start.After(now) {
+			n.Status = domain.NewsPublished
+			if n.PublishedAt == nil {
+				n.PublishedAt = n.PublishAt
+			}
+		}
+	case domain.NewsPublished:
+		if n.PublishedAt == nil {
+			n.PublishedAt = &now
+		}
+	default:
+		return domain.ErrInvalidInput
+	}
+	if n.ExpiresAt != nil && !n.ExpiresAt.After(start) {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
+// notify mails a just-published item to all campaign subscribers once. It
+// goes out as a campaign, so delivery is paced and unsubscribed or bouncing
+// addresses are skipped. Failures are logged and the claim released, so the
+// scheduler retries; the item stays published.
+func (s *NewsService) notify(ctx context.Context, actorID *uuid.UUID, n domain.News) {
+	if !n.NotifySubscribers || n.NotifiedAt != nil || s.campaigns == nil {
+		return
+	}
+	claimed, err := s.news.ClaimNotification(ctx, n.ID, s.clock.Now())
+	if err != nil || !claimed {
+		if err != nil {
+			println("Warning: failed to claim news notification", n.ID.String(), ":", err.Error())
+		}
+		return
+	}
+	campaignID, _, err := s.campaigns.Announce(ctx, actorID, newsCampaign(n, joinURL(s.cfg.AppURL, "/news/"+n.ID.String())))
+	if err != nil {
+		println("Warning: failed to mail news", n.ID.String(), "to subscribers:", err.Error())
+		if err := s.news.ReleaseNotification(ctx, n.ID); err != nil {
+			println("Warning: failed to release news notification", n.ID.String(), ":", err.Error())
+		}
+		return
+	}
+	if err := s.news.SetCampaign(ctx, n.ID, campaignID); err != nil {
+		println("Warning: failed to link news", n.ID.String(), "to its campaign:", err.Error())
+	}
+}
+
+func newsCampaign(n domain.News, url string) domain.Campaign {
+	return domain.Campaign{
+		Name:      "News: " + n.TitleRu,
+		SubjectRu: templateLiteral(n.TitleRu),
+		BodyRu:    templateLiteral(strings.TrimSpace(n.BodyRu)) + "\n\nПодробнее: " + url,
+		SubjectEn: templateLiteral(n.TitleEn),
+		BodyEn:    templateLiteral(strings.TrimSpace(n.BodyEn)) + "\n\nRead more: " + url,
+	}
+}
+
+// templateLiteral quotes text for a campaign template so that braces in it
+// are not taken for actions.
+func templateLiteral(text string) string {
+	return strings.ReplaceAll(text, "{{", `{{"{{"}}`)
+}
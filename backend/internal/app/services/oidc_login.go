@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/lib/auth"
+	"confsite/backend/internal/ports"
+)
+
+// OIDCStateTTL bounds how long a started SSO login stays valid.
+const OIDCStateTTL = 10 * time.Minute
+
+type OIDCProviderInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// OIDCProviders lists the configured single sign-on providers in configuration order.
+func (s *AuthService) OIDCProviders() []OIDCProviderInfo {
+	out := make([]OIDCProviderInfo, 0, len(s.oidcOrder))
+	for _, id := range s.oidcOrder {
+		out = append(out, OIDCProviderInfo{ID: id, Name: s.oidc[id].Name()})
+	}
+	return out
+}
+
+// StartOIDC persists the state/nonce/PKCE verifier for the login attempt and
+// returns the provider's authorization URL to redirect the browser to, plus
+// the raw state the caller must bind to the browser (see CompleteOIDC).
+func (s *AuthService) StartOIDC(ctx context.Context, providerID, redirectTo string) (string, string, error) {
+	p, ok := s.oidc[providerID]
+	if !ok {
+		return "", "", domain.ErrNotFound
+	}
+	rawState, stateHash, err := newTokenPair()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, _, err := newTokenPair()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, _, err := newTokenPair()
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.oidcState.Create(ctx, ports.OIDCLoginState{
+		StateHash:    stateHash,
+		Provider:     providerID,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		RedirectTo:   safeRedirectPath(redirectTo),
+		ExpiresAt:    s.clock.Now().Add(OIDCStateTTL),
+	}); err != nil {
+		return "", "", err
+	}
+	authURL, err := p.AuthCodeURL(ctx, rawState, nonce, pkceChallenge(verifier), s.oidcRedirectURI(providerID))
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, rawState, nil
+}
+
+// CompleteOIDC finishes the authorization code flow and signs the user in with
+// the same access/refresh session as a password login. It returns the path the
+// browser should land on afterwards. browserState is the state StartOIDC
+// handed to the same browser; a callback carrying someone else's state (login
+// CSRF) is rejected without consuming it.
+func (s *AuthService) CompleteOIDC(ctx context.Context, providerID, code, state, browserState string, client ClientInfo, lang string) (*IssuedTokens, string, error) {
+	p, ok := s.oidc[providerID]
+	if !ok {
+		return nil, "", domain.ErrNotFound
+	}
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, "", domain.ErrUnauthorized
+	}
+	st, err := s.oidcState.Consume(ctx, hashToken(state))
+	if err != nil || st.Provider != providerID || s.clock.Now().After(st.ExpiresAt) {
+		return nil, "", domain.ErrUnauthorized
+	}
+	claims, err := p.Exchange(ctx, code, st.CodeVerifier, st.Nonce, s.oidcRedirectURI(providerID))
+	if err != nil {
+		println("Warning: oidc exchange failed for provider", providerID, ":", err.Error())
+		return nil, "", domain.ErrUnauthorized
+	}
+	u, roles, err := s.resolveOIDCUser(ctx, providerID, claims)
+	if err != nil {
+		return nil, "", err
+	}
+	s.trackDevice(ctx, u, client, s.clock.Now(), lang)
+	s.rememberLang(ctx, u, lang)
+	issued, err := s.issueSession(ctx, u, roles)
+	if err != nil {
+		return nil, "", err
+	}
+	return issued, st.RedirectTo, nil
+}
+
+// resolveOIDCUser finds the account for an external identity: first by an
+// existing link, then by verified email, otherwise it creates a new account.
+func (s *AuthService) resolveOIDCUser(ctx context.Context, providerID string, claims *ports.IdentityClaims) (*domain.User, []domain.RoleAssignment, error) {
+	if link, err := s.idents.ByProviderSubject(ctx, providerID, claims.Subject); err == nil {
+		u, roles, err := s.users.ByID(ctx, link.UserID)
+		if err != nil {
+			return nil, nil, domain.ErrUnauthorized
+		}
+		_ = s.idents.Link(ctx, ports.UserIdentity{UserID: u.ID, Provider: providerID, Subject: claims.Subject, Email: claims.Email})
+		return u, roles, nil
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, nil, domain.ErrEmailNotVerified
+	}
+	u, roles, err := s.users.ByEmail(ctx, claims.Email)
+	if err != nil {
+		u, roles, err = s.createOIDCUser(ctx, claims.Email)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if !u.EmailVerified {
+		// Linking to an unverified local account would let whoever registered
+		// the address first take over the SSO identity.
+		return nil, nil, domain.ErrEmailNotVerified
+	}
+	if err := s.idents.Link(ctx, ports.UserIdentity{UserID: u.ID, Provider: providerID, Subject: claims.Subject, Email: claims.Email}); err != nil {
+		return nil, nil, err
+	}
+	return u, roles, nil
+}
+
+func (s *AuthService) createOIDCUser(ctx context.Context, email string) (*domain.User, []domain.RoleAssignment, error) {
+	// SSO accounts get an unusable random password; an admin reset enables password login.
+	rawPassword, _, err := newTokenPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	hash, err := auth.HashPassword(rawPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+	userID, err := s.users.Create(ctx, email, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = s.users.AssignRole(ctx, userID, domain.RoleUser, nil)
+	if err := s.users.SetEmailVerified(ctx, userID, true); err != nil {
+		return nil, nil, err
+	}
+	if err := s.profiles.Upsert(ctx, domain.Profile{UserID: userID}); err != nil {
+		println("Warning: failed to create profile for user", userID.String(), ":", err.Error())
+	}
+	return s.users.ByID(ctx, userID)
+}
+
+func (s *AuthService) oidcRedirectURI(providerID string) string {
+	return joinURL(s.cfg.OIDCRedirectBase, "/auth/oidc/"+providerID+"/callback")
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// safeRedirectPath only allows local absolute paths so the callback cannot be
+// turned into an open redirect.
+func safeRedirectPath(p string) string {
+	p = strings.TrimSpace(p)
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") || strings.ContainsAny(p, "\\\r\n") {
+		return ""
+	}
+	return p
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// UserLang is the language of emails to u: the stored preference, otherwise
+// fallback (the request language in interactive flows, "" for Russian).
+func UserLang(u *domain.User, fallback string) string {
+	if u != nil && u.PreferredLang != nil {
+		return SafeLang(*u.PreferredLang)
+	}
+	return SafeLang(fallback)
+}
+
+// recipientLangs maps each address to its owner's preferred language.
+// Addresses without an account, like shared organizer mailboxes, get Russian.
+func recipientLangs(ctx context.Context, users ports.UserRepo, emails []string) map[string]string {
+	out := make(map[string]string, len(emails))
+	for _, e := range emails {
+		out[strings.ToLower(e)] = "ru"
+	}
+	prefs, err := users.PreferredLangs(ctx, emails)
+	if err != nil {
+		println("Warning: failed to load preferred languages of recipients:", err.Error())
+		return out
+	}
+	for email, lang := range prefs {
+		out[email] = SafeLang(lang)
+	}
+	return out
+}
+
+// sendPerLang renders a template once per language and mails every
+// recipient the version in their own language.
+func sendPerLang(ctx context.Context, users ports.UserRepo, mailer ports.Mailer, recipients []string, kind string,
+	tpl func(lang string) (string, string, string)) {
+	langs := recipientLangs(ctx, users, recipients)
+	type rendered struct{ subject, html, text string }
+	cache := map[string]rendered{}
+	for _, to := range recipients {
+		lang := langs[strings.ToLower(to)]
+		m, ok := cache[lang]
+		if !ok {
+			m.subject, m.html, m.text = tpl(lang)
+			cache[lang] = m
+		}
+		if err := mailer.Send(ctx, to, m.subject, m.html, m.text); err != nil {
+			println("Warning: failed to send", kind, "email to", to, ":", err.Error())
+		}
+	}
+}
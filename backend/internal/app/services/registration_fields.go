@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+var fieldKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// FieldValidationError reports the first registration answer that failed validation.
+type FieldValidationError struct {
+	Key    string
+	Reason string // required, invalid_type, invalid_option, too_short, too_long, pattern, date_range, unknown_field
+}
+
+func (e *FieldValidationError) Error() string {
+	return "field " + e.Key + ": " + e.Reason
+}
+
+// FieldDefinitionError reports a stored field definition that answers cannot
+// be checked against, such as a pattern that does not compile. It is a
+// configuration problem, not something the applicant can fix.
+type FieldDefinitionError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldDefinitionError) Error() string {
+	return "field " + e.Key + " definition: " + e.Err.Error()
+}
+
+func (e *FieldDefinitionError) Unwrap() error { return e.Err }
+
+func (s *RegistrationService) ListFields(ctx context.Context, activeOnly bool) ([]domain.RegistrationField, error) {
+	return s.fields.List(ctx, activeOnly)
+}
+
+func (s *RegistrationService) CreateField(ctx context.Context, f domain.RegistrationField) (uuid.UUID, error) {
+	if err := validateFieldDefinition(&f); err != nil {
+		return uuid.Nil, err
+	}
+	return s.fields.Create(ctx, f)
+}
+
+func (s *RegistrationService) UpdateField(ctx context.Context, f domain.RegistrationField) error {
+	if err := validateFieldDefinition(&f); err != nil {
+		return err
+	}
+	return s.fields.Update(ctx, f)
+}
+
+// DeleteField removes the definition. Stored answers keep the value under the
+// old key, so deactivating a field is usually the better option.
+func (s *RegistrationService) DeleteField(ctx context.Context, id uuid.UUID) error {
+	return s.fields.Delete(ctx, id)
+}
+
+func (s *RegistrationService) Answers(ctx context.Context, userID uuid.UUID) (map[string]any, error) {
+	return s.answers.Get(ctx, userID)
+}
+
+// FieldByKey returns the active field with the given key.
+func (s *RegistrationService) FieldByKey(ctx context.Context, key string) (*domain.RegistrationField, error) {
+	fields, err := s.fields.List(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.Key == key {
+			return &f, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func validateFieldDefinition(f *domain.RegistrationField) error {
+	f.Key = strings.TrimSpace(f.Key)
+	f.LabelRu = strings.TrimSpace(f.LabelRu)
+	f.LabelEn = strings.TrimSpace(f.LabelEn)
+	if !fieldKeyRe.MatchString(f.Key) || f.LabelRu == "" || f.LabelEn == "" {
+		return domain.ErrInvalidInput
+	}
+	v := f.Validation
+	switch f.Type {
+	case domain.FieldText:
+		if err := f.Validation.CompilePattern(); err != nil {
+			return domain.ErrInvalidInput
+		}
+		if (v.MinLength != nil && *v.MinLength < 0) || (v.MaxLength != nil && *v.MaxLength < 1) ||
+			(v.MinLength != nil && v.MaxLength != nil && *v.MinLength > *v.MaxLength) {
+			return domain.ErrInvalidInput
+		}
+	case domain.FieldSelect:
+		if len(f.Options) == 0 {
+			return domain.ErrInvalidInput
+		}
+		seen := map[string]bool{}
+		for _, o := range f.Options {
+			if strings.TrimSpace(o.Value) == "" || seen[o.Value] {
+				return domain.ErrInvalidInput
+			}
+			seen[o.Value] = true
+		}
+	case domain.FieldDate:
+		for _, d := range []string{v.MinDate, v.MaxDate} {
+			if d == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return domain.ErrInvalidInput
+			}
+		}
+	case domain.FieldCheckbox, domain.FieldFile:
+	default:
+		return domain.ErrInvalidInput
+	}
+	if f.Type != domain.FieldSelect {
+		f.Options = nil
+	}
+	if f.Type != domain.FieldText {
+		f.Validation.MinLength, f.Validation.MaxLength, f.Validation.Pattern = nil, nil, ""
+	}
+	if f.Type != domain.FieldDate {
+		f.Validation.MinDate, f.Validation.MaxDate = "", ""
+	}
+	return nil
+}
+
+// validateAnswers checks submitted answers against the active fields and
+// returns the normalized map to store. Empty values are dropped.
+func validateAnswers(fields []domain.RegistrationField, in map[string]any, userID uuid.UUID) (map[string]any, error) {
+	known := map[string]bool{}
+	out := map[string]any{}
+	for _, f := range fields {
+		known[f.Key] = true
+		raw, ok := in[f.Key]
+		if ok && raw == nil {
+			ok = false
+		}
+		if s, isStr := raw.(string); ok && isStr && strings.TrimSpace(s) == "" {
+			ok = false
+		}
+		if !ok {
+			if f.Required {
+				return nil, &FieldValidationError{Key: f.Key, Reason: "required"}
+			}
+			continue
+		}
+		v, reason, err := checkAnswer(f, raw, userID)
+		if err != nil {
+			return nil, &FieldDefinitionError{Key: f.Key, Err: err}
+		}
+		if reason != "" {
+			return nil, &FieldValidationError{Key: f.Key, Reason: reason}
+		}
+		out[f.Key] = v
+	}
+	for k := range in {
+		if !known[k] {
+			return nil, &FieldValidationError{Key: k, Reason: "unknown_field"}
+		}
+	}
+	return out, nil
+}
+
+// checkAnswer returns the normalized value, or the reason the answer is
+// rejected. An error means the definition itself cannot be applied.
+func checkAnswer(f domain.RegistrationField, raw any, userID uuid.UUID) (any, string, error) {
+	if f.Type == domain.FieldCheckbox {
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, "invalid_type", nil
+		}
+		if f.Required && !b {
+			return nil, "required", nil
+		}
+		return b, "", nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return nil, "invalid_type", nil
+	}
+	s = strings.TrimSpace(s)
+	v := f.Validation
+	switch f.Type {
+	case domain.FieldText:
+		n := utf8.RuneCountInString(s)
+		if v.MinLength != nil && n < *v.MinLength {
+			return nil, "too_short", nil
+		}
+		if v.MaxLength != nil && n > *v.MaxLength {
+			return nil, "too_long", nil
+		}
+		ok, err := v.MatchPattern(s)
+		if err != nil {
+			return nil, "", err
+		}
+		if !ok {
+			return nil, "pattern", nil
+		}
+	case domain.FieldSelect:
+		found := false
+		for _, o := range f.Options {
+			if o.Value == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, "invalid_option", nil
+		}
+	case domain.FieldDate:
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, "invalid_type", nil
+		}
+		// ISO dates compare correctly as strings
+		if (v.MinDate != "" && s < v.MinDate) || (v.MaxDate != "" && s > v.MaxDate) {
+			return nil, "date_range", nil
+		}
+	case domain.FieldFile:
+		// only keys of files uploaded by the user for this field are accepted
+		prefix := RegistrationFileKeyPrefix(userID, f.Key)
+		name := strings.TrimPrefix(s, prefix)
+		if !strings.HasPrefix(s, prefix) || name == "" || name == "." || name == ".." ||
+			strings.ContainsAny(name, "/\\") {
+			return nil, "invalid_type", nil
+		}
+	}
+	return s, "", nil
+}
+
+// RegistrationFileKeyPrefix is the storage key prefix for uploads answering a file field.
+func RegistrationFileKeyPrefix(userID uuid.UUID, fieldKey string) string {
+	return "registration/" + userID.String() + "/" + fieldKey + "/"
+}
+
+// formatAnswer renders a stored answer for exports; select values are shown
+// with their Russian label.
+func formatAnswer(f domain.RegistrationField, v any) string {
+	if v == nil {
+		return ""
+	}
+	switch f.Type {
+	case domain.FieldCheckbox:
+		if b, _ := v.(bool); b {
+			return "yes"
+		}
+		return "no"
+	case domain.FieldSelect:
+		for _, o := range f.Options {
+			if o.Value == v {
+				return o.LabelRu
+			}
+		}
+	}
+	return fmt.Sprint(v)
+}
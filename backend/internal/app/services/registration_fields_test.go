@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func intPtr(n int) *int { return &n }
+
+func testFields(t *testing.T) []domain.RegistrationField {
+	t.Helper()
+	fields := []domain.RegistrationField{
+		{Key: "city", Type: domain.FieldText, LabelRu: "Город", LabelEn: "City", Required: true,
+			Validation: domain.RegistrationFieldValidation{MinLength: intPtr(2), MaxLength: intPtr(5)}},
+		{Key: "orcid", Type: domain.FieldText, LabelRu: "ORCID", LabelEn: "ORCID",
+			Validation: domain.RegistrationFieldValidation{Pattern: `^\d{4}-\d{4}-\d{4}-\d{3}[\dX]$`}},
+		{Key: "meal", Type: domain.FieldSelect, LabelRu: "Питание", LabelEn: "Meal",
+			Options: []domain.RegistrationFieldOption{{Value: "veg"}, {Value: "any"}}},
+		{Key: "excursion", Type: domain.FieldCheckbox, LabelRu: "Экскурсия", LabelEn: "Excursion"},
+		{Key: "agree", Type: domain.FieldCheckbox, LabelRu: "Согласие", LabelEn: "Agreement", Required: true},
+		{Key: "arrival", Type: domain.FieldDate, LabelRu: "Приезд", LabelEn: "Arrival",
+			Validation: domain.RegistrationFieldValidation{MinDate: "2026-05-10", MaxDate: "2026-05-15"}},
+		{Key: "visa", Type: domain.FieldFile, LabelRu: "Виза", LabelEn: "Visa"},
+	}
+	for i := range fields {
+		if err := validateFieldDefinition(&fields[i]); err != nil {
+			t.Fatalf("%s: %v", fields[i].Key, err)
+		}
+	}
+	return fields
+}
+
+func TestValidateAnswers(t *testing.T) {
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	fields := testFields(t)
+	visa := RegistrationFileKeyPrefix(userID, "visa") + "scan.pdf"
+	base := func(extra map[string]any) map[string]any {
+		in := map[string]any{"city": " Omsk ", "agree": true}
+		for k, v := range extra {
+			in[k] = v
+		}
+		return in
+	}
+
+	tests := []struct {
+		name       string
+		in         map[string]any
+		want       map[string]any
+		key, wrong string // expected FieldValidationError
+	}{
+		{name: "minimal", in: base(nil), want: map[string]any{"city": "Omsk", "agree": true}},
+		{name: "all fields", in: base(map[string]any{
+			"orcid": "0000-0002-1825-009X", "meal": "veg", "excursion": false, "arrival": "2026-05-12", "visa": visa,
+		}), want: map[string]any{
+			"city": "Omsk", "agree": true, "orcid": "0000-0002-1825-009X", "meal": "veg", "excursion": false,
+			"arrival": "2026-05-12", "visa": visa,
+		}},
+		{name: "empty optional dropped", in: base(map[string]any{"orcid": "  ", "meal": nil}),
+			want: map[string]any{"city": "Omsk", "agree": true}},
+		{name: "missing required", in: map[string]any{"agree": true}, key: "city", wrong: "required"},
+		{name: "blank required", in: base(map[string]any{"city": " "}), key: "city", wrong: "required"},
+		{name: "unchecked required", in: base(map[string]any{"agree": false}), key: "agree", wrong: "required"},
+		{name: "too short", in: base(map[string]any{"city": "O"}), key: "city", wrong: "too_short"},
+		{name: "too long counts runes", in: base(map[string]any{"city": "Москва"}), key: "city", wrong: "too_long"},
+		{name: "not a string", in: base(map[string]any{"city": 42.0}), key: "city", wrong: "invalid_type"},
+		{name: "not a bool", in: base(map[string]any{"excursion": "yes"}), key: "excursion", wrong: "invalid_type"},
+		{name: "pattern", in: base(map[string]any{"orcid": "0000-0002-1825"}), key: "orcid", wrong: "pattern"},
+		{name: "unknown option", in: base(map[string]any{"meal": "fish"}), key: "meal", wrong: "invalid_option"},
+		{name: "bad date", in: base(map[string]any{"arrival": "12.05.2026"}), key: "arrival", wrong: "invalid_type"},
+		{name: "date before range", in: base(map[string]any{"arrival": "2026-05-09"}), key: "arrival", wrong: "date_range"},
+		{name: "date after range", in: base(map[string]any{"arrival": "2026-05-16"}), key: "arrival", wrong: "date_range"},
+		{name: "file of another user", in: base(map[string]any{
+			"visa": RegistrationFileKeyPrefix(uuid.New(), "visa") + "scan.pdf",
+		}), key: "visa", wrong: "invalid_type"},
+		{name: "file of another field", in: base(map[string]any{
+			"visa": RegistrationFileKeyPrefix(userID, "photo") + "scan.pdf",
+		}), key: "visa", wrong: "invalid_type"},
+		{name: "file path escape", in: base(map[string]any{
+			"visa": RegistrationFileKeyPrefix(userID, "visa") + "../x",
+		}), key: "visa", wrong: "invalid_type"},
+		{name: "unknown field", in: base(map[string]any{"shoe_size": "42"}), key: "shoe_size", wrong: "unknown_field"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := validateAnswers(fields, tc.in, userID)
+			if tc.wrong != "" {
+				var fe *FieldValidationError
+				if !errors.As(err, &fe) || fe.Key != tc.key || fe.Reason != tc.wrong {
+					t.Fatalf("err = %v, want field %s: %s", err, tc.key, tc.wrong)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("answers = %v, want %v", got, tc.want)
+			}
+			for k, v := range tc.want {
+				if got[k] != v {
+					t.Errorf("%s = %#v, want %#v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAnswerBadPatternIsDefinitionError(t *testing.T) {
+	userID := uuid.New()
+	valid := domain.RegistrationField{Key: "code", Type: domain.FieldText, LabelRu: "Код", LabelEn: "Code",
+		Validation: domain.RegistrationFieldValidation{Pattern: `^[A-Z]+$`}}
+
+	// never compiled, e.g. built by hand instead of loaded or saved
+	if _, _, err := checkAnswer(valid, "ABC", userID); err == nil {
+		t.Error("uncompiled pattern: want an error")
+	}
+
+	broken := valid
+	broken.Validation.Pattern = `([A-Z]`
+	if err := validateFieldDefinition(&broken); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("saving a bad pattern: err = %v, want ErrInvalidInput", err)
+	}
+	// as loaded from the database, where the stored pattern no longer compiles
+	if broken.Validation.CompilePattern() == nil {
+		t.Fatal("want a compile error")
+	}
+	_, reason, err := checkAnswer(broken, "ABC", userID)
+	if err == nil || reason != "" {
+		t.Fatalf("checkAnswer = %q, %v; want a definition error", reason, err)
+	}
+	_, err = validateAnswers([]domain.RegistrationField{broken}, map[string]any{"code": "ABC"}, userID)
+	var de *FieldDefinitionError
+	var fe *FieldValidationError
+	if !errors.As(err, &de) || de.Key != "code" || errors.As(err, &fe) {
+		t.Errorf("validateAnswers err = %v, want a FieldDefinitionError for code", err)
+	}
+
+	if err := validateFieldDefinition(&valid); err != nil {
+		t.Fatal(err)
+	}
+	for s, want := range map[string]string{"ABC": "", "abc": "pattern"} {
+		if _, reason, err := checkAnswer(valid, s, userID); err != nil || reason != want {
+			t.Errorf("checkAnswer(%q) = %q, %v; want %q", s, reason, err, want)
+		}
+	}
+}
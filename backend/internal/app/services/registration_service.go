@@ -15,15 +15,35 @@ type RegistrationService struct {
 	cfg       AppConfig
 	users     ports.UserRepo
 	profiles  ports.ProfileRepo
+	fields    ports.RegistrationFieldRepo
+	answers   ports.RegistrationAnswerRepo
+	talks     ports.TalkRepo
+	sections  ports.SectionRepo
+	waitlist  ports.WaitlistRepo
 	mailer    ports.Mailer
 	templates EmailTemplates
+	clock     ports.Clock
 }
 
-func NewRegistrationService(cfg AppConfig, u ports.UserRepo, p ports.ProfileRepo, m ports.Mailer, t EmailTemplates) *RegistrationService {
-	return &RegistrationService{cfg: cfg, users: u, profiles: p, mailer: m, templates: t}
+func NewRegistrationService(
+	cfg AppConfig,
+	u ports.UserRepo,
+	p ports.ProfileRepo,
+	f ports.RegistrationFieldRepo,
+	a ports.RegistrationAnswerRepo,
+	tr ports.TalkRepo,
+	sr ports.SectionRepo,
+	wr ports.WaitlistRepo,
+	m ports.Mailer,
+	t EmailTemplates,
+	clock ports.Clock,
+) *RegistrationService {
+	return &RegistrationService{cfg: cfg, users: u, profiles: p, fields: f, answers: a, talks: tr, sections: sr, waitlist: wr, mailer: m, templates: t, clock: clock}
 }
 
-func (s *RegistrationService) Submit(ctx context.Context, userID uuid.UUID, profile domain.Profile, lang string) error {
+// Submit stores the profile and the answers to the organizer-defined fields.
+// Invalid answers are reported as *FieldValidationError.
+func (s *RegistrationService) Submit(ctx context.Context, userID uuid.UUID, profile domain.Profile, answers map[string]any, lang string) error {
 	u, _, err := s.users.ByID(ctx, userID)
 	if err != nil {
 		return err
@@ -34,26 +54,34 @@ func (s *RegistrationService) Submit(ctx context.Context, userID uuid.UUID, prof
 	if !profile.ConsentDataProcessing || !profile.ConsentDataTransfer {
 		return errors.New("all consents required")
 	}
+	fields, err := s.fields.List(ctx, true)
+	if err != nil {
+		return err
+	}
+	extra, err := validateAnswers(fields, answers, userID)
+	if err != nil {
+		return err
+	}
 
 	profile.UserID = userID
 	if err := s.profiles.Upsert(ctx, profile); err != nil {
 		return err
 	}
+	if err := s.answers.Upsert(ctx, userID, extra); err != nil {
+		return err
+	}
 
 	// user email: received
-	subj, html, text := s.templates.RegistrationReceived(SafeLang(lang))
+	subj, html, text := s.templates.RegistrationReceived(UserLang(u, lang))
 	if err := s.mailer.Send(ctx, u.Email, subj, html, text); err != nil {
 		println("Warning: failed to send registration-received email to", u.Email, ":", err.Error())
 	}
 
 	// org email: new registration
 	fullName := strings.TrimSpace(profile.Surname + " " + profile.Name + " " + profile.Patronymic)
-	for _, org := range s.cfg.OrganizerEmails {
-		subj2, html2, text2 := s.templates.OrgNewRegistration(SafeLang(lang), fullName, profile.Affiliation, profile.City, u.Email)
-		if err := s.mailer.Send(ctx, org, subj2, html2, text2); err != nil {
-			println("Warning: failed to send org new-registration email to", org, ":", err.Error())
-		}
-	}
+	sendPerLang(ctx, s.users, s.mailer, s.cfg.OrganizerEmails, "org new-registration", func(lang string) (string, string, string) {
+		return s.templates.OrgNewRegistration(lang, fullName, profile.Affiliation, profile.City, u.Email)
+	})
 
 	return nil
 }
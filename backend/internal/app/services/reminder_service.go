@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// ReminderService nudges participants about unfinished checklist items:
+// consent scans, signed license agreements and talk files. Each kind is sent
+// to a user at most once per ReminderInterval.
+type ReminderService struct {
+	repo      ports.ReminderRepo
+	mailer    ports.Mailer
+	templates EmailTemplates
+	clock     ports.Clock
+	cfg       AppConfig
+}
+
+func NewReminderService(repo ports.ReminderRepo, mailer ports.Mailer, templates EmailTemplates, clock ports.Clock, cfg AppConfig) *ReminderService {
+	return &ReminderService{repo: repo, mailer: mailer, templates: templates, clock: clock, cfg: cfg}
+}
+
+// SendDue mails one combined reminder to every user with due items. A failed
+// send is not recorded, so the user is retried on the next run.
+func (s *ReminderService) SendDue(ctx context.Context) error {
+	now := s.clock.Now()
+	if s.cfg.ReminderInterval <= 0 || (s.cfg.ReminderCutoff != nil && !now.Before(*s.cfg.ReminderCutoff)) {
+		return nil
+	}
+	candidates, err := s.repo.Due(ctx, now.Add(-s.cfg.ReminderInterval))
+	if err != nil {
+		return err
+	}
+	cabinetURL := joinURL(s.cfg.AppURL, "/cabinet")
+	for _, c := range candidates {
+		subject, html, text := s.templates.MissingItemsReminder(c.Lang, ReminderPayload{
+			MissingConsents: c.MissingConsents,
+			LicenseTalks:    c.LicenseTalks,
+			FileTalks:       c.FileTalks,
+			CabinetURL:      cabinetURL,
+		})
+		if err := s.mailer.Send(ctx, c.Email, subject, html, text); err != nil {
+			println("Warning: failed to send reminder to", c.Email, ":", err.Error())
+			continue
+		}
+		if err := s.repo.Record(ctx, remindedItems(c, s.clock.Now())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Report lists the reminders sent in [from, to).
+func (s *ReminderService) Report(ctx context.Context, from, to *time.Time) ([]domain.ReminderSent, error) {
+	return s.repo.Sent(ctx, from, to)
+}
+
+func remindedItems(c domain.ReminderCandidate, at time.Time) []domain.ReminderSent {
+	var out []domain.ReminderSent
+	item := func(kind domain.ReminderKind, talks []string) {
+		out = append(out, domain.ReminderSent{UserID: c.UserID, Email: c.Email, Kind: kind, Talks: talks, SentAt: at})
+	}
+	if c.MissingConsents {
+		item(domain.ReminderConsents, nil)
+	}
+	if len(c.LicenseTalks) > 0 {
+		item(domain.ReminderLicenseAgreement, c.LicenseTalks)
+	}
+	if len(c.FileTalks) > 0 {
+		item(domain.ReminderTalkFile, c.FileTalks)
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// DigestService sends section responsibles who chose the daily digest one
+// summary per day instead of an email per uploaded file.
+type DigestService struct {
+	sections  ports.SectionRepo
+	mailer    ports.Mailer
+	templates EmailTemplates
+	clock     ports.Clock
+	cfg       AppConfig
+}
+
+func NewDigestService(sections ports.SectionRepo, mailer ports.Mailer, templates EmailTemplates, clock ports.Clock, cfg AppConfig) *DigestService {
+	return &DigestService{sections: sections, mailer: mailer, templates: templates, clock: clock, cfg: cfg}
+}
+
+// SetMode switches a responsible address between immediate emails and the
+// daily digest.
+func (s *DigestService) SetMode(ctx context.Context, email string, mode domain.NotifyMode) error {
+	if mode != domain.NotifyImmediate && mode != domain.NotifyDigest {
+		return domain.ErrInvalidInput
+	}
+	return s.sections.SetResponsibleMode(ctx, strings.ToLower(strings.TrimSpace(email)), mode)
+}
+
+// SendDue sends the digests that have not gone out since the last DigestHour.
+// It is meant to run hourly; a digest with nothing to report is skipped but
+// still closes the period.
+func (s *DigestService) SendDue(ctx context.Context) error {
+	now := s.clock.Now().UTC()
+	dueAt := time.Date(now.Year(), now.Month(), now.Day(), s.cfg.DigestHour, 0, 0, 0, time.UTC)
+	if now.Before(dueAt) {
+		dueAt = dueAt.AddDate(0, 0, -1)
+	}
+	recipients, err := s.sections.DigestRecipients(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range recipients {
+		if r.LastDigestAt != nil && !r.LastDigestAt.Before(dueAt) {
+			continue
+		}
+		since := now.AddDate(0, 0, -1)
+		if r.LastDigestAt != nil {
+			since = *r.LastDigestAt
+		}
+		talks, err := s.sections.DigestTalks(ctx, r.SectionIDs, since)
+		if err != nil {
+			return err
+		}
+		if len(talks) > 0 {
+			subject, html, text := s.templates.ResponsibleDigest(r.Lang, s.payload(r.Lang, since, talks))
+			if err := s.mailer.Send(ctx, r.Email, subject, html, text); err != nil {
+				println("Warning: failed to send responsible digest to", r.Email, ":", err.Error())
+				continue
+			}
+		}
+		if err := s.sections.MarkDigestSent(ctx, r.Email, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// payload sorts the talks into new, revised and pending; a talk appears in
+// the first group that matches.
+func (s *DigestService) payload(lang string, since time.Time, talks []domain.DigestTalk) DigestPayload {
+	p := DigestPayload{
+		Since:        since,
+		NewTalks:     []DigestItem{},
+		RevisedTalks: []DigestItem{},
+		PendingTalks: []DigestItem{},
+		AdminURL:     joinURL(s.cfg.AppURL, "/admin/talks"),
+	}
+	for _, t := range talks {
+		item := DigestItem{
+			Title:   t.Title,
+			Speaker: t.Speaker,
+			Section: t.SectionTitleRu,
+			Kind:    string(t.Kind),
+			URL:     adminSectionTalksURL(s.cfg.AppURL, t.SectionID),
+		}
+		if lang == "en" {
+			item.Section = t.SectionTitleEn
+		}
+		switch {
+		case t.CreatedAt.After(since):
+			p.NewTalks = append(p.NewTalks, item)
+		case t.RevisedAt != nil && t.RevisedAt.After(since):
+			p.RevisedTalks = append(p.RevisedTalks, item)
+		default:
+			p.PendingTalks = append(p.PendingTalks, item)
+		}
+	}
+	return p
+}
+
+func adminSectionTalksURL(appURL string, sectionID uuid.UUID) string {
+	return joinURL(appURL, "/admin/talks?section="+sectionID.String())
+}
@@ -5,11 +5,45 @@ import (
 )
 
 type AppConfig struct {
-	AppURL          string
-	AccessTTL       time.Duration
-	RefreshTTL      time.Duration
-	VerifyEmailTTL  time.Duration
+	AppURL     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// ImpersonationTTL is the lifetime of an admin impersonation access token.
+	ImpersonationTTL time.Duration
+	VerifyEmailTTL   time.Duration
+	// UnverifiedMaxAge is how long never-verified accounts are kept; 0 keeps them forever.
+	UnverifiedMaxAge time.Duration
+	// CapacityCategoryField is the registration field holding the participation
+	// category used for per-category capacity; empty means only the total applies.
+	CapacityCategoryField string
+	// DuplicateThreshold is the title or abstract similarity that flags two talks.
+	DuplicateThreshold float64
+	// CampaignBatchSize is how many campaign emails one delivery run sends.
+	CampaignBatchSize int
+	// ReminderInterval is the reminder cadence; 0 disables reminders.
+	ReminderInterval time.Duration
+	// ReminderCutoff stops reminders from this moment; nil means never.
+	ReminderCutoff *time.Time
+	// DigestHour is the UTC hour (0-23) of the daily responsible digest.
+	DigestHour int
+	// ContentLocales are the locales pages, news, sections and materials can be
+	// translated to, besides ru and en; LocaleFallback orders the fallbacks.
+	ContentLocales  []string
+	LocaleFallback  []string
 	CookieSecure    bool
 	CookieDomain    string
 	OrganizerEmails []string
+	Login           LoginPolicy
+	// OIDCRedirectBase is the public API base URL used to build OIDC callback URLs.
+	OIDCRedirectBase string
+}
+
+// LoginPolicy configures brute-force protection per login email.
+type LoginPolicy struct {
+	MaxFailures   int           // failures within FailureWindow before the email is locked
+	FailureWindow time.Duration // failures older than this no longer count
+	Lockout       time.Duration
+	DelayAfter    int // failures tolerated before progressive delays kick in
+	DelayBase     time.Duration
+	DelayMax      time.Duration
 }
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"confsite/backend/internal/ports"
+)
+
+// SitemapEntry is one public page of the site; LastMod is nil when the page
+// is static or its age is unknown.
+type SitemapEntry struct {
+	Path    string
+	LastMod *time.Time
+}
+
+// SitemapService lists the public pages of the frontend for search engines.
+// Sections have no page of their own: they count towards the home page and
+// the program.
+type SitemapService struct {
+	pages  ports.PageRepo
+	news   *NewsService
+	stamps ports.SitemapRepo
+}
+
+func NewSitemapService(pages ports.PageRepo, news *NewsService, stamps ports.SitemapRepo) *SitemapService {
+	return &SitemapService{pages: pages, news: news, stamps: stamps}
+}
+
+func (s *SitemapService) Entries(ctx context.Context) ([]SitemapEntry, error) {
+	stamps, err := s.stamps.LastModified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	news, err := s.news.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := s.pages.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var newsLast *time.Time
+	newsEntries := make([]SitemapEntry, 0, len(news))
+	for _, n := range news {
+		lm := latest(&n.UpdatedAt, n.PublishedAt)
+		if tr, ok := stamps.NewsTranslations[n.ID]; ok {
+			lm = latest(lm, &tr)
+		}
+		newsLast = latest(newsLast, lm)
+		newsEntries = append(newsEntries, SitemapEntry{Path: "/news/" + n.ID.String(), LastMod: lm})
+	}
+
+	out := []SitemapEntry{
+		{Path: "/", LastMod: latest(newsLast, stamps.Sections)},
+		{Path: "/news", LastMod: newsLast},
+		{Path: "/program", LastMod: latest(stamps.Program, stamps.Sections)},
+		{Path: "/materials", LastMod: stamps.Materials},
+		{Path: "/participants"},
+		{Path: "/important-dates"},
+		{Path: "/fee"},
+		{Path: "/history"},
+	}
+	for _, p := range pages {
+		updated := p.UpdatedAt
+		lm := &updated
+		if tr, ok := stamps.PageTranslations[p.Slug]; ok {
+			lm = latest(lm, &tr)
+		}
+		out = append(out, SitemapEntry{Path: "/page/" + p.Slug, LastMod: lm})
+	}
+	return append(out, newsEntries...), nil
+}
+
+// latest returns the later of two optional times.
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"html"
 	"strings"
 	"unicode/utf8"
 
@@ -16,17 +14,19 @@ import (
 )
 
 type TalkService struct {
-	cfg       AppConfig
-	talks     ports.TalkRepo
-	profiles  ports.ProfileRepo
-	sections  ports.SectionRepo
-	users     ports.UserRepo
-	mailer    ports.Mailer
-	templates EmailTemplates
+	cfg        AppConfig
+	talks      ports.TalkRepo
+	profiles   ports.ProfileRepo
+	sections   ports.SectionRepo
+	users      ports.UserRepo
+	duplicates ports.DuplicateRepo
+	mailer     ports.Mailer
+	templates  EmailTemplates
+	clock      ports.Clock
 }
 
-func NewTalkService(cfg AppConfig, tr ports.TalkRepo, pr ports.ProfileRepo, sr ports.SectionRepo, ur ports.UserRepo, m ports.Mailer, t EmailTemplates) *TalkService {
-	return &TalkService{cfg: cfg, talks: tr, profiles: pr, sections: sr, users: ur, mailer: m, templates: t}
+func NewTalkService(cfg AppConfig, tr ports.TalkRepo, pr ports.ProfileRepo, sr ports.SectionRepo, ur ports.UserRepo, dr ports.DuplicateRepo, m ports.Mailer, t EmailTemplates, clock ports.Clock) *TalkService {
+	return &TalkService{cfg: cfg, talks: tr, profiles: pr, sections: sr, users: ur, duplicates: dr, mailer: m, templates: t, clock: clock}
 }
 
 func (s *TalkService) validateTalk(t domain.Talk) error {
@@ -56,36 +56,60 @@ func (s *TalkService) validateTalk(t domain.Talk) error {
 	return nil
 }
 
-func (s *TalkService) Create(ctx context.Context, speakerID uuid.UUID, t domain.Talk) (uuid.UUID, error) {
+// Create stores the talk and returns warnings about similar submissions.
+func (s *TalkService) Create(ctx context.Context, speakerID uuid.UUID, t domain.Talk) (uuid.UUID, []DuplicateWarning, error) {
 	t.SpeakerUserID = speakerID
 	t.Status = domain.TalkStatusWaiting
 	if err := s.validateTalk(t); err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, nil, err
 	}
 	cnt, err := s.talks.CountBySpeaker(ctx, speakerID)
 	if err == nil && cnt >= 3 {
-		return uuid.Nil, domain.ErrTalkLimitReached
+		return uuid.Nil, nil, domain.ErrTalkLimitReached
 	}
-	return s.talks.Create(ctx, t)
+	id, err := s.talks.Create(ctx, t)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return id, s.checkDuplicates(ctx, id, speakerID), nil
 }
 
-func (s *TalkService) Update(ctx context.Context, speakerID uuid.UUID, t domain.Talk) error {
+// Update saves the talk and returns warnings about similar submissions.
+func (s *TalkService) Update(ctx context.Context, speakerID uuid.UUID, t domain.Talk) ([]DuplicateWarning, error) {
 	orig, err := s.talks.Get(ctx, t.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if orig.SpeakerUserID != speakerID {
-		return domain.ErrForbidden
+		return nil, domain.ErrForbidden
+	}
+	if orig.Status == domain.TalkStatusWithdrawn {
+		return nil, domain.ErrInvalidState
 	}
 	t.SpeakerUserID = speakerID
 	t.Status = domain.TalkStatusWaiting
 	if err := s.validateTalk(t); err != nil {
-		return err
+		return nil, err
+	}
+	if err := s.talks.Update(ctx, t); err != nil {
+		return nil, err
 	}
-	return s.talks.Update(ctx, t)
+	return s.checkDuplicates(ctx, t.ID, speakerID), nil
 }
 
+// Delete removes a talk that is not in the program. Approved talks have to be
+// withdrawn instead so they stay visible to organizers.
 func (s *TalkService) Delete(ctx context.Context, speakerID, talkID uuid.UUID) error {
+	t, err := s.talks.Get(ctx, talkID)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	if t.SpeakerUserID != speakerID {
+		return domain.ErrForbidden
+	}
+	if t.Status == domain.TalkStatusApproved || t.Status == domain.TalkStatusWithdrawn {
+		return domain.ErrInvalidState
+	}
 	return s.talks.Delete(ctx, talkID, speakerID)
 }
 
@@ -118,7 +142,7 @@ func (s *TalkService) NotifyFileUploaded(ctx context.Context, speakerID, talkID
 	u, _, _ := s.users.ByID(ctx, speakerID)
 
 	// email to user
-	subj, html, text := s.templates.TalkFileUploadedToUser(SafeLang(lang), t.Title)
+	subj, html, text := s.templates.TalkFileUploadedToUser(UserLang(u, lang), t.Title)
 	_ = s.mailer.Send(ctx, u.Email, subj, html, text)
 
 	_ = s.notifyResponsibles(ctx, t, prof, fileURL)
@@ -135,6 +159,9 @@ func (s *TalkService) SetFileURL(ctx context.Context, speakerID, talkID uuid.UUI
 	if t.SpeakerUserID != speakerID {
 		return domain.ErrForbidden
 	}
+	if t.Status == domain.TalkStatusWithdrawn {
+		return domain.ErrInvalidState
+	}
 	// persist file URL
 	if err := s.talks.UpdateFile(ctx, talkID, fileURL); err != nil {
 		return err
@@ -158,62 +185,58 @@ func (s *TalkService) notifyResponsibles(ctx context.Context, t *domain.Talk, pr
 		recipients = append(recipients, e)
 	}
 
-	// Primary recipients: section responsibles.
+	// Primary recipients: section responsibles; those on the daily digest
+	// hear about the upload there.
+	hasResponsibles := false
 	if t.SectionID != nil {
 		all, err := s.sections.ListResponsibleEmails(ctx)
 		if err != nil {
 			return err
 		}
 		for _, item := range all {
-			if item.SectionID == *t.SectionID {
+			if item.SectionID != *t.SectionID {
+				continue
+			}
+			hasResponsibles = true
+			if item.Mode != domain.NotifyDigest {
 				addRecipient(item.Email)
 			}
 		}
 	}
 
 	// Fallback recipients: organizers from config.
-	if len(recipients) == 0 {
+	if !hasResponsibles {
 		for _, org := range s.cfg.OrganizerEmails {
 			addRecipient(org)
 		}
 	}
 	if len(recipients) == 0 {
-		println("Warning: no recipients found for talk notification:", t.ID.String())
+		if !hasResponsibles {
+			println("Warning: no recipients found for talk notification:", t.ID.String())
+		}
 		return nil
 	}
 
-	authors := authorsToLine(t.AuthorsJSON)
-	subject := "Новый доклад на проверку: " + strings.TrimSpace(t.Title)
-	textBody := fmt.Sprintf(`«ФИЗИКА НИЗКОТЕМПЕРАТУРНОЙ ПЛАЗМЫ» (ФНТП-2026)
-
-Название доклада: %s
-Аннотация:
-%s
-
-Файл тезиса: %s
-Авторы: %s
-Докладчик: %s`,
-		strings.TrimSpace(t.Title),
-		strings.TrimSpace(t.Abstract),
-		strings.TrimSpace(fileURL),
-		strings.TrimSpace(authors),
-		strings.TrimSpace(fullName(prof)),
-	)
-	htmlBody := fmt.Sprintf(
-		"<p><strong>«ФИЗИКА НИЗКОТЕМПЕРАТУРНОЙ ПЛАЗМЫ» (ФНТП-2026)</strong></p><p><strong>Название доклада:</strong> %s</p><p><strong>Аннотация:</strong><br>%s</p><p><strong>Файл тезиса:</strong> <a href=\"%s\">%s</a></p><p><strong>Авторы:</strong> %s</p><p><strong>Докладчик:</strong> %s</p>",
-		html.EscapeString(strings.TrimSpace(t.Title)),
-		html.EscapeString(strings.TrimSpace(t.Abstract)),
-		html.EscapeString(strings.TrimSpace(fileURL)),
-		html.EscapeString(strings.TrimSpace(fileURL)),
-		html.EscapeString(strings.TrimSpace(authors)),
-		html.EscapeString(strings.TrimSpace(fullName(prof))),
-	)
-
-	for _, to := range recipients {
-		if err := s.mailer.Send(ctx, to, subject, htmlBody, textBody); err != nil {
-			println("Warning: failed to send talk notification email to", to, ":", err.Error())
-		}
+	payload := OrgTalkUploadedPayload{
+		SpeakerFullName: fullName(prof),
+		Title:           strings.TrimSpace(t.Title),
+		AuthorsLine:     authorsToLine(t.AuthorsJSON),
+		Abstract:        strings.TrimSpace(t.Abstract),
+		Kind:            string(t.Kind),
+		FileNoteOrURL:   strings.TrimSpace(fileURL),
+	}
+	if prof != nil {
+		payload.SpeakerAffiliation = prof.Affiliation
+		payload.SpeakerCity = prof.City
+	}
+	var section *domain.Section
+	if t.SectionID != nil {
+		section = findSection(ctx, s.sections, *t.SectionID)
 	}
+	sendPerLang(ctx, s.users, s.mailer, recipients, "talk notification", func(lang string) (string, string, string) {
+		payload.Section = sectionTitle(section, lang)
+		return s.templates.OrgTalkFileUploaded(lang, payload)
+	})
 	return nil
 }
 
@@ -239,3 +262,26 @@ func fullName(p *domain.Profile) string {
 func safeStr(s string) string {
 	return strings.TrimSpace(s)
 }
+
+func findSection(ctx context.Context, sections ports.SectionRepo, id uuid.UUID) *domain.Section {
+	list, err := sections.List(ctx)
+	if err != nil {
+		return nil
+	}
+	for i := range list {
+		if list[i].ID == id {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func sectionTitle(sec *domain.Section, lang string) string {
+	if sec == nil {
+		return ""
+	}
+	if lang == "en" && sec.TitleEn != "" {
+		return sec.TitleEn
+	}
+	return sec.TitleRu
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+)
+
+// TranslationService manages content in the enabled locales and picks the
+// best available translation for a requested locale. Emails stay ru/en.
+type TranslationService struct {
+	repo     ports.TranslationRepo
+	enabled  []string
+	fallback []string
+}
+
+// NewTranslationService: ru and en are always enabled; fallback is tried, in
+// order, after the requested locale and its base language, with ru last.
+func NewTranslationService(repo ports.TranslationRepo, cfg AppConfig) *TranslationService {
+	s := &TranslationService{repo: repo}
+	s.enabled = appendLocales([]string{"ru", "en"}, cfg.ContentLocales...)
+	for _, l := range cfg.LocaleFallback {
+		if s.Enabled(l) {
+			s.fallback = appendLocales(s.fallback, l)
+		}
+	}
+	s.fallback = appendLocales(s.fallback, "ru")
+	return s
+}
+
+// TranslatableItem carries an entity's own ru/en columns into Resolve.
+type TranslatableItem struct {
+	Key     string
+	TitleRu string
+	BodyRu  string
+	TitleEn string
+	BodyEn  string
+}
+
+// Localized is the text picked for one item; Locale is the one actually used
+// after falling back. UpdatedAt is set for the additional locales; ru and en
+// share the item's own timestamp.
+type Localized struct {
+	Locale    string     `json:"locale"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	UpdatedAt *time.Time `json:"-"`
+}
+
+func (s *TranslationService) Locales() []string  { return s.enabled }
+func (s *TranslationService) Fallback() []string { return s.fallback }
+
+func (s *TranslationService) Enabled(locale string) bool {
+	for _, l := range s.enabled {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Chain lists the locales tried for a request: the locale itself, its base
+// language (de-at -> de), then the configured fallback.
+func (s *TranslationService) Chain(locale string) []string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	var chain []string
+	if s.Enabled(locale) {
+		chain = appendLocales(chain, locale)
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok && s.Enabled(base) {
+		chain = appendLocales(chain, base)
+	}
+	return appendLocales(chain, s.fallback...)
+}
+
+func (s *TranslationService) List(ctx context.Context, entity domain.ContentEntity, key string) ([]domain.Translation, error) {
+	return s.repo.List(ctx, entity, key)
+}
+
+func (s *TranslationService) Put(ctx context.Context, t domain.Translation) error {
+	t.Locale = strings.ToLower(strings.TrimSpace(t.Locale))
+	t.Title = strings.TrimSpace(t.Title)
+	if !s.Enabled(t.Locale) || t.Title == "" {
+		return domain.ErrInvalidInput
+	}
+	if t.Entity == domain.ContentSection {
+		t.Body = ""
+	}
+	return s.repo.Upsert(ctx, t)
+}
+
+// Delete removes an extra locale; ru and en are part of the entity itself.
+func (s *TranslationService) Delete(ctx context.Context, entity domain.ContentEntity, key, locale string) error {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "ru" || locale == "en" {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.Delete(ctx, entity, key, locale)
+}
+
+// Resolve picks for each item the first locale of Chain(locale) that has a
+// title. Items without any translation fall back to their Russian columns.
+func (s *TranslationService) Resolve(ctx context.Context, entity domain.ContentEntity, locale string, items []TranslatableItem) (map[string]Localized, error) {
+	chain := s.Chain(locale)
+	extra := []string{}
+	for _, l := range chain {
+		if l != "ru" && l != "en" {
+			extra = append(extra, l)
+		}
+	}
+	stored := map[string]map[string]domain.Translation{}
+	if len(extra) > 0 && len(items) > 0 {
+		keys := make([]string, 0, len(items))
+		for _, it := range items {
+			keys = append(keys, it.Key)
+		}
+		rows, err := s.repo.ForKeys(ctx, entity, keys, extra)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range rows {
+			if stored[t.Key] == nil {
+				stored[t.Key] = map[string]domain.Translation{}
+			}
+			stored[t.Key][t.Locale] = t
+		}
+	}
+
+	out := make(map[string]Localized, len(items))
+	for _, it := range items {
+		res := Localized{Locale: "ru", Title: it.TitleRu, Body: it.BodyRu}
+		for _, l := range chain {
+			var cand Localized
+			switch l {
+			case "ru":
+				cand = Localized{Locale: l, Title: it.TitleRu, Body: it.BodyRu}
+			case "en":
+				cand = Localized{Locale: l, Title: it.TitleEn, Body: it.BodyEn}
+			default:
+				t := stored[it.Key][l]
+				cand = Localized{Locale: l, Title: t.Title, Body: t.Body, UpdatedAt: t.UpdatedAt}
+			}
+			if strings.TrimSpace(cand.Title) != "" {
+				res = cand
+				break
+			}
+		}
+		out[it.Key] = res
+	}
+	return out, nil
+}
+
+func appendLocales(list []string, locales ...string) []string {
+	for _, l := range locales {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if l == "" {
+			continue
+		}
+		dup := false
+		for _, have := range list {
+			if have == l {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, l)
+		}
+	}
+	return list
+}
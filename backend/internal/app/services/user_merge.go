@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// MergeUsers folds the source account into the target (see ports.UserRepo.Merge).
+// profileFromSource lists the profile fields whose source value wins. An
+// approved source keeps its place for the target; only when both accounts were
+// approved is a place freed for the waitlist.
+func (s *AdminService) MergeUsers(ctx context.Context, actorID, sourceID, targetID uuid.UUID, profileFromSource []string) (domain.UserMergeResult, error) {
+	source, _, err := s.users.ByID(ctx, sourceID)
+	if err != nil {
+		return domain.UserMergeResult{}, domain.ErrNotFound
+	}
+	target, _, err := s.users.ByID(ctx, targetID)
+	if err != nil {
+		return domain.UserMergeResult{}, domain.ErrNotFound
+	}
+	res, err := s.users.Merge(ctx, domain.UserMerge{
+		SourceID:          sourceID,
+		TargetID:          targetID,
+		ActorID:           actorID,
+		ProfileFromSource: profileFromSource,
+		CategoryField:     s.cfg.CapacityCategoryField,
+	})
+	if err != nil {
+		return res, err
+	}
+	if source.Status == domain.StatusApproved && target.Status == domain.StatusApproved {
+		s.promoteWaitlist(ctx)
+	}
+	return res, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"confsite/backend/internal/domain"
+)
+
+const (
+	resendMinInterval = time.Minute
+	resendDailyLimit  = 5
+)
+
+// VerificationThrottledError is returned when verification emails for the
+// address are requested too often.
+type VerificationThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationThrottledError) Error() string { return "too many verification emails" }
+func (e *VerificationThrottledError) Unwrap() error { return domain.ErrTooManyAttempts }
+
+// ResendVerification invalidates earlier links and mails a new one. Unknown and
+// already verified addresses succeed silently so the endpoint does not reveal
+// which accounts exist.
+func (s *AuthService) ResendVerification(ctx context.Context, email, lang string) error {
+	u, _, err := s.users.ByEmail(ctx, strings.TrimSpace(email))
+	if err != nil || u.EmailVerified {
+		return nil
+	}
+	now := s.clock.Now()
+	n, last, err := s.tokens.IssuedSince(ctx, u.ID, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if last != nil && now.Sub(*last) < resendMinInterval {
+		return &VerificationThrottledError{RetryAfter: resendMinInterval - now.Sub(*last)}
+	}
+	if n >= resendDailyLimit {
+		return &VerificationThrottledError{RetryAfter: time.Hour}
+	}
+	if err := s.tokens.InvalidateForUser(ctx, u.ID); err != nil {
+		return err
+	}
+	return s.sendVerification(ctx, u.ID, u.Email, UserLang(u, lang))
+}
+
+// PurgeUnverified deletes accounts that never verified their email within
+// UnverifiedMaxAge so the address can register again.
+func (s *AuthService) PurgeUnverified(ctx context.Context) error {
+	if s.cfg.UnverifiedMaxAge <= 0 {
+		return nil
+	}
+	n, err := s.users.DeleteUnverifiedBefore(ctx, s.clock.Now().Add(-s.cfg.UnverifiedMaxAge))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		if err := s.audit.Insert(ctx, domain.AuditLog{
+			Action:  "users.purge_unverified",
+			Entity:  "user",
+			Details: map[string]any{"count": n},
+		}); err != nil {
+			println("Warning: failed to write audit entry users.purge_unverified:", err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+func (s *AdminService) Capacity(ctx context.Context) (domain.CapacityLimits, error) {
+	return s.waitlist.Limits(ctx)
+}
+
+// SetCapacity replaces the limits. Raising a limit promotes waitlisted users
+// right away.
+func (s *AdminService) SetCapacity(ctx context.Context, l domain.CapacityLimits) error {
+	if l.Total != nil && *l.Total < 0 {
+		return domain.ErrInvalidInput
+	}
+	for _, n := range l.Categories {
+		if n < 0 {
+			return domain.ErrInvalidInput
+		}
+	}
+	if len(l.Categories) > 0 && s.cfg.CapacityCategoryField == "" {
+		return domain.ErrInvalidInput
+	}
+	if err := s.waitlist.SetLimits(ctx, l); err != nil {
+		return err
+	}
+	s.promoteWaitlist(ctx)
+	return nil
+}
+
+func (s *AdminService) Waitlist(ctx context.Context) ([]domain.WaitlistEntry, error) {
+	return s.waitlist.List(ctx, s.cfg.CapacityCategoryField)
+}
+
+// PromoteFromWaitlist approves a waitlisted user even when it exceeds capacity.
+// It runs under the capacity lock, like the automatic promotion.
+func (s *AdminService) PromoteFromWaitlist(ctx context.Context, userID uuid.UUID) error {
+	if err := s.waitlist.Promote(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrInvalidState) {
+			return domain.ErrInvalidInput
+		}
+		return err
+	}
+	u, _, err := s.users.ByID(ctx, userID)
+	if err != nil {
+		println("Warning: failed to load promoted user", userID.String(), ":", err.Error())
+		return nil
+	}
+	sendLocalized(ctx, s.mailer, u, s.templates.WaitlistPromoted)
+	return nil
+}
+
+func (s *AdminService) MoveInWaitlist(ctx context.Context, userID uuid.UUID, position int) error {
+	return s.waitlist.Move(ctx, userID, position)
+}
+
+func (s *AdminService) promoteWaitlist(ctx context.Context) {
+	promoteWaitlist(ctx, s.cfg, s.waitlist, s.users, s.mailer, s.templates)
+}
+
+// promoteWaitlist fills free places from the waitlist and notifies the
+// promoted users. Failures are logged; the triggering change already happened.
+func promoteWaitlist(ctx context.Context, cfg AppConfig, waitlist ports.WaitlistRepo, users ports.UserRepo, mailer ports.Mailer, templates EmailTemplates) {
+	ids, err := waitlist.PromoteNext(ctx, cfg.CapacityCategoryField)
+	if err != nil {
+		println("Warning: failed to promote waitlist:", err.Error())
+		return
+	}
+	for _, id := range ids {
+		u, _, err := users.ByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		sendLocalized(ctx, mailer, u, templates.WaitlistPromoted)
+	}
+}
+
+// sendLocalized sends a template to the user in their preferred language.
+func sendLocalized(ctx context.Context, mailer ports.Mailer, u *domain.User, tpl func(lang string) (string, string, string)) {
+	m := localized(u, tpl)
+	if err := mailer.Send(ctx, m.to, m.subject, m.html, m.text); err != nil {
+		println("Warning: failed to send user status email to", u.Email, ":", err.Error())
+	}
+}
+
+func localized(u *domain.User, tpl func(lang string) (string, string, string)) outgoingMail {
+	subj, html, text := tpl(UserLang(u, ""))
+	return outgoingMail{to: u.Email, subject: subj, html: html, text: text}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"confsite/backend/internal/domain"
+	"confsite/backend/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+const maxReasonLen = 1000
+
+func normalizeReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReasonLen {
+		return "", domain.ErrInvalidInput
+	}
+	return reason, nil
+}
+
+// Withdraw is the speaker's way out of the program. The talk is kept with
+// status WITHDRAWN, its schedule slot is freed and section responsibles and
+// organizers are notified.
+func (s *TalkService) Withdraw(ctx context.Context, speakerID, talkID uuid.UUID, reason string) error {
+	reason, err := normalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	t, err := s.talks.Get(ctx, talkID)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	if t.SpeakerUserID != speakerID {
+		return domain.ErrForbidden
+	}
+	if t.Status == domain.TalkStatusWithdrawn {
+		return domain.ErrInvalidState
+	}
+	if err := s.talks.Withdraw(ctx, talkID, reason, s.clock.Now()); err != nil {
+		return err
+	}
+	prof, _ := s.profiles.Get(ctx, speakerID)
+	notifyTalkWithdrawn(ctx, s.cfg, s.sections, s.users, s.mailer, s.templates, t, fullName(prof), reason)
+	return nil
+}
+
+// Cancel withdraws the participant's registration. Talks still under review or
+// in the program are withdrawn with the same reason, and a freed place goes to
+// the waitlist.
+func (s *RegistrationService) Cancel(ctx context.Context, userID uuid.UUID, reason string) error {
+	reason, err := normalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	u, _, err := s.users.ByID(ctx, userID)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	switch u.Status {
+	case domain.StatusWaiting, domain.StatusApproved, domain.StatusWaitlisted:
+	default:
+		return domain.ErrInvalidState
+	}
+	now := s.clock.Now()
+	if err := s.waitlist.Cancel(ctx, userID, reason, now); err != nil {
+		return err
+	}
+
+	prof, _ := s.profiles.Get(ctx, userID)
+	name := fullName(prof)
+	talks, err := s.talks.ListBySpeaker(ctx, userID)
+	if err != nil {
+		println("Warning: failed to list talks of cancelled participant", userID.String(), ":", err.Error())
+	}
+	for i := range talks {
+		t := &talks[i]
+		if t.Status != domain.TalkStatusWaiting && t.Status != domain.TalkStatusApproved {
+			continue
+		}
+		if err := s.talks.Withdraw(ctx, t.ID, reason, now); err != nil {
+			println("Warning: failed to withdraw talk", t.ID.String(), ":", err.Error())
+			continue
+		}
+		notifyTalkWithdrawn(ctx, s.cfg, s.sections, s.users, s.mailer, s.templates, t, name, reason)
+	}
+
+	sendPerLang(ctx, s.users, s.mailer, s.cfg.OrganizerEmails, "registration-cancelled", func(lang string) (string, string, string) {
+		return s.templates.OrgRegistrationCancelled(lang, name, u.Email, reason)
+	})
+
+	if u.Status == domain.StatusApproved {
+		promoteWaitlist(ctx, s.cfg, s.waitlist, s.users, s.mailer, s.templates)
+	}
+	return nil
+}
+
+// notifyTalkWithdrawn mails the section responsibles and the organizers.
+func notifyTalkWithdrawn(ctx context.Context, cfg AppConfig, sections ports.SectionRepo, users ports.UserRepo, mailer ports.Mailer, templates EmailTemplates, t *domain.Talk, speaker, reason string) {
+	recipients := []string{}
+	seen := map[string]bool{}
+	add := func(email string) {
+		e := strings.ToLower(strings.TrimSpace(email))
+		if e != "" && !seen[e] {
+			seen[e] = true
+			recipients = append(recipients, e)
+		}
+	}
+	var section *domain.Section
+	if t.SectionID != nil {
+		if all, err := sections.ListResponsibleEmails(ctx); err == nil {
+			for _, item := range all {
+				if item.SectionID == *t.SectionID {
+					add(item.Email)
+				}
+			}
+		}
+		section = findSection(ctx, sections, *t.SectionID)
+	}
+	for _, org := range cfg.OrganizerEmails {
+		add(org)
+	}
+
+	sendPerLang(ctx, users, mailer, recipients, "talk-withdrawn", func(lang string) (string, string, string) {
+		return templates.OrgTalkWithdrawn(lang, strings.TrimSpace(t.Title), sectionTitle(section, lang), speaker, reason)
+	})
+}
@@ -12,9 +12,15 @@ type DBConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     []byte
+	Secret     []byte // HS256 fallback when no key directory is configured
 	AccessTTL  time.Duration
 	RefreshTTL time.Duration
+	// ImpersonationTTL bounds admin "log in as" sessions.
+	ImpersonationTTL time.Duration
+	KeysDir          string // directory of <kid>.pem RSA/Ed25519 keys
+	ActiveKID        string // key used for signing; others only verify
+	Issuer           string
+	Audience         string
 }
 
 type SMTPConfig struct {
@@ -25,6 +31,44 @@ type SMTPConfig struct {
 	Pass string
 }
 
+// MailConfig selects the outgoing mail transport: "smtp" (default, with an
+// optional fallback relay), "sendmail" or "file" (.eml files in FileDir).
+// DKIM signing is enabled by DKIMKeyFile; DKIMDomain defaults to the From domain.
+type MailConfig struct {
+	Transport    string
+	Fallback     SMTPConfig
+	SendmailPath string
+	FileDir      string
+	DKIMDomain   string
+	DKIMSelector string
+	DKIMKeyFile  string
+}
+
+// LoginConfig holds brute-force protection thresholds, applied per login email.
+type LoginConfig struct {
+	MaxFailures   int
+	FailureWindow time.Duration
+	Lockout       time.Duration
+	DelayAfter    int
+	DelayBase     time.Duration
+	DelayMax      time.Duration
+}
+
+// OIDCProviderConfig describes an external OpenID Connect identity provider.
+type OIDCProviderConfig struct {
+	ID           string
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+type OIDCConfig struct {
+	RedirectBase string // public base URL of the API, callbacks go to {RedirectBase}/auth/oidc/{id}/callback
+	Providers    []OIDCProviderConfig
+}
+
 type StorageConfig struct {
 	Driver    string
 	LocalDir  string
@@ -43,8 +87,44 @@ type Config struct {
 	DB              DBConfig
 	JWT             JWTConfig
 	SMTP            SMTPConfig
+	Mail            MailConfig
+	Login           LoginConfig
+	OIDC            OIDCConfig
 	Storage         StorageConfig
 	OrganizerEmails []string
+	// UnverifiedMaxAge is how long never-verified accounts are kept; 0 disables the cleanup.
+	UnverifiedMaxAge time.Duration
+	// CapacityCategoryField is the registration field key whose answer is the
+	// participation category for per-category capacity limits.
+	CapacityCategoryField string
+	// DuplicateThreshold is the trigram similarity (0..1) of title or abstract
+	// at which a talk is reported as a possible duplicate.
+	DuplicateThreshold float64
+	// CampaignBatchSize and CampaignInterval pace campaign delivery: at most
+	// CampaignBatchSize emails per run, one run every CampaignInterval.
+	CampaignBatchSize int
+	CampaignInterval  time.Duration
+	// ReminderInterval is the minimum time between two reminders of the same
+	// kind to a user; 0 disables reminders. None are sent after ReminderCutoff.
+	ReminderInterval time.Duration
+	ReminderCutoff   *time.Time
+	// DigestHour is the UTC hour after which the daily responsible digest goes out.
+	DigestHour int
+	Bounces    BounceConfig
+	// ContentLocales are the enabled content locales (ru and en are always on);
+	// LocaleFallback is tried after the requested locale and its base language.
+	ContentLocales []string
+	LocaleFallback []string
+}
+
+// BounceConfig selects where bounce reports come from besides the webhook:
+// Source "maildir" polls MaildirPath, "fake" uses an in-memory source for
+// development, empty disables polling.
+type BounceConfig struct {
+	Source        string
+	MaildirPath   string
+	PollInterval  time.Duration
+	WebhookSecret string
 }
 
 func Load() Config {
@@ -52,16 +132,27 @@ func Load() Config {
 	refreshTTL, _ := strconv.Atoi(os.Getenv("REFRESH_TTL_DAYS"))
 	smtpPort, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
 
+	appURL := os.Getenv("APP_URL")
+	oidcRedirectBase := os.Getenv("OIDC_REDIRECT_BASE")
+	if oidcRedirectBase == "" {
+		oidcRedirectBase = strings.TrimRight(appURL, "/") + "/api"
+	}
+
 	return Config{
 		Env:    os.Getenv("APP_ENV"),
-		AppURL: os.Getenv("APP_URL"),
+		AppURL: appURL,
 		DB: DBConfig{
 			DSN: os.Getenv("DB_DSN"),
 		},
 		JWT: JWTConfig{
-			Secret:     []byte(os.Getenv("JWT_SECRET")),
-			AccessTTL:  time.Duration(accessTTL) * time.Minute,
-			RefreshTTL: time.Duration(refreshTTL) * 24 * time.Hour,
+			Secret:           []byte(os.Getenv("JWT_SECRET")),
+			AccessTTL:        time.Duration(accessTTL) * time.Minute,
+			RefreshTTL:       time.Duration(refreshTTL) * 24 * time.Hour,
+			ImpersonationTTL: time.Duration(envInt("IMPERSONATION_TTL_MIN", 30)) * time.Minute,
+			KeysDir:          os.Getenv("JWT_KEYS_DIR"),
+			ActiveKID:        os.Getenv("JWT_ACTIVE_KID"),
+			Issuer:           envOr("JWT_ISSUER", appURL),
+			Audience:         envOr("JWT_AUDIENCE", "confsite"),
 		},
 		SMTP: SMTPConfig{
 			Host: os.Getenv("SMTP_HOST"),
@@ -70,6 +161,32 @@ func Load() Config {
 			User: os.Getenv("SMTP_USER"),
 			Pass: os.Getenv("SMTP_PASS"),
 		},
+		Mail: MailConfig{
+			Transport: envOr("MAIL_TRANSPORT", "smtp"),
+			Fallback: SMTPConfig{
+				Host: os.Getenv("SMTP_FALLBACK_HOST"),
+				Port: envInt("SMTP_FALLBACK_PORT", 587),
+				User: os.Getenv("SMTP_FALLBACK_USER"),
+				Pass: os.Getenv("SMTP_FALLBACK_PASS"),
+			},
+			SendmailPath: envOr("SENDMAIL_PATH", "/usr/sbin/sendmail"),
+			FileDir:      envOr("MAIL_FILE_DIR", "./mail-out"),
+			DKIMDomain:   os.Getenv("DKIM_DOMAIN"),
+			DKIMSelector: os.Getenv("DKIM_SELECTOR"),
+			DKIMKeyFile:  os.Getenv("DKIM_KEY_FILE"),
+		},
+		Login: LoginConfig{
+			MaxFailures:   envInt("LOGIN_MAX_FAILURES", 10),
+			FailureWindow: time.Duration(envInt("LOGIN_FAILURE_WINDOW_MIN", 15)) * time.Minute,
+			Lockout:       time.Duration(envInt("LOGIN_LOCKOUT_MIN", 15)) * time.Minute,
+			DelayAfter:    envInt("LOGIN_DELAY_AFTER", 3),
+			DelayBase:     time.Duration(envInt("LOGIN_DELAY_BASE_SEC", 1)) * time.Second,
+			DelayMax:      time.Duration(envInt("LOGIN_DELAY_MAX_SEC", 30)) * time.Second,
+		},
+		OIDC: OIDCConfig{
+			RedirectBase: oidcRedirectBase,
+			Providers:    loadOIDCProviders(),
+		},
 		Storage: StorageConfig{
 			Driver:     os.Getenv("STORAGE_DRIVER"),
 			LocalDir:   os.Getenv("STORAGE_LOCAL_DIR"),
@@ -80,8 +197,93 @@ func Load() Config {
 			S3Secret:   os.Getenv("S3_SECRET_KEY"),
 			S3Region:   os.Getenv("S3_REGION"),
 		},
-		OrganizerEmails: splitCSVEmails(os.Getenv("ORGANIZER_EMAILS")),
+		OrganizerEmails:       splitCSVEmails(os.Getenv("ORGANIZER_EMAILS")),
+		UnverifiedMaxAge:      time.Duration(envInt("UNVERIFIED_ACCOUNT_MAX_AGE_DAYS", 0)) * 24 * time.Hour,
+		CapacityCategoryField: strings.TrimSpace(os.Getenv("CAPACITY_CATEGORY_FIELD")),
+		DuplicateThreshold:    envFloat("DUPLICATE_SIMILARITY_THRESHOLD", 0.6),
+		CampaignBatchSize:     envInt("CAMPAIGN_BATCH_SIZE", 60),
+		CampaignInterval:      time.Duration(envInt("CAMPAIGN_INTERVAL_SEC", 60)) * time.Second,
+		ReminderInterval:      time.Duration(envInt("REMINDER_INTERVAL_DAYS", 0)) * 24 * time.Hour,
+		ReminderCutoff:        envDate("REMINDER_CUTOFF"),
+		DigestHour:            envInt("DIGEST_HOUR_UTC", 7),
+		ContentLocales:        splitCSVLocales(envOr("CONTENT_LOCALES", "ru,en")),
+		LocaleFallback:        splitCSVLocales(envOr("CONTENT_LOCALE_FALLBACK", "en,ru")),
+		Bounces: BounceConfig{
+			Source:        strings.ToLower(strings.TrimSpace(os.Getenv("BOUNCE_SOURCE"))),
+			MaildirPath:   os.Getenv("BOUNCE_MAILDIR"),
+			PollInterval:  time.Duration(envInt("BOUNCE_POLL_INTERVAL_MIN", 5)) * time.Minute,
+			WebhookSecret: os.Getenv("BOUNCE_WEBHOOK_SECRET"),
+		},
+	}
+}
+
+// loadOIDCProviders reads OIDC_PROVIDERS=a,b and the per-provider
+// OIDC_<ID>_ISSUER / _CLIENT_ID / _CLIENT_SECRET / _NAME / _SCOPES variables.
+func loadOIDCProviders() []OIDCProviderConfig {
+	out := []OIDCProviderConfig{}
+	for _, raw := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		id := strings.ToLower(strings.TrimSpace(raw))
+		if id == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(id, "-", "_")) + "_"
+		p := OIDCProviderConfig{
+			ID:           id,
+			Name:         os.Getenv(prefix + "NAME"),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if p.Issuer == "" || p.ClientID == "" {
+			continue
+		}
+		out = append(out, p)
 	}
+	return out
+}
+
+func envOr(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+// envInt reads an integer env var, falling back to def when unset or malformed.
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func envFloat(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv(key)), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// envDate reads a YYYY-MM-DD date (UTC midnight); nil when unset or malformed.
+func envDate(key string) *time.Time {
+	v, err := time.Parse("2006-01-02", strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func splitCSVLocales(raw string) []string {
+	out := []string{}
+	for _, p := range strings.Split(raw, ",") {
+		if l := strings.ToLower(strings.TrimSpace(p)); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
 }
 
 func splitCSVEmails(raw string) []string {
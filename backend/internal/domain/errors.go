@@ -8,4 +8,12 @@ var (
 	ErrNotFound         = errors.New("not found")
 	ErrInvalidInput     = errors.New("invalid input")
 	ErrTalkLimitReached = errors.New("talk limit reached")
+	ErrCapacityFull     = errors.New("capacity full")
+	ErrAccountLocked    = errors.New("account locked")
+	ErrTooManyAttempts  = errors.New("too many attempts")
+	ErrEmailNotVerified = errors.New("email not verified")
+	ErrEmailTaken       = errors.New("email already exists")
+	ErrInvalidState     = errors.New("invalid state")
+	// ErrKeyReused means an idempotency key came back with a different request.
+	ErrKeyReused = errors.New("idempotency key reused")
 )
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,16 +28,32 @@ const (
 	StatusWaiting  UserStatus = "WAITING"
 	StatusApproved UserStatus = "APPROVED"
 	StatusRejected UserStatus = "REJECTED"
+	// StatusWaitlisted is an approval that did not fit the capacity; see WaitlistEntry.
+	StatusWaitlisted UserStatus = "WAITLISTED"
+	// StatusCancelled is set by the participant; the reason is kept on the user.
+	StatusCancelled UserStatus = "CANCELLED"
 )
 
 type User struct {
-	ID            uuid.UUID
-	Email         string
-	PasswordHash  string
-	EmailVerified bool
-	Status        UserStatus
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID                 uuid.UUID
+	Email              string
+	PasswordHash       string
+	EmailVerified      bool
+	Status             UserStatus
+	CancelledAt        *time.Time
+	CancellationReason *string
+	// DisabledAt is set when the account was merged into MergedInto; a
+	// disabled account cannot sign in.
+	DisabledAt *time.Time
+	MergedInto *uuid.UUID
+	// PreferredLang is "ru" or "en"; nil until known.
+	PreferredLang *string
+	// EmailUndeliverableAt is set when mail to Email hard-bounced; non-critical
+	// mail is not sent to it until the address changes.
+	EmailUndeliverableAt *time.Time
+	EmailBounceReason    *string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 type UserWithRoles struct {
@@ -43,6 +61,54 @@ type UserWithRoles struct {
 	Roles []RoleAssignment
 }
 
+// AdminUserRow is one row of the admin user listing; Profile is nil until the
+// user has registered.
+type AdminUserRow struct {
+	User    User
+	Roles   []RoleAssignment
+	Profile *Profile
+}
+
+// UserMerge folds the Source account into Target. ProfileFromSource names the
+// profile fields (as in the API, e.g. "phone") whose value is taken from
+// Source; every other field keeps Target's value.
+type UserMerge struct {
+	SourceID          uuid.UUID
+	TargetID          uuid.UUID
+	ActorID           uuid.UUID
+	ProfileFromSource []string
+	CategoryField     string // registration field that picks the capacity category
+}
+
+// UserMergeResult counts the rows moved to the surviving account.
+type UserMergeResult struct {
+	Talks           int `json:"talks"`
+	ConsentFiles    int `json:"consentFiles"`
+	SignedDocuments int `json:"signedDocuments"`
+	Sessions        int `json:"sessions"`
+	Roles           int `json:"roles"`
+	Identities      int `json:"identities"`
+	// ApprovalCarried is set when the source's approved place moved to a
+	// target that was not approved yet.
+	ApprovalCarried bool `json:"approvalCarried"`
+}
+
+// CapacityLimits caps the number of approved participants. Categories are the
+// values of the registration field configured as the participation category.
+type CapacityLimits struct {
+	Total      *int
+	Categories map[string]int
+}
+
+type WaitlistEntry struct {
+	UserID   uuid.UUID
+	Email    string
+	FullName string
+	Category string
+	Position int // 1-based
+	QueuedAt time.Time
+}
+
 type Profile struct {
 	UserID                uuid.UUID
 	Surname               string
@@ -60,6 +126,79 @@ type Profile struct {
 	UpdatedAt             time.Time
 }
 
+// RegistrationFieldType is the input kind of an organizer-defined registration
+// question. Answers are stored per user as a key -> value JSON object.
+type RegistrationFieldType string
+
+const (
+	FieldText     RegistrationFieldType = "text"     // string
+	FieldSelect   RegistrationFieldType = "select"   // option value
+	FieldCheckbox RegistrationFieldType = "checkbox" // bool
+	FieldDate     RegistrationFieldType = "date"     // YYYY-MM-DD
+	FieldFile     RegistrationFieldType = "file"     // URL returned by the upload endpoint
+)
+
+type RegistrationFieldOption struct {
+	Value   string `json:"value"`
+	LabelRu string `json:"labelRu"`
+	LabelEn string `json:"labelEn"`
+}
+
+// RegistrationFieldValidation holds optional constraints; which ones apply
+// depends on the field type.
+type RegistrationFieldValidation struct {
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	MinDate   string `json:"minDate,omitempty"`
+	MaxDate   string `json:"maxDate,omitempty"`
+
+	pattern    *regexp.Regexp
+	patternErr error
+}
+
+// CompilePattern prepares Pattern for MatchPattern. It is called once when a
+// definition is loaded or saved rather than for every answer.
+func (v *RegistrationFieldValidation) CompilePattern() error {
+	v.pattern, v.patternErr = nil, nil
+	if v.Pattern == "" {
+		return nil
+	}
+	v.pattern, v.patternErr = regexp.Compile(v.Pattern)
+	return v.patternErr
+}
+
+// MatchPattern reports whether s matches Pattern; an empty Pattern matches
+// anything. It fails when Pattern did not compile or was never compiled.
+func (v RegistrationFieldValidation) MatchPattern(s string) (bool, error) {
+	switch {
+	case v.Pattern == "":
+		return true, nil
+	case v.patternErr != nil:
+		return false, v.patternErr
+	case v.pattern == nil || v.pattern.String() != v.Pattern:
+		return false, errors.New("pattern not compiled")
+	}
+	return v.pattern.MatchString(s), nil
+}
+
+type RegistrationField struct {
+	ID         uuid.UUID
+	Key        string
+	Type       RegistrationFieldType
+	LabelRu    string
+	LabelEn    string
+	HelpRu     *string
+	HelpEn     *string
+	Options    []RegistrationFieldOption
+	Required   bool
+	Validation RegistrationFieldValidation
+	SortOrder  int32
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
 type ConsentType string
 
 const (
@@ -93,6 +232,38 @@ type Section struct {
 type SectionResponsibleEmail struct {
 	SectionID uuid.UUID
 	Email     string
+	Mode      NotifyMode
+}
+
+// NotifyMode is how a section responsible hears about uploaded talk files.
+type NotifyMode string
+
+const (
+	NotifyImmediate NotifyMode = "IMMEDIATE"
+	NotifyDigest    NotifyMode = "DIGEST"
+)
+
+// DigestRecipient is a responsible address on the daily digest with the
+// sections it covers.
+type DigestRecipient struct {
+	Email        string
+	Lang         string
+	SectionIDs   []uuid.UUID
+	LastDigestAt *time.Time
+}
+
+// DigestTalk is a talk listed in a responsible's digest.
+type DigestTalk struct {
+	ID             uuid.UUID
+	SectionID      uuid.UUID
+	SectionTitleRu string
+	SectionTitleEn string
+	Title          string
+	Speaker        string
+	Kind           TalkKind
+	Status         TalkStatus
+	CreatedAt      time.Time
+	RevisedAt      *time.Time
 }
 
 type TalkKind string
@@ -109,6 +280,8 @@ const (
 	TalkStatusWaiting  TalkStatus = "WAITING"
 	TalkStatusApproved TalkStatus = "APPROVED"
 	TalkStatusRejected TalkStatus = "REJECTED"
+	// TalkStatusWithdrawn is a soft delete by the speaker; the row stays for admin views and exports.
+	TalkStatusWithdrawn TalkStatus = "WITHDRAWN"
 )
 
 type TalkAuthor struct {
@@ -117,17 +290,19 @@ type TalkAuthor struct {
 }
 
 type Talk struct {
-	ID            uuid.UUID
-	SpeakerUserID uuid.UUID
-	SectionID     *uuid.UUID
-	Title         string
-	Affiliation   string
-	Abstract      string
-	Kind          TalkKind
-	Status        TalkStatus
-	AuthorsJSON   []byte // raw jsonb payload
-	CreatedAt     time.Time
-	FileURL       *string
+	ID               uuid.UUID
+	SpeakerUserID    uuid.UUID
+	SectionID        *uuid.UUID
+	Title            string
+	Affiliation      string
+	Abstract         string
+	Kind             TalkKind
+	Status           TalkStatus
+	AuthorsJSON      []byte // raw jsonb payload
+	CreatedAt        time.Time
+	FileURL          *string
+	WithdrawnAt      *time.Time
+	WithdrawalReason *string
 }
 
 type AdminTalkRow struct {
@@ -145,6 +320,67 @@ type AdminTalkRow struct {
 	SpeakerAffiliation string
 	AuthorsJSON        []byte
 	Abstract           string
+	WithdrawnAt        *time.Time
+	WithdrawalReason   *string
+}
+
+// TalkSearchHit is one full-text search result. TitleHighlight and
+// AbstractSnippet are HTML-escaped with matches wrapped in <mark>.
+type TalkSearchHit struct {
+	ID              uuid.UUID
+	Title           string
+	Kind            TalkKind
+	Status          TalkStatus
+	SectionID       *uuid.UUID
+	SectionTitleRu  *string
+	SectionTitleEn  *string
+	SpeakerFullName string
+	AuthorsJSON     []byte
+	Rank            float32
+	TitleHighlight  string
+	AbstractSnippet string
+}
+
+// DuplicateCandidate is an existing talk whose title or abstract resembles
+// the talk being checked. Similarities are pg_trgm scores in [0, 1].
+type DuplicateCandidate struct {
+	TalkID             uuid.UUID
+	Title              string
+	SpeakerUserID      uuid.UUID
+	TitleSimilarity    float32
+	AbstractSimilarity float32
+}
+
+type DuplicateTalkRef struct {
+	ID              uuid.UUID
+	Title           string
+	Status          TalkStatus
+	SpeakerUserID   uuid.UUID
+	SpeakerEmail    string
+	SpeakerFullName string
+}
+
+type DuplicateTalkPair struct {
+	TalkA              DuplicateTalkRef
+	TalkB              DuplicateTalkRef
+	TitleSimilarity    float32
+	AbstractSimilarity float32
+	FlaggedAt          time.Time
+	DismissedAt        *time.Time
+}
+
+// DuplicateUserGroup is a set of accounts with the same full name and birth date.
+type DuplicateUserGroup struct {
+	FullName  string
+	BirthDate time.Time
+	Users     []DuplicateUserRef
+}
+
+type DuplicateUserRef struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type PageContent struct {
@@ -156,14 +392,48 @@ type PageContent struct {
 	UpdatedAt time.Time
 }
 
+type NewsStatus string
+
+const (
+	NewsDraft     NewsStatus = "DRAFT"
+	NewsScheduled NewsStatus = "SCHEDULED"
+	NewsPublished NewsStatus = "PUBLISHED"
+	NewsArchived  NewsStatus = "ARCHIVED"
+)
+
+// News is an announcement. A SCHEDULED item becomes PUBLISHED at PublishAt
+// and a published one is ARCHIVED at ExpiresAt; PublishedAt is set once the
+// item goes public. NotifySubscribers mails it to campaign subscribers on
+// publish; NotifiedAt records that it was sent.
 type News struct {
-	ID          uuid.UUID
-	TitleRu     string
-	BodyRu      string
-	TitleEn     string
-	BodyEn      string
-	Pinned      bool
-	PublishedAt time.Time
+	ID                uuid.UUID  `json:"id"`
+	TitleRu           string     `json:"titleRu"`
+	BodyRu            string     `json:"bodyRu"`
+	TitleEn           string     `json:"titleEn"`
+	BodyEn            string     `json:"bodyEn"`
+	Pinned            bool       `json:"pinned"`
+	Status            NewsStatus `json:"status"`
+	PublishAt         *time.Time `json:"publishAt"`
+	ExpiresAt         *time.Time `json:"expiresAt"`
+	PublishedAt       *time.Time `json:"publishedAt"`
+	NotifySubscribers bool       `json:"notifySubscribers"`
+	NotifiedAt        *time.Time `json:"notifiedAt"`
+	CampaignID        *uuid.UUID `json:"campaignId"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// SiteLastModified is when public content without a page of its own last
+// changed; nil when there is none. Program covers the uploaded program file
+// and the approved talks; Sections and Materials include their translations.
+// PageTranslations (by slug) and NewsTranslations hold the latest translation
+// of each page and news item that has one.
+type SiteLastModified struct {
+	Sections         *time.Time
+	Program          *time.Time
+	Materials        *time.Time
+	PageTranslations map[string]time.Time
+	NewsTranslations map[uuid.UUID]time.Time
 }
 
 type RefreshSession struct {
@@ -175,14 +445,106 @@ type RefreshSession struct {
 	CreatedAt time.Time
 }
 
+// LoginAttempts is the persisted brute-force state of a login email, which
+// need not belong to an account.
+type LoginAttempts struct {
+	Email        string
+	FailedCount  int
+	LastFailedAt *time.Time
+	LockedUntil  *time.Time
+}
+
+// APIToken is a personal access token for scripts and integrations. Only the
+// hash of the secret is stored; Prefix is kept so users can tell tokens apart.
+type APIToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	LastUsedIP *string
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// API token scopes have the form "<resource>:read" or "<resource>:write".
+// A token never grants more than the owner's roles allow.
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+	ScopeTalksRead    = "talks:read"
+	ScopeTalksWrite   = "talks:write"
+	ScopeUsersRead    = "users:read"
+	ScopeUsersWrite   = "users:write"
+	ScopeContentRead  = "content:read"
+	ScopeContentWrite = "content:write"
+	ScopeExportsRead  = "exports:read"
+	ScopeAuditRead    = "audit:read"
+)
+
+var APIScopes = []string{
+	ScopeProfileRead, ScopeProfileWrite,
+	ScopeTalksRead, ScopeTalksWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopeContentRead, ScopeContentWrite,
+	ScopeExportsRead,
+	ScopeAuditRead,
+}
+
 type AuditLog struct {
 	ID          uuid.UUID
 	ActorUserID *uuid.UUID
 	Action      string
 	Entity      string
 	EntityID    *uuid.UUID
+	Details     map[string]any
 	CreatedAt   time.Time
 }
+
+// BulkFilter narrows a bulk moderation request; nil fields do not filter.
+// Section and kind refer to the talk itself, or to any non-withdrawn talk of
+// the user; email and consent state refer to the user or the speaker.
+type BulkFilter struct {
+	Status           *string    `json:"status,omitempty"`
+	SectionID        *uuid.UUID `json:"sectionId,omitempty"`
+	Kind             *TalkKind  `json:"kind,omitempty"`
+	EmailVerified    *bool      `json:"emailVerified,omitempty"`
+	ConsentsUploaded *bool      `json:"consentsUploaded,omitempty"` // both consent files present
+}
+
+func (f BulkFilter) Empty() bool {
+	return f.Status == nil && f.SectionID == nil && f.Kind == nil && f.EmailVerified == nil && f.ConsentsUploaded == nil
+}
+
+// Bulk item outcomes.
+const (
+	BulkUpdated   = "updated"
+	BulkUnchanged = "unchanged"
+	BulkSkipped   = "skipped"
+	BulkNotFound  = "not_found"
+)
+
+type BulkItemResult struct {
+	ID       uuid.UUID `json:"id"`
+	Result   string    `json:"result"`
+	Previous string    `json:"previous,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// QueuedMail is a notification of a bulk moderation call waiting to be sent.
+type QueuedMail struct {
+	ID      uuid.UUID
+	BatchID uuid.UUID
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
 type MaterialType string
 
 const (
@@ -205,3 +567,173 @@ type Material struct {
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
+
+// EmailTemplate is one language of a notification: subject and text are Go
+// text/template sources, HTML is an html/template source. Version is 0 for the
+// built-in file template.
+type EmailTemplate struct {
+	Name      string     `json:"name"`
+	Lang      string     `json:"lang"`
+	Subject   string     `json:"subject"`
+	HTML      string     `json:"html"`
+	Text      string     `json:"text"`
+	Version   int        `json:"version"`
+	CreatedBy *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// EmailTemplateDef documents a notification and the variables its templates
+// may use, as {{.Name}}. Sample values feed previews and validation.
+type EmailTemplateDef struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Variables   []EmailTemplateVariable `json:"variables"`
+}
+
+type EmailTemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Sample      any    `json:"sample"`
+}
+
+type CampaignStatus string
+
+const (
+	CampaignDraft     CampaignStatus = "DRAFT"
+	CampaignSending   CampaignStatus = "SENDING"
+	CampaignSent      CampaignStatus = "SENT"
+	CampaignCancelled CampaignStatus = "CANCELLED"
+)
+
+// CampaignSegment selects the recipients of a campaign; nil fields do not
+// filter. Talk conditions (Speakers, SectionID, Kind, TalkStatus) match users
+// with at least one non-withdrawn talk meeting all of them. Disabled and
+// unsubscribed users are never included.
+type CampaignSegment struct {
+	Status           *UserStatus `json:"status,omitempty"`
+	Role             *Role       `json:"role,omitempty"`
+	Speakers         bool        `json:"speakers,omitempty"`
+	SectionID        *uuid.UUID  `json:"sectionId,omitempty"`
+	Kind             *TalkKind   `json:"kind,omitempty"`
+	TalkStatus       *TalkStatus `json:"talkStatus,omitempty"`
+	ConsentsUploaded *bool       `json:"consentsUploaded,omitempty"`
+	EmailVerified    *bool       `json:"emailVerified,omitempty"`
+}
+
+// Campaign is an announcement mailed to a segment. Subjects and bodies are
+// Go text/template sources; the body is plain text, paragraphs separated by
+// blank lines.
+type Campaign struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	Segment    CampaignSegment `json:"segment"`
+	SubjectRu  string          `json:"subjectRu"`
+	BodyRu     string          `json:"bodyRu"`
+	SubjectEn  string          `json:"subjectEn"`
+	BodyEn     string          `json:"bodyEn"`
+	Status     CampaignStatus  `json:"status"`
+	CreatedBy  *uuid.UUID      `json:"createdBy"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	StartedAt  *time.Time      `json:"startedAt"`
+	FinishedAt *time.Time      `json:"finishedAt"`
+	Pending    int             `json:"pending"`
+	Sent       int             `json:"sent"`
+	Failed     int             `json:"failed"`
+	Skipped    int             `json:"skipped"`
+}
+
+const (
+	RecipientPending = "PENDING"
+	RecipientSent    = "SENT"
+	RecipientFailed  = "FAILED"
+	// RecipientSkipped: unsubscribed or disabled after the campaign started.
+	RecipientSkipped = "SKIPPED"
+)
+
+// CampaignRecipient is one addressee with the data used to personalize the
+// message. Status is empty for a segment preview.
+type CampaignRecipient struct {
+	CampaignID       uuid.UUID  `json:"-"`
+	UserID           uuid.UUID  `json:"userId"`
+	Email            string     `json:"email"`
+	Lang             string     `json:"lang"`
+	FullName         string     `json:"fullName"`
+	FirstName        string     `json:"-"`
+	UserStatus       UserStatus `json:"-"`
+	Status           string     `json:"status,omitempty"`
+	Error            *string    `json:"error,omitempty"`
+	SentAt           *time.Time `json:"sentAt,omitempty"`
+	UnsubscribeToken string     `json:"-"`
+}
+
+type ReminderKind string
+
+const (
+	ReminderConsents         ReminderKind = "CONSENTS"
+	ReminderLicenseAgreement ReminderKind = "LICENSE_AGREEMENT"
+	ReminderTalkFile         ReminderKind = "TALK_FILE"
+)
+
+// ReminderCandidate is a user with unfinished checklist items that are due
+// for a reminder. LicenseTalks and FileTalks hold titles of approved talks.
+type ReminderCandidate struct {
+	UserID          uuid.UUID
+	Email           string
+	Lang            string
+	MissingConsents bool
+	LicenseTalks    []string
+	FileTalks       []string
+}
+
+// ReminderSent is one reminded item, as listed in the organizers' report.
+type ReminderSent struct {
+	ID       uuid.UUID    `json:"id"`
+	UserID   uuid.UUID    `json:"userId"`
+	Email    string       `json:"email"`
+	FullName string       `json:"fullName"`
+	Kind     ReminderKind `json:"kind"`
+	Talks    []string     `json:"talks"`
+	SentAt   time.Time    `json:"sentAt"`
+}
+
+type BounceKind string
+
+const (
+	BounceHard      BounceKind = "HARD"
+	BounceSoft      BounceKind = "SOFT"
+	BounceComplaint BounceKind = "COMPLAINT"
+)
+
+// MailBounce is a delivery failure or spam complaint reported for an address.
+// Source names where it came from: "webhook" or a mailbox poller.
+type MailBounce struct {
+	Email      string
+	Kind       BounceKind
+	Reason     string
+	Source     string
+	ReceivedAt time.Time
+}
+
+// ContentEntity is a kind of translatable site content.
+type ContentEntity string
+
+const (
+	ContentPage     ContentEntity = "page"
+	ContentNews     ContentEntity = "news"
+	ContentSection  ContentEntity = "section"
+	ContentMaterial ContentEntity = "material"
+)
+
+// Translation is one locale of a page, news item, section or material. Key is
+// the page slug or the entity id. Body is the page/news body or the material
+// description; sections only have a title. ru and en live in the entities'
+// own columns.
+type Translation struct {
+	Entity    ContentEntity `json:"entity"`
+	Key       string        `json:"key"`
+	Locale    string        `json:"locale"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	UpdatedAt *time.Time    `json:"updatedAt,omitempty"`
+}
@@ -1,6 +1,8 @@
 ﻿package auth
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,28 +12,100 @@ import (
 type Claims struct {
 	UserID uuid.UUID `json:"uid"`
 	Roles  []string  `json:"roles"`
+	// Act is set on impersonation tokens and names the admin acting as UserID (RFC 8693).
+	Act *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func SignAccessJWT(secret []byte, userID uuid.UUID, roles []string, ttl time.Duration) (string, error) {
+type Actor struct {
+	UserID uuid.UUID `json:"sub"`
+}
+
+// SigningKey is one entry of a KeySet. Keys without a private part only verify.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   any
+	verify any
+}
+
+func (k *SigningKey) CanSign() bool { return k.sign != nil }
+
+// KeySet signs access tokens with the active key and verifies them against any
+// key it holds, which allows rotating keys without invalidating live tokens.
+type KeySet struct {
+	Issuer   string
+	Audience string
+	active   *SigningKey
+	keys     map[string]*SigningKey
+}
+
+// NewHMACKeySet is the single shared-secret (HS256) setup used in development.
+func NewHMACKeySet(secret []byte, issuer, audience string) (*KeySet, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("jwt: empty secret")
+	}
+	k := &SigningKey{ID: "hs256", Method: jwt.SigningMethodHS256, sign: secret, verify: secret}
+	return &KeySet{Issuer: issuer, Audience: audience, active: k, keys: map[string]*SigningKey{k.ID: k}}, nil
+}
+
+func (ks *KeySet) ActiveKeyID() string { return ks.active.ID }
+
+func (ks *KeySet) SignAccess(userID uuid.UUID, roles []string, ttl time.Duration) (string, error) {
+	return ks.sign(ks.claims(userID, roles, ttl))
+}
+
+// SignImpersonation issues an access token for userID that also records the acting admin.
+func (ks *KeySet) SignImpersonation(userID uuid.UUID, roles []string, actorID uuid.UUID, ttl time.Duration) (string, error) {
+	claims := ks.claims(userID, roles, ttl)
+	claims.Act = &Actor{UserID: actorID}
+	return ks.sign(claims)
+}
+
+func (ks *KeySet) claims(userID uuid.UUID, roles []string, ttl time.Duration) Claims {
 	now := time.Now()
-	claims := Claims{
+	return Claims{
 		UserID: userID,
 		Roles:  roles,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    ks.Issuer,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{ks.Audience},
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
 }
 
-func ParseAccessJWT(secret []byte, token string) (*Claims, error) {
-	t, err := jwt.ParseWithClaims(token, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return secret, nil
-	})
+func (ks *KeySet) sign(claims Claims) (string, error) {
+	t := jwt.NewWithClaims(ks.active.Method, claims)
+	t.Header["kid"] = ks.active.ID
+	return t.SignedString(ks.active.sign)
+}
+
+// ParseAccess verifies signature, algorithm (bound to the key's kid), issuer,
+// audience and expiry.
+func (ks *KeySet) ParseAccess(token string) (*Claims, error) {
+	var key *SigningKey
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key = ks.keys[kid]
+		if key == nil {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+		}
+		return key.verify, nil
+	},
+		jwt.WithValidMethods([]string{"RS256", "EdDSA", "HS256"}),
+		jwt.WithIssuer(ks.Issuer),
+		jwt.WithAudience(ks.Audience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
 		return nil, err
 	}
-	return t.Claims.(*Claims), nil
+	return claims, nil
 }
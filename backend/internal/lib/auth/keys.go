@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LoadKeySet reads every <kid>.pem file in dir. Private keys (RSA or Ed25519,
+// PKCS#1 or PKCS#8) can sign and verify; public keys (PKIX) only verify, which
+// is how a retired key is kept around until tokens signed with it expire.
+// activeKID selects the signing key; when empty the only private key is used.
+func LoadKeySet(dir, activeKID, issuer, audience string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	ks := &KeySet{Issuer: issuer, Audience: audience, keys: map[string]*SigningKey{}}
+	var signers []*SigningKey
+	for _, f := range files {
+		kid := strings.TrimSuffix(filepath.Base(f), ".pem")
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		k, err := parsePEMKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", f, err)
+		}
+		ks.keys[kid] = k
+		if k.CanSign() {
+			signers = append(signers, k)
+		}
+	}
+	switch {
+	case activeKID != "":
+		k := ks.keys[activeKID]
+		if k == nil || !k.CanSign() {
+			return nil, fmt.Errorf("jwt: active key %q not found or has no private key", activeKID)
+		}
+		ks.active = k
+	case len(signers) == 1:
+		ks.active = signers[0]
+	default:
+		return nil, fmt.Errorf("jwt: %d private keys in %s, set the active key id", len(signers), dir)
+	}
+	return ks, nil
+}
+
+func parsePEMKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, sign: k, verify: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, verify: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, sign: k, verify: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, verify: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// JWK is the public form of a verification key (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the asymmetric verification keys; shared secrets are never exposed.
+func (ks *KeySet) JWKS() JWKSet {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := JWKSet{Keys: []JWK{}}
+	for _, id := range ids {
+		k := ks.keys[id]
+		switch pub := k.verify.(type) {
+		case *rsa.PublicKey:
+			out.Keys = append(out.Keys, JWK{
+				Kty: "RSA", Kid: id, Use: "sig", Alg: "RS256",
+				N: b64(pub.N.Bytes()),
+				E: b64(bigEndian(pub.E)),
+			})
+		case ed25519.PublicKey:
+			out.Keys = append(out.Keys, JWK{Kty: "OKP", Kid: id, Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: b64(pub)})
+		}
+	}
+	return out
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func bigEndian(e int) []byte {
+	var out []byte
+	for ; e > 0; e >>= 8 {
+		out = append([]byte{byte(e)}, out...)
+	}
+	return out
+}
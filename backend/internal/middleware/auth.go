@@ -1,31 +1,83 @@
 ﻿package middleware
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
 	"confsite/backend/internal/lib/auth"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
-	CtxUserIDKey = "userID"
-	CtxRolesKey  = "roles"
+	CtxUserIDKey     = "userID"
+	CtxRolesKey      = "roles"
+	CtxAuthMethodKey = "authMethod"
+	CtxScopesKey     = "scopes"
+	CtxActorIDKey    = "actorID" // admin behind an impersonation session
 )
 
+const (
+	AuthMethodSession = "session"
+	AuthMethodToken   = "token"
+)
+
+// BearerAuthenticator resolves personal API tokens sent as "Authorization: Bearer".
+type BearerAuthenticator interface {
+	AuthenticateBearer(ctx context.Context, token, ip string) (userID uuid.UUID, roles []string, scopes []string, err error)
+}
+
+// ImpersonationRecorder audits requests made by an admin acting as another user.
+type ImpersonationRecorder interface {
+	RecordImpersonatedRequest(ctx context.Context, actorID, userID uuid.UUID, method, path string, status int)
+}
+
 type AuthConfig struct {
-	JWTSecret []byte
+	Keys   *auth.KeySet
+	Bearer BearerAuthenticator // optional; nil disables API tokens
+	// Impersonation audits impersonated requests; nil rejects impersonation tokens.
+	Impersonation ImpersonationRecorder
+	// Routes tells which routes take API tokens and which ends impersonation.
+	Routes *Routes
 }
 
+// Auth accepts API tokens only on routes registered with Routes.Scoped, and
+// only with the matching scope; every other route answers 403 to a bearer
+// credential.
 func Auth(cfg AuthConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if bearer, ok := bearerToken(c); ok && cfg.Bearer != nil {
+			userID, roles, scopes, err := cfg.Bearer.AuthenticateBearer(c, bearer, c.ClientIP())
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+				return
+			}
+			resource := cfg.Routes.lookup(c).scope
+			if resource == "" {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "session_required"})
+				return
+			}
+			if want := requiredScope(c, resource); !hasScope(scopes, want) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient_scope", "scope": want})
+				return
+			}
+			c.Set(CtxUserIDKey, userID)
+			c.Set(CtxRolesKey, roles)
+			c.Set(CtxAuthMethodKey, AuthMethodToken)
+			c.Set(CtxScopesKey, scopes)
+			c.Next()
+			return
+		}
+
 		token, err := c.Cookie("access_token")
 		if err != nil || token == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
 
-		claims, err := auth.ParseAccessJWT(cfg.JWTSecret, token)
+		claims, err := cfg.Keys.ParseAccess(token)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
@@ -33,10 +85,49 @@ func Auth(cfg AuthConfig) gin.HandlerFunc {
 
 		c.Set(CtxUserIDKey, claims.UserID)
 		c.Set(CtxRolesKey, claims.Roles)
+		c.Set(CtxAuthMethodKey, AuthMethodSession)
+		if claims.Act == nil {
+			c.Next()
+			return
+		}
+
+		if cfg.Impersonation == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Set(CtxActorIDKey, claims.Act.UserID)
+		// Impersonation is read-only; the only write allowed is leaving it.
+		readOnly := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+		if !readOnly && !cfg.Routes.lookup(c).impersonationExit {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "impersonation_forbidden"})
+		} else {
+			c.Next()
+		}
+		cfg.Impersonation.RecordImpersonatedRequest(context.WithoutCancel(c.Request.Context()),
+			claims.Act.UserID, claims.UserID, c.Request.Method, c.Request.URL.Path, c.Writer.Status())
+	}
+}
+
+// DenyImpersonation blocks actions an admin must not take on a user's behalf.
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(CtxActorIDKey); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "impersonation_forbidden"})
+			return
+		}
 		c.Next()
 	}
 }
 
+func bearerToken(c *gin.Context) (string, bool) {
+	h := c.GetHeader("Authorization")
+	if len(h) < 7 || !strings.EqualFold(h[:7], "Bearer ") {
+		return "", false
+	}
+	t := strings.TrimSpace(h[7:])
+	return t, t != ""
+}
+
 func RequireRole(role string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		roles, _ := c.Get(CtxRolesKey)
@@ -50,3 +141,31 @@ func RequireRole(role string) gin.HandlerFunc {
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 	}
 }
+
+// requiredScope is "<resource>:read" for GET/HEAD and "<resource>:write" otherwise.
+func requiredScope(c *gin.Context, resource string) string {
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		return resource + ":read"
+	}
+	return resource + ":write"
+}
+
+func hasScope(scopes []string, want string) bool {
+	for _, s := range scopes {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireSession rejects API token requests, e.g. for managing the tokens themselves.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(CtxAuthMethodKey) == AuthMethodToken {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "session_required"})
+			return
+		}
+		c.Next()
+	}
+}
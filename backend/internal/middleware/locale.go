@@ -1,20 +1,85 @@
 ﻿package middleware
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-const CtxLangKey = "lang"
+const (
+	CtxLangKey = "lang"
+	// CtxContentLocaleKey holds the requested content locale, which may be any
+	// enabled content locale rather than only a UI language.
+	CtxContentLocaleKey = "content_locale"
+)
+
+// SupportedLangs are the languages of the UI and emails; the first is the default.
+var SupportedLangs = []string{"ru", "en"}
 
-func Locale() gin.HandlerFunc {
+// Locale picks the request language: an explicit ?lang=, else the best
+// Accept-Language match, else Russian. The content locale is negotiated the
+// same way against contentLocales.
+func Locale(contentLocales []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		lang := strings.ToLower(c.Query("lang"))
-		if lang != "en" && lang != "ru" {
-			lang = "ru"
+		query := strings.ToLower(strings.TrimSpace(c.Query("lang")))
+		header := c.GetHeader("Accept-Language")
+		lang := query
+		if !supportedLang(SupportedLangs, lang) {
+			lang = NegotiateLang(header, SupportedLangs)
+		}
+		content := query
+		if !supportedLang(contentLocales, content) {
+			if base, _, ok := strings.Cut(content, "-"); ok && supportedLang(contentLocales, base) {
+				content = base
+			} else {
+				content = NegotiateLang(header, contentLocales)
+			}
 		}
 		c.Set(CtxLangKey, lang)
+		c.Set(CtxContentLocaleKey, content)
 		c.Next()
 	}
 }
+
+// NegotiateLang returns the supported language with the highest q-value in
+// an Accept-Language header; regional variants match their base language
+// unless the variant itself is supported. Without a match it returns the
+// first supported language.
+func NegotiateLang(header string, supported []string) string {
+	best, bestQ := "", 0.0
+	if len(supported) > 0 {
+		best = supported[0]
+	}
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !supportedLang(supported, tag) {
+			tag, _, _ = strings.Cut(tag, "-")
+			if !supportedLang(supported, tag) {
+				continue
+			}
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+func supportedLang(supported []string, lang string) bool {
+	for _, l := range supported {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
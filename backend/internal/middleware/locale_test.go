@@ -0,0 +1,33 @@
+package middleware
+
+import "testing"
+
+func TestNegotiateLang(t *testing.T) {
+	content := []string{"ru", "en", "zh", "pt-br"}
+	tests := []struct {
+		header    string
+		supported []string
+		want      string
+	}{
+		{"", SupportedLangs, "ru"},
+		{"en", SupportedLangs, "en"},
+		{"EN-us", SupportedLangs, "en"},
+		{"de, en;q=0.5", SupportedLangs, "en"},
+		{"ru;q=0.4, en;q=0.8", SupportedLangs, "en"},
+		{"en;q=0.8, ru", SupportedLangs, "ru"},
+		{"en;q=0.5, ru;q=0.5", SupportedLangs, "en"}, // first of equal weight wins
+		{"fr, de", SupportedLangs, "ru"},
+		{"en;q=0", SupportedLangs, "ru"},
+		{"en;q=abc, ru;q=0.1", SupportedLangs, "ru"}, // malformed weight is skipped
+		{" en ; q=0.9 ", SupportedLangs, "en"},
+		{"zh-CN,zh;q=0.9", content, "zh"},
+		{"pt-BR, pt;q=0.9", content, "pt-br"}, // a supported variant beats its base
+		{"pt-PT", content, "ru"},
+		{"en", nil, ""},
+	}
+	for _, tc := range tests {
+		if got := NegotiateLang(tc.header, tc.supported); got != tc.want {
+			t.Errorf("NegotiateLang(%q, %v) = %q, want %q", tc.header, tc.supported, got, tc.want)
+		}
+	}
+}
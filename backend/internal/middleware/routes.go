@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeMeta is what Auth needs to know about a route before running it.
+type routeMeta struct {
+	scope             string // API tokens need "<scope>:read" or "<scope>:write"; "" rejects tokens
+	impersonationExit bool   // the one write allowed while impersonating
+}
+
+// Routes records per-route metadata for Auth, keyed by method and full path.
+// Routes are registered while the router is built and only read afterwards.
+type Routes struct {
+	meta map[string]routeMeta
+}
+
+func NewRoutes() *Routes {
+	return &Routes{meta: map[string]routeMeta{}}
+}
+
+// Scoped returns g for registering routes that API tokens may call with
+// "<resource>:read" (GET/HEAD) or "<resource>:write" (other methods).
+// Routes registered on g directly answer 403 to a token.
+func (rt *Routes) Scoped(g *gin.RouterGroup, resource string) RouteGroup {
+	return RouteGroup{routes: rt, group: g, meta: routeMeta{scope: resource}}
+}
+
+// ImpersonationExit returns g for registering the route that ends an
+// impersonation session, the one non-GET request Auth lets through while
+// impersonating.
+func (rt *Routes) ImpersonationExit(g *gin.RouterGroup) RouteGroup {
+	return RouteGroup{routes: rt, group: g, meta: routeMeta{impersonationExit: true}}
+}
+
+func (rt *Routes) lookup(c *gin.Context) routeMeta {
+	if rt == nil {
+		return routeMeta{}
+	}
+	return rt.meta[c.Request.Method+" "+c.FullPath()]
+}
+
+// RouteGroup registers routes on a gin group together with their metadata.
+type RouteGroup struct {
+	routes *Routes
+	group  *gin.RouterGroup
+	meta   routeMeta
+}
+
+func (g RouteGroup) Handle(method, relativePath string, handlers ...gin.HandlerFunc) {
+	g.routes.meta[method+" "+joinPaths(g.group.BasePath(), relativePath)] = g.meta
+	g.group.Handle(method, relativePath, handlers...)
+}
+
+func (g RouteGroup) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (g RouteGroup) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (g RouteGroup) PUT(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+func (g RouteGroup) PATCH(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+func (g RouteGroup) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+// joinPaths builds the full path the way gin does, so it matches c.FullPath().
+func joinPaths(base, relative string) string {
+	if relative == "" {
+		return base
+	}
+	full := path.Join(base, relative)
+	if relative[len(relative)-1] == '/' && full[len(full)-1] != '/' {
+		return full + "/"
+	}
+	return full
+}
@@ -9,17 +9,24 @@ type ParticipantExportRow struct {
 	Affiliation string
 	City        string
 	Email       string
+	// Status is APPROVED or CANCELLED; cancelled registrations stay in exports.
+	Status             string
+	CancellationReason string
+	// Answers holds the extra registration fields keyed by field key.
+	Answers map[string]any
 }
 
 type TalkExportRow struct {
-	Section     string
-	Title       string
-	Kind        string
-	AuthorsJSON []byte
-	Speaker     string
-	SpeakerCity string
-	SpeakerAff  string
-	Abstract    string
+	Section          string
+	Title            string
+	Kind             string
+	AuthorsJSON      []byte
+	Speaker          string
+	SpeakerCity      string
+	SpeakerAff       string
+	Abstract         string
+	Status           string
+	WithdrawalReason string
 }
 
 type ExportRepo interface {
@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdentityClaims are the verified claims of an external identity provider's ID token.
+type IdentityClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// IdentityProvider is an OpenID Connect provider using the authorization code flow with PKCE.
+type IdentityProvider interface {
+	ID() string
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge, redirectURI string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce, redirectURI string) (*IdentityClaims, error)
+}
+
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	RedirectTo   string
+	ExpiresAt    time.Time
+}
+
+type OIDCStateRepo interface {
+	Create(ctx context.Context, st OIDCLoginState) error
+	// Consume deletes the state and returns it; a state can only be used once.
+	Consume(ctx context.Context, stateHash string) (*OIDCLoginState, error)
+}
+
+type UserIdentity struct {
+	UserID   uuid.UUID
+	Provider string
+	Subject  string
+	Email    string
+}
+
+type UserIdentityRepo interface {
+	ByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	Link(ctx context.Context, id UserIdentity) error
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// JobLocker provides cluster-wide mutual exclusion for background jobs.
+type JobLocker interface {
+	TryLock(ctx context.Context, name string) (release func(), ok bool, err error)
+}
@@ -0,0 +1,81 @@
+package ports
+
+import (
+	"time"
+
+	"confsite/backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ListQuery holds the paging, sorting and search parameters shared by admin
+// listings. Sort is one of the keys the listing supports, prefixed with "-"
+// for descending order ("" for the listing's default). Repos report unknown
+// sort keys as domain.ErrInvalidInput.
+type ListQuery struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Search string
+}
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// Normalize applies the default and maximum page size.
+func (q ListQuery) Normalize() ListQuery {
+	if q.Limit <= 0 {
+		q.Limit = DefaultListLimit
+	}
+	if q.Limit > MaxListLimit {
+		q.Limit = MaxListLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return q
+}
+
+// Nil filter fields do not filter. From/To bound the creation time (To is exclusive).
+type UserListFilter struct {
+	ListQuery
+	Status           *domain.UserStatus
+	Role             *domain.Role
+	SectionID        *uuid.UUID // has a non-withdrawn talk in the section
+	Kind             *domain.TalkKind
+	EmailVerified    *bool
+	ConsentsUploaded *bool
+	// EmailUndeliverable filters on the hard-bounce flag.
+	EmailUndeliverable *bool
+	From, To           *time.Time
+}
+
+type TalkListFilter struct {
+	ListQuery
+	Status           *domain.TalkStatus
+	SectionID        *uuid.UUID
+	Kind             *domain.TalkKind
+	ConsentsUploaded *bool // of the speaker
+	From, To         *time.Time
+}
+
+type AuditListFilter struct {
+	ListQuery
+	Action      *string
+	Entity      *string
+	ActorUserID *uuid.UUID
+	EntityID    *uuid.UUID
+	From, To    *time.Time
+}
+
+// TalkSearchFilter is a full-text query over talks. Search holds the query in
+// web search syntax; Lang ("ru" or "en") picks the snippet configuration.
+type TalkSearchFilter struct {
+	ListQuery
+	Lang      string
+	Status    *domain.TalkStatus // nil = any status
+	SectionID *uuid.UUID
+	Kind      *domain.TalkKind
+}
@@ -1,7 +1,26 @@
 ﻿package ports
 
-import "context"
+import (
+	"context"
+
+	"confsite/backend/internal/domain"
+)
 
 type Mailer interface {
 	Send(ctx context.Context, to, subject, html, text string) error
 }
+
+// MailTransport hands a complete, already signed RFC 5322 message to the next
+// hop: an SMTP relay, the local sendmail or a directory of .eml files.
+type MailTransport interface {
+	Name() string
+	Deliver(ctx context.Context, from string, to []string, msg []byte) error
+}
+
+// BounceSource is polled for bounce and complaint reports, e.g. a mailbox
+// that receives the DSNs for our envelope sender. Fetch returns each report
+// once.
+type BounceSource interface {
+	Name() string
+	Fetch(ctx context.Context) ([]domain.MailBounce, error)
+}
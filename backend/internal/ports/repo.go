@@ -16,16 +16,93 @@ type UserRepo interface {
 	SetEmailVerified(ctx context.Context, id uuid.UUID, verified bool) error
 	SetStatus(ctx context.Context, id uuid.UUID, status domain.UserStatus) error
 	SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	SetPreferredLang(ctx context.Context, id uuid.UUID, lang string) error
+	// PreferredLangs maps lower-cased addresses of accounts with a stored
+	// language to that language; other addresses are absent.
+	PreferredLangs(ctx context.Context, emails []string) (map[string]string, error)
 
 	AssignRole(ctx context.Context, userID uuid.UUID, role domain.Role, sectionID *uuid.UUID) error
 	RemoveRole(ctx context.Context, userID uuid.UUID, role domain.Role, sectionID *uuid.UUID) error
-	ListUsers(ctx context.Context) ([]domain.UserWithRoles, error)
+	// ListAdmin returns one page of users with roles and profiles plus the
+	// total number of matching users.
+	ListAdmin(ctx context.Context, f UserListFilter) ([]domain.AdminUserRow, int, error)
+	// DeleteUnverifiedBefore removes never-verified accounts created before the
+	// cutoff that have no talks and no admin role.
+	DeleteUnverifiedBefore(ctx context.Context, before time.Time) (int64, error)
+	// Merge moves talks, consents, signed documents, sessions, roles and
+	// identities of m.SourceID to m.TargetID and disables the source account.
+	// It returns domain.ErrInvalidState when either account is already disabled.
+	Merge(ctx context.Context, m domain.UserMerge) (domain.UserMergeResult, error)
+}
+
+// WaitlistRepo keeps approvals within capacity. Methods that change statuses
+// are serialized so concurrent approvals cannot overbook. categoryField is the
+// registration field key holding the participation category ("" for none).
+type WaitlistRepo interface {
+	Limits(ctx context.Context) (domain.CapacityLimits, error)
+	SetLimits(ctx context.Context, l domain.CapacityLimits) error
+	// Admit approves the user when there is room, otherwise appends them to the
+	// waitlist. It returns the resulting status and the waitlist position.
+	Admit(ctx context.Context, userID uuid.UUID, categoryField string) (domain.UserStatus, int, error)
+	// Enqueue waitlists the user regardless of capacity.
+	Enqueue(ctx context.Context, userID uuid.UUID) (int, error)
+	// PromoteNext approves waitlisted users in order while there is room.
+	PromoteNext(ctx context.Context, categoryField string) ([]uuid.UUID, error)
+	// Promote approves one waitlisted user regardless of capacity. It returns
+	// domain.ErrInvalidState when the user is not waitlisted.
+	Promote(ctx context.Context, userID uuid.UUID) error
+	List(ctx context.Context, categoryField string) ([]domain.WaitlistEntry, error)
+	Move(ctx context.Context, userID uuid.UUID, position int) error
+	// Leave sets a status outside the capacity count (WAITING or REJECTED)
+	// and drops any waitlist entry in the same transaction.
+	Leave(ctx context.Context, userID uuid.UUID, status domain.UserStatus) error
+	// Cancel cancels the registration and drops any waitlist entry in the same
+	// transaction.
+	Cancel(ctx context.Context, userID uuid.UUID, reason string, at time.Time) error
+}
+
+// ModerationRepo applies one status to many users or talks in a single
+// transaction. ids may be empty, in which case every row matching the filter
+// is affected. A non-empty key makes the call idempotent: a repeated key
+// returns the stored results (replayed=true) without touching any row.
+type ModerationRepo interface {
+	// SetUserStatuses honours capacity like WaitlistRepo.Admit: approvals that
+	// do not fit end up WAITLISTED.
+	SetUserStatuses(ctx context.Context, key string, actorID uuid.UUID, ids []uuid.UUID, f domain.BulkFilter, status domain.UserStatus, categoryField string) (results []domain.BulkItemResult, replayed bool, err error)
+	SetTalkStatuses(ctx context.Context, key string, actorID uuid.UUID, ids []uuid.UUID, f domain.BulkFilter, status domain.TalkStatus) (results []domain.BulkItemResult, replayed bool, err error)
+	// EnqueueMail stores the notifications of a committed bulk call.
+	EnqueueMail(ctx context.Context, mails []domain.QueuedMail) error
+	// PendingMail returns up to limit queued notifications, oldest first.
+	PendingMail(ctx context.Context, limit int) ([]domain.QueuedMail, error)
+	// MarkMail records the outcome of one send; a nil errMsg means sent.
+	MarkMail(ctx context.Context, id uuid.UUID, errMsg *string) error
 }
 
 type SessionRepo interface {
 	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
 	ByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshSession, error)
 	Revoke(ctx context.Context, sessionID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// LoginAttemptRepo keeps throttling state per lower-cased login email,
+// whether or not an account uses it.
+type LoginAttemptRepo interface {
+	// Get returns a zero state (no failures, not locked) when the email has no record yet.
+	Get(ctx context.Context, email string) (*domain.LoginAttempts, error)
+	// RecordFailure increments the counter, restarting it when the previous failure is older than windowStart.
+	RecordFailure(ctx context.Context, email string, at, windowStart time.Time) (int, error)
+	Lock(ctx context.Context, email string, until time.Time) error
+	Reset(ctx context.Context, email string) error
+	// DeleteStale removes records that are neither locked at now nor have a
+	// failure after windowStart.
+	DeleteStale(ctx context.Context, now, windowStart time.Time) (int64, error)
+}
+
+type UserDeviceRepo interface {
+	// Touch records a login from the device and reports whether it was seen for the first time.
+	Touch(ctx context.Context, userID uuid.UUID, fingerprint, userAgent, ip string, at time.Time) (bool, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 type ProfileRepo interface {
@@ -34,6 +111,21 @@ type ProfileRepo interface {
 	ListApprovedPublic(ctx context.Context) ([]domain.PublicParticipant, error)
 }
 
+type RegistrationFieldRepo interface {
+	List(ctx context.Context, activeOnly bool) ([]domain.RegistrationField, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.RegistrationField, error)
+	// Create and Update return domain.ErrInvalidInput when the key is already used.
+	Create(ctx context.Context, f domain.RegistrationField) (uuid.UUID, error)
+	Update(ctx context.Context, f domain.RegistrationField) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type RegistrationAnswerRepo interface {
+	// Get returns an empty map when the user has not answered yet.
+	Get(ctx context.Context, userID uuid.UUID) (map[string]any, error)
+	Upsert(ctx context.Context, userID uuid.UUID, answers map[string]any) error
+}
+
 type ConsentFileRepo interface {
 	Create(ctx context.Context, cf domain.ConsentFile) error
 	Upsert(ctx context.Context, userID uuid.UUID, consentType string, fileURL string, fileSize *int64, mimeType *string) error
@@ -47,6 +139,15 @@ type SectionRepo interface {
 	Create(ctx context.Context, titleRu, titleEn string, sortOrder int32) error
 	ListResponsibleEmails(ctx context.Context) ([]domain.SectionResponsibleEmail, error)
 	ReplaceResponsibleEmails(ctx context.Context, sectionID uuid.UUID, emails []string) error
+	// SetResponsibleMode returns domain.ErrNotFound when the address is not a
+	// responsible of any section.
+	SetResponsibleMode(ctx context.Context, email string, mode domain.NotifyMode) error
+	// DigestRecipients lists the responsible addresses in digest mode.
+	DigestRecipients(ctx context.Context) ([]domain.DigestRecipient, error)
+	// DigestTalks returns the non-withdrawn talks of the sections that were
+	// submitted or revised after since, or are waiting for a decision.
+	DigestTalks(ctx context.Context, sectionIDs []uuid.UUID, since time.Time) ([]domain.DigestTalk, error)
+	MarkDigestSent(ctx context.Context, email string, at time.Time) error
 }
 
 type TalkRepo interface {
@@ -57,9 +158,28 @@ type TalkRepo interface {
 	ListBySpeaker(ctx context.Context, speakerID uuid.UUID) ([]domain.Talk, error)
 	CountBySpeaker(ctx context.Context, speakerID uuid.UUID) (int64, error)
 	ListAdmin(ctx context.Context, sectionID *uuid.UUID, onlyPlenary bool) ([]domain.AdminTalkRow, error)
+	// ListAdminPage returns one page of talks plus the total number of matching talks.
+	ListAdminPage(ctx context.Context, f TalkListFilter) ([]domain.AdminTalkRow, int, error)
+	// Search ranks talks matching f.Search; results carry HTML snippets.
+	Search(ctx context.Context, f TalkSearchFilter) ([]domain.TalkSearchHit, int, error)
 	UpdateSchedule(ctx context.Context, talkID uuid.UUID, sectionID *uuid.UUID, scheduleTime *time.Time) error
 	UpdateFile(ctx context.Context, talkID uuid.UUID, fileURL string) error
 	SetStatus(ctx context.Context, talkID uuid.UUID, status domain.TalkStatus) error
+	// Withdraw marks the talk WITHDRAWN and frees its schedule slot; the row is kept.
+	Withdraw(ctx context.Context, talkID uuid.UUID, reason string, at time.Time) error
+}
+
+type DuplicateRepo interface {
+	// SimilarTalks compares the talk with every other non-withdrawn talk and
+	// returns those scoring at least threshold on title or abstract.
+	SimilarTalks(ctx context.Context, talkID uuid.UUID, threshold float64) ([]domain.DuplicateCandidate, error)
+	// FlagTalks records the pairs for the admin report; dismissed pairs stay dismissed.
+	FlagTalks(ctx context.Context, talkID uuid.UUID, candidates []domain.DuplicateCandidate) error
+	ListTalkPairs(ctx context.Context, includeDismissed bool) ([]domain.DuplicateTalkPair, error)
+	DismissTalkPair(ctx context.Context, a, b uuid.UUID, by uuid.UUID) error
+	// DuplicateUsers groups active accounts whose profiles share surname, name,
+	// patronymic and birth date; profiles without surname and name are skipped.
+	DuplicateUsers(ctx context.Context) ([]domain.DuplicateUserGroup, error)
 }
 
 type PageRepo interface {
@@ -69,16 +189,123 @@ type PageRepo interface {
 }
 
 type NewsRepo interface {
+	// List returns every item for the admin, newest first.
 	List(ctx context.Context) ([]domain.News, error)
+	// ListVisible returns the items public at now: published or due
+	// scheduled, not expired; pinned first.
+	ListVisible(ctx context.Context, now time.Time) ([]domain.News, error)
 	Get(ctx context.Context, id uuid.UUID) (*domain.News, error)
 	Create(ctx context.Context, n domain.News) (uuid.UUID, error)
 	Update(ctx context.Context, n domain.News) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// PublishDue publishes the scheduled items whose PublishAt has passed.
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
+	// ArchiveExpired archives the published items whose ExpiresAt has passed.
+	ArchiveExpired(ctx context.Context, now time.Time) (int64, error)
+	// ClaimNotification marks the item notified unless it already was; false
+	// means another run got there first.
+	ClaimNotification(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	// ReleaseNotification undoes a claim whose mailing could not be started.
+	ReleaseNotification(ctx context.Context, id uuid.UUID) error
+	// PendingNotifications returns the published items that ask for a
+	// mailing which has not gone out yet.
+	PendingNotifications(ctx context.Context) ([]domain.News, error)
+	SetCampaign(ctx context.Context, id, campaignID uuid.UUID) error
+}
+
+// SitemapRepo reports modification times for the sitemap.
+type SitemapRepo interface {
+	LastModified(ctx context.Context) (domain.SiteLastModified, error)
+}
+
+// EmailTemplateRepo stores admin-edited email templates. Every save adds a
+// version; only names and languages with an active version override the
+// template files.
+type EmailTemplateRepo interface {
+	// Active returns domain.ErrNotFound when the template is not overridden.
+	Active(ctx context.Context, name, lang string) (*domain.EmailTemplate, error)
+	ListActive(ctx context.Context) ([]domain.EmailTemplate, error)
+	// Save stores t as the next version and activates it.
+	Save(ctx context.Context, t domain.EmailTemplate, by uuid.UUID) (int, error)
+	// Deactivate falls back to the template file; the history is kept.
+	Deactivate(ctx context.Context, name, lang string) error
+	Versions(ctx context.Context, name, lang string) ([]domain.EmailTemplate, error)
+	Version(ctx context.Context, name, lang string, version int) (*domain.EmailTemplate, error)
+}
+
+// CampaignRepo stores mailing campaigns and their per-recipient delivery state.
+// Only drafts can be edited or deleted (domain.ErrInvalidState otherwise).
+type CampaignRepo interface {
+	Create(ctx context.Context, c domain.Campaign) (uuid.UUID, error)
+	Update(ctx context.Context, c domain.Campaign) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Get and List fill the delivery counters.
+	Get(ctx context.Context, id uuid.UUID) (*domain.Campaign, error)
+	List(ctx context.Context) ([]domain.Campaign, error)
+	// SegmentRecipients evaluates the segment now.
+	SegmentRecipients(ctx context.Context, seg domain.CampaignSegment) ([]domain.CampaignRecipient, error)
+	// Start moves a draft to SENDING and stores its recipients as PENDING.
+	Start(ctx context.Context, id uuid.UUID, recipients []domain.CampaignRecipient, at time.Time) error
+	// Cancel stops a SENDING campaign; pending recipients are not mailed.
+	Cancel(ctx context.Context, id uuid.UUID, at time.Time) error
+	Recipients(ctx context.Context, id uuid.UUID, status string) ([]domain.CampaignRecipient, error)
+	// NextPending returns up to limit pending recipients of SENDING campaigns,
+	// oldest campaign first.
+	NextPending(ctx context.Context, limit int) ([]domain.CampaignRecipient, error)
+	MarkRecipient(ctx context.Context, campaignID, userID uuid.UUID, status string, errMsg *string, at time.Time) error
+	// FinishCompleted marks SENDING campaigns without pending recipients SENT.
+	FinishCompleted(ctx context.Context, at time.Time) error
+	// Unsubscribe opts the token's user out of campaigns; domain.ErrNotFound
+	// for an unknown token.
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+// ReminderRepo finds incomplete checklists and records sent reminders.
+type ReminderRepo interface {
+	// Due returns users with items that became due before since and were not
+	// reminded after since. Disabled, unverified, rejected and cancelled users
+	// are skipped; only approved talks count.
+	Due(ctx context.Context, since time.Time) ([]domain.ReminderCandidate, error)
+	Record(ctx context.Context, items []domain.ReminderSent) error
+	// Sent lists reminders sent in [from, to), newest first; nil bounds are open.
+	Sent(ctx context.Context, from, to *time.Time) ([]domain.ReminderSent, error)
+}
+
+// BounceRepo stores bounce reports. Record marks the matching user's address
+// undeliverable on a hard bounce and opts the user out of campaigns on a
+// complaint.
+type BounceRepo interface {
+	Record(ctx context.Context, b domain.MailBounce) error
+	// Undeliverable reports whether the address belongs to a user whose mail
+	// hard-bounced.
+	Undeliverable(ctx context.Context, email string) (bool, error)
+}
+
+// TranslationRepo edits and looks up content translations. ru and en read and
+// write the entity's own columns. Unknown pages/ids give domain.ErrNotFound.
+type TranslationRepo interface {
+	// List returns every stored locale of one entity, ru and en first.
+	List(ctx context.Context, entity domain.ContentEntity, key string) ([]domain.Translation, error)
+	// ForKeys returns the stored translations of the given entities in the
+	// given locales other than ru and en.
+	ForKeys(ctx context.Context, entity domain.ContentEntity, keys, locales []string) ([]domain.Translation, error)
+	Upsert(ctx context.Context, t domain.Translation) error
+	Delete(ctx context.Context, entity domain.ContentEntity, key, locale string) error
+}
+
+type APITokenRepo interface {
+	Create(ctx context.Context, t domain.APIToken) (uuid.UUID, error)
+	ByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIToken, error)
+	ListAll(ctx context.Context) ([]domain.APIToken, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.APIToken, error)
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	TouchUsage(ctx context.Context, id uuid.UUID, ip string, at time.Time) error
 }
 
 type AuditRepo interface {
 	Insert(ctx context.Context, entry domain.AuditLog) error
-	List(ctx context.Context, limit int32) ([]domain.AuditLog, error)
+	List(ctx context.Context, f AuditListFilter) ([]domain.AuditLog, int, error)
 }
 
 type MaterialRepo interface {
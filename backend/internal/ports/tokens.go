@@ -20,4 +20,41 @@ type EmailTokenRepo interface {
 	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
 	ByTokenHash(ctx context.Context, tokenHash string) (*EmailVerifyToken, error)
 	MarkUsed(ctx context.Context, tokenID uuid.UUID) error
+	// InvalidateForUser marks every unused token of the user as used.
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+	// IssuedSince counts tokens created for the user after since and returns the newest creation time.
+	IssuedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, *time.Time, error)
+}
+
+// EmailChangeRequest is a pending switch of users.email. The confirm token goes
+// to the new address, the cancel token to the old one.
+type EmailChangeRequest struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	OldEmail         string
+	NewEmail         string
+	ConfirmTokenHash string
+	CancelTokenHash  string
+	ExpiresAt        time.Time // confirm link
+	CancelExpiresAt  time.Time
+	ConfirmedAt      *time.Time
+	CancelledAt      *time.Time
+	CreatedAt        time.Time
+}
+
+type EmailChangeRepo interface {
+	// Create replaces any still pending request of the same user.
+	Create(ctx context.Context, req EmailChangeRequest) error
+	ByConfirmHash(ctx context.Context, tokenHash string) (*EmailChangeRequest, error)
+	ByCancelHash(ctx context.Context, tokenHash string) (*EmailChangeRequest, error)
+	// Confirm atomically marks a still pending request confirmed, moves the
+	// user to the new address and revokes their sessions and verification
+	// tokens. It returns domain.ErrInvalidState when the request is no longer
+	// pending and domain.ErrEmailTaken when the address was claimed meanwhile.
+	Confirm(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Cancel atomically marks a request cancelled and, if it was confirmed and
+	// the user still has the new address, moves them back to the old one and
+	// revokes their sessions, reporting whether it did. It returns
+	// domain.ErrInvalidState when the request can no longer be cancelled.
+	Cancel(ctx context.Context, id uuid.UUID, at time.Time) (reverted bool, err error)
 }
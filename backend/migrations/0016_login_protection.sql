@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE login_attempts (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  failed_count int NOT NULL DEFAULT 0,
+  last_failed_at timestamptz NULL,
+  locked_until timestamptz NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_devices (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  fingerprint text NOT NULL,
+  user_agent text NOT NULL DEFAULT '',
+  last_ip text NOT NULL DEFAULT '',
+  first_seen_at timestamptz NOT NULL DEFAULT now(),
+  last_seen_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (user_id, fingerprint)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_devices;
+DROP TABLE IF EXISTS login_attempts;
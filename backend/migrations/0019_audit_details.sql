@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE audit_logs ADD COLUMN details jsonb;
+
+-- +goose Down
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS details;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE email_change_requests (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  old_email text NOT NULL,
+  new_email text NOT NULL,
+  confirm_token_hash text NOT NULL UNIQUE,
+  cancel_token_hash text NOT NULL UNIQUE,
+  expires_at timestamptz NOT NULL,
+  cancel_expires_at timestamptz NOT NULL,
+  confirmed_at timestamptz,
+  cancelled_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_email_change_requests_user_id ON email_change_requests(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS email_change_requests;
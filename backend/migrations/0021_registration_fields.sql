@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE registration_fields (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  key text NOT NULL UNIQUE,
+  type text NOT NULL CHECK (type IN ('text','select','checkbox','date','file')),
+  label_ru text NOT NULL,
+  label_en text NOT NULL,
+  help_ru text,
+  help_en text,
+  options jsonb NOT NULL DEFAULT '[]'::jsonb,
+  required boolean NOT NULL DEFAULT false,
+  validation jsonb NOT NULL DEFAULT '{}'::jsonb,
+  sort_order int NOT NULL DEFAULT 0,
+  active boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE registration_answers (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  answers jsonb NOT NULL DEFAULT '{}'::jsonb,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS registration_answers;
+DROP TABLE IF EXISTS registration_fields;
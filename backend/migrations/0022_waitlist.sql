@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('WAITING','APPROVED','REJECTED','WAITLISTED'));
+
+-- category '*' is the overall limit; other rows limit one participation category
+CREATE TABLE capacity_limits (
+  category text PRIMARY KEY,
+  capacity int NOT NULL CHECK (capacity >= 0)
+);
+
+CREATE TABLE waitlist_entries (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  position bigint NOT NULL,
+  queued_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_waitlist_entries_position ON waitlist_entries(position);
+
+-- +goose Down
+DROP TABLE IF EXISTS waitlist_entries;
+DROP TABLE IF EXISTS capacity_limits;
+UPDATE users SET status='WAITING' WHERE status='WAITLISTED';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('WAITING','APPROVED','REJECTED'));
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE talks DROP CONSTRAINT IF EXISTS talks_status_check;
+ALTER TABLE talks ADD CONSTRAINT talks_status_check CHECK (status IN ('WAITING','APPROVED','REJECTED','WITHDRAWN'));
+ALTER TABLE talks
+  ADD COLUMN withdrawn_at timestamptz,
+  ADD COLUMN withdrawal_reason text;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('WAITING','APPROVED','REJECTED','WAITLISTED','CANCELLED'));
+ALTER TABLE users
+  ADD COLUMN cancelled_at timestamptz,
+  ADD COLUMN cancellation_reason text;
+
+-- +goose Down
+UPDATE users SET status='REJECTED' WHERE status='CANCELLED';
+ALTER TABLE users DROP COLUMN IF EXISTS cancellation_reason, DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('WAITING','APPROVED','REJECTED','WAITLISTED'));
+
+UPDATE talks SET status='REJECTED' WHERE status='WITHDRAWN';
+ALTER TABLE talks DROP COLUMN IF EXISTS withdrawal_reason, DROP COLUMN IF EXISTS withdrawn_at;
+ALTER TABLE talks DROP CONSTRAINT IF EXISTS talks_status_check;
+ALTER TABLE talks ADD CONSTRAINT talks_status_check CHECK (status IN ('WAITING','APPROVED','REJECTED'));
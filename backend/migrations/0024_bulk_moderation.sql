@@ -0,0 +1,13 @@
+-- +goose Up
+-- one row per idempotency key; results are replayed when a bulk request is retried
+CREATE TABLE bulk_moderation_runs (
+  key text PRIMARY KEY,
+  actor_user_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  entity text NOT NULL CHECK (entity IN ('user','talk')),
+  status text NOT NULL,
+  results jsonb NOT NULL DEFAULT '[]'::jsonb,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS bulk_moderation_runs;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Search document of a talk: title (A), authors and affiliations (B), section
+-- titles (C) and abstract (D), indexed with both the Russian and the English
+-- configuration. TalkRepo recomputes it on every insert and update.
+-- +goose StatementBegin
+CREATE FUNCTION talk_search_vector(title text, affiliation text, abstract text, authors jsonb, section_id uuid)
+RETURNS tsvector
+LANGUAGE sql STABLE AS $$
+  WITH doc AS (
+    SELECT
+      title,
+      concat_ws(' ', affiliation,
+        (SELECT string_agg(concat_ws(' ', a->>'fullName', a->>'affiliation'), ' ')
+         FROM jsonb_array_elements(CASE WHEN jsonb_typeof(authors) = 'array' THEN authors ELSE '[]'::jsonb END) a)) AS people,
+      (SELECT concat_ws(' ', s.title_ru, s.title_en) FROM sections s WHERE s.id = section_id) AS section,
+      abstract
+  )
+  SELECT
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(people, '')), 'B') ||
+    setweight(to_tsvector('russian', coalesce(section, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(section, '')), 'C') ||
+    setweight(to_tsvector('russian', coalesce(abstract, '')), 'D') ||
+    setweight(to_tsvector('english', coalesce(abstract, '')), 'D')
+  FROM doc
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE talks ADD COLUMN search_vector tsvector;
+UPDATE talks SET search_vector = talk_search_vector(title, affiliation, abstract, authors, section_id);
+CREATE INDEX idx_talks_search_vector ON talks USING gin (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_talks_search_vector;
+ALTER TABLE talks DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS talk_search_vector(text, text, text, jsonb, uuid);
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- suspected duplicate talks, one row per pair (talk_a < talk_b)
+CREATE TABLE talk_duplicate_flags (
+  talk_a uuid NOT NULL REFERENCES talks(id) ON DELETE CASCADE,
+  talk_b uuid NOT NULL REFERENCES talks(id) ON DELETE CASCADE,
+  title_similarity real NOT NULL,
+  abstract_similarity real NOT NULL,
+  dismissed_at timestamptz,
+  dismissed_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (talk_a, talk_b),
+  CHECK (talk_a < talk_b)
+);
+
+CREATE INDEX idx_talk_duplicate_flags_talk_b ON talk_duplicate_flags(talk_b);
+
+-- +goose Down
+DROP TABLE IF EXISTS talk_duplicate_flags;
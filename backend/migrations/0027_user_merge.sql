@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+  ADD COLUMN disabled_at timestamptz,
+  ADD COLUMN merged_into uuid REFERENCES users(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS merged_into, DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- every saved revision of an email template; versions count up per (name, lang)
+CREATE TABLE email_template_versions (
+  name text NOT NULL,
+  lang text NOT NULL CHECK (lang IN ('ru','en')),
+  version int NOT NULL,
+  subject text NOT NULL,
+  html text NOT NULL,
+  text text NOT NULL,
+  created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (name, lang, version)
+);
+
+-- the active revision; templates without a row are read from templates/email
+CREATE TABLE email_templates (
+  name text NOT NULL,
+  lang text NOT NULL,
+  version int NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (name, lang),
+  FOREIGN KEY (name, lang, version) REFERENCES email_template_versions(name, lang, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS email_templates;
+DROP TABLE IF EXISTS email_template_versions;
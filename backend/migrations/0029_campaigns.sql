@@ -0,0 +1,46 @@
+-- +goose Up
+-- language for emails; NULL until known (treated as ru)
+ALTER TABLE users ADD COLUMN preferred_lang text CHECK (preferred_lang IN ('ru','en'));
+
+-- users who opted out of campaigns; transactional mail is still sent
+CREATE TABLE mail_unsubscribes (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE campaigns (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name text NOT NULL,
+  segment jsonb NOT NULL DEFAULT '{}'::jsonb,
+  subject_ru text NOT NULL,
+  body_ru text NOT NULL,
+  subject_en text NOT NULL,
+  body_en text NOT NULL,
+  status text NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT','SENDING','SENT','CANCELLED')),
+  created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  started_at timestamptz,
+  finished_at timestamptz
+);
+
+-- recipients are fixed when sending starts
+CREATE TABLE campaign_recipients (
+  campaign_id uuid NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  email text NOT NULL,
+  lang text NOT NULL,
+  status text NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING','SENT','FAILED','SKIPPED')),
+  error text,
+  unsubscribe_token text NOT NULL UNIQUE,
+  sent_at timestamptz,
+  PRIMARY KEY (campaign_id, user_id)
+);
+
+CREATE INDEX idx_campaign_recipients_pending ON campaign_recipients(campaign_id) WHERE status = 'PENDING';
+
+-- +goose Down
+DROP TABLE IF EXISTS campaign_recipients;
+DROP TABLE IF EXISTS campaigns;
+DROP TABLE IF EXISTS mail_unsubscribes;
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_lang;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- reminder emails about missing consents, license agreements and talk files;
+-- one row per user and kind each time a reminder is sent
+CREATE TABLE reminders_sent (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind text NOT NULL CHECK (kind IN ('CONSENTS','LICENSE_AGREEMENT','TALK_FILE')),
+  email text NOT NULL,
+  talks text[] NOT NULL DEFAULT '{}',
+  sent_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reminders_sent_user_kind ON reminders_sent(user_id, kind, sent_at DESC);
+CREATE INDEX idx_reminders_sent_sent_at ON reminders_sent(sent_at);
+
+-- +goose Down
+DROP TABLE reminders_sent;
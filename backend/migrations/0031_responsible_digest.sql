@@ -0,0 +1,15 @@
+-- +goose Up
+-- last change by the speaker (edit or new file), for the responsibles' digest
+ALTER TABLE talks ADD COLUMN revised_at timestamptz;
+
+-- how a section responsible address is notified about talk uploads
+CREATE TABLE responsible_preferences (
+  email text PRIMARY KEY CHECK (email = lower(email)),
+  mode text NOT NULL DEFAULT 'IMMEDIATE' CHECK (mode IN ('IMMEDIATE','DIGEST')),
+  last_digest_at timestamptz,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE responsible_preferences;
+ALTER TABLE talks DROP COLUMN revised_at;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- set by a hard bounce; cleared when the user changes the address
+ALTER TABLE users ADD COLUMN email_undeliverable_at timestamptz;
+ALTER TABLE users ADD COLUMN email_bounce_reason text;
+
+-- bounce and complaint notifications received from the provider webhook or
+-- the bounce mailbox
+CREATE TABLE mail_bounces (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  email text NOT NULL,
+  kind text NOT NULL CHECK (kind IN ('HARD','SOFT','COMPLAINT')),
+  reason text NOT NULL DEFAULT '',
+  source text NOT NULL,
+  user_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  received_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_mail_bounces_email ON mail_bounces(lower(email));
+
+-- +goose Down
+DROP TABLE mail_bounces;
+ALTER TABLE users DROP COLUMN email_bounce_reason;
+ALTER TABLE users DROP COLUMN email_undeliverable_at;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- content in locales beyond ru/en, which stay in the entities' own columns;
+-- body is the page/news body or the material description, unused for sections
+CREATE TABLE page_translations (
+  page_id uuid NOT NULL REFERENCES page_contents(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (page_id, locale)
+);
+
+CREATE TABLE news_translations (
+  news_id uuid NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (news_id, locale)
+);
+
+CREATE TABLE section_translations (
+  section_id uuid NOT NULL REFERENCES sections(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (section_id, locale)
+);
+
+CREATE TABLE material_translations (
+  material_id uuid NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (material_id, locale)
+);
+
+-- +goose Down
+DROP TABLE material_translations;
+DROP TABLE section_translations;
+DROP TABLE news_translations;
+DROP TABLE page_translations;
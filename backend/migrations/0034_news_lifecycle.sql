@@ -0,0 +1,34 @@
+-- +goose Up
+-- news lifecycle: drafts, scheduled publishing at publish_at, archiving at
+-- expires_at; published_at is set when an item goes public
+ALTER TABLE news ADD COLUMN status text NOT NULL DEFAULT 'PUBLISHED'
+  CHECK (status IN ('DRAFT','SCHEDULED','PUBLISHED','ARCHIVED'));
+ALTER TABLE news ADD COLUMN publish_at timestamptz;
+ALTER TABLE news ADD COLUMN expires_at timestamptz;
+ALTER TABLE news ADD COLUMN notify_subscribers boolean NOT NULL DEFAULT false;
+ALTER TABLE news ADD COLUMN notified_at timestamptz;
+ALTER TABLE news ADD COLUMN campaign_id uuid REFERENCES campaigns(id) ON DELETE SET NULL;
+ALTER TABLE news ADD COLUMN created_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE news ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+UPDATE news SET created_at = published_at, updated_at = published_at;
+ALTER TABLE news ALTER COLUMN published_at DROP NOT NULL;
+ALTER TABLE news ALTER COLUMN published_at DROP DEFAULT;
+ALTER TABLE news ADD CONSTRAINT news_scheduled_publish_at CHECK (status <> 'SCHEDULED' OR publish_at IS NOT NULL);
+
+CREATE INDEX idx_news_status_publish_at ON news(status, publish_at);
+
+-- +goose Down
+-- items that were never public would otherwise show up as published
+DELETE FROM news WHERE published_at IS NULL;
+ALTER TABLE news ALTER COLUMN published_at SET DEFAULT now();
+ALTER TABLE news ALTER COLUMN published_at SET NOT NULL;
+DROP INDEX idx_news_status_publish_at;
+ALTER TABLE news DROP CONSTRAINT news_scheduled_publish_at;
+ALTER TABLE news DROP COLUMN updated_at;
+ALTER TABLE news DROP COLUMN created_at;
+ALTER TABLE news DROP COLUMN campaign_id;
+ALTER TABLE news DROP COLUMN notified_at;
+ALTER TABLE news DROP COLUMN notify_subscribers;
+ALTER TABLE news DROP COLUMN expires_at;
+ALTER TABLE news DROP COLUMN publish_at;
+ALTER TABLE news DROP COLUMN status;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- last change of a section title, for the sitemap
+ALTER TABLE sections ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE sections DROP COLUMN updated_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- trigram indexes for the duplicate-talk check (pg_trgm's % operator)
+CREATE INDEX idx_talks_title_trgm ON talks USING gin (lower(title) gin_trgm_ops);
+CREATE INDEX idx_talks_abstract_trgm ON talks USING gin (lower(abstract) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_talks_abstract_trgm;
+DROP INDEX IF EXISTS idx_talks_title_trgm;
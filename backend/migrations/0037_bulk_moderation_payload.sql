@@ -0,0 +1,23 @@
+-- +goose Up
+-- hash of actor, entity, status, ids and filter; a reused key must carry the same payload
+ALTER TABLE bulk_moderation_runs ADD COLUMN payload_hash text;
+
+-- notifications of committed bulk moderation calls, sent by the deliver_bulk_mail job
+CREATE TABLE bulk_mail_queue (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  batch_id uuid NOT NULL,
+  to_email text NOT NULL,
+  subject text NOT NULL,
+  html_body text NOT NULL,
+  text_body text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  sent_at timestamptz,
+  failed_at timestamptz,
+  last_error text
+);
+
+CREATE INDEX idx_bulk_mail_queue_pending ON bulk_mail_queue(created_at) WHERE sent_at IS NULL AND failed_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS bulk_mail_queue;
+ALTER TABLE bulk_moderation_runs DROP COLUMN payload_hash;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- keep sections.updated_at current on every change of a section
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION touch_section() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at := now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_touch_section
+BEFORE UPDATE ON sections
+FOR EACH ROW EXECUTE FUNCTION touch_section();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_touch_section ON sections;
+DROP FUNCTION IF EXISTS touch_section();
@@ -0,0 +1,23 @@
+-- +goose Up
+-- throttle by normalized email so unknown addresses are delayed and locked
+-- exactly like existing accounts
+ALTER TABLE login_attempts ADD COLUMN email text;
+UPDATE login_attempts a SET email = lower(trim(u.email)) FROM users u WHERE u.id = a.user_id;
+DELETE FROM login_attempts a
+USING login_attempts b
+WHERE a.email = b.email AND a.ctid < b.ctid;
+ALTER TABLE login_attempts DROP COLUMN user_id;
+ALTER TABLE login_attempts ALTER COLUMN email SET NOT NULL;
+ALTER TABLE login_attempts ADD PRIMARY KEY (email);
+
+-- +goose Down
+DELETE FROM login_attempts a WHERE NOT EXISTS (SELECT 1 FROM users u WHERE lower(trim(u.email)) = a.email);
+ALTER TABLE login_attempts ADD COLUMN user_id uuid REFERENCES users(id) ON DELETE CASCADE;
+UPDATE login_attempts a SET user_id = u.id FROM users u WHERE lower(trim(u.email)) = a.email;
+DELETE FROM login_attempts a
+USING login_attempts b
+WHERE a.user_id = b.user_id AND a.ctid < b.ctid;
+ALTER TABLE login_attempts DROP CONSTRAINT login_attempts_pkey;
+ALTER TABLE login_attempts DROP COLUMN email;
+ALTER TABLE login_attempts ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE login_attempts ADD PRIMARY KEY (user_id);
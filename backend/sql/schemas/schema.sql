@@ -1,4 +1,5 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
 
 CREATE TABLE roles (
   id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -10,7 +11,14 @@ CREATE TABLE users (
   email text NOT NULL UNIQUE,
   password_hash text NOT NULL,
   email_verified boolean NOT NULL DEFAULT false,
-  status text NOT NULL CHECK (status IN ('WAITING','APPROVED','REJECTED')),
+  status text NOT NULL CHECK (status IN ('WAITING','APPROVED','REJECTED','WAITLISTED','CANCELLED')),
+  cancelled_at timestamptz,
+  cancellation_reason text,
+  disabled_at timestamptz,
+  merged_into uuid REFERENCES users(id) ON DELETE SET NULL,
+  preferred_lang text CHECK (preferred_lang IN ('ru','en')),
+  email_undeliverable_at timestamptz,
+  email_bounce_reason text,
   created_at timestamptz NOT NULL DEFAULT now(),
   updated_at timestamptz NOT NULL DEFAULT now()
 );
@@ -52,9 +60,22 @@ CREATE TABLE sections (
   id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
   title_ru text NOT NULL,
   title_en text NOT NULL,
-  sort_order int NOT NULL DEFAULT 0
+  sort_order int NOT NULL DEFAULT 0,
+  updated_at timestamptz NOT NULL DEFAULT now()
 );
 
+-- keep sections.updated_at current on every change of a section
+CREATE OR REPLACE FUNCTION touch_section() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at := now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_touch_section
+BEFORE UPDATE ON sections
+FOR EACH ROW EXECUTE FUNCTION touch_section();
+
 CREATE TABLE section_responsibles (
   section_id uuid NOT NULL REFERENCES sections(id) ON DELETE CASCADE,
   email text NOT NULL,
@@ -73,10 +94,14 @@ CREATE TABLE talks (
   abstract text NOT NULL CHECK (char_length(abstract) BETWEEN 250 AND 350),
   kind text NOT NULL CHECK (kind IN ('PLENARY','ORAL','POSTER')),
   authors jsonb NOT NULL,
-  status text NOT NULL DEFAULT 'WAITING' CHECK (status IN ('WAITING','APPROVED','REJECTED')),
+  status text NOT NULL DEFAULT 'WAITING' CHECK (status IN ('WAITING','APPROVED','REJECTED','WITHDRAWN')),
   reviewed_at timestamptz,
   schedule_time timestamptz,
   file_url text,
+  withdrawn_at timestamptz,
+  withdrawal_reason text,
+  revised_at timestamptz,
+  search_vector tsvector,
   created_at timestamptz NOT NULL DEFAULT now()
 );
 
@@ -87,7 +112,16 @@ CREATE TABLE news (
   title_en text NOT NULL,
   body_en text NOT NULL,
   pinned boolean NOT NULL DEFAULT false,
-  published_at timestamptz NOT NULL DEFAULT now()
+  status text NOT NULL DEFAULT 'PUBLISHED' CHECK (status IN ('DRAFT','SCHEDULED','PUBLISHED','ARCHIVED')),
+  publish_at timestamptz,
+  expires_at timestamptz,
+  published_at timestamptz,
+  notify_subscribers boolean NOT NULL DEFAULT false,
+  notified_at timestamptz,
+  campaign_id uuid,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT news_scheduled_publish_at CHECK (status <> 'SCHEDULED' OR publish_at IS NOT NULL)
 );
 
 CREATE TABLE page_contents (
@@ -106,6 +140,7 @@ CREATE TABLE audit_logs (
   action text NOT NULL,
   entity text NOT NULL,
   entity_id uuid,
+  details jsonb,
   created_at timestamptz NOT NULL DEFAULT now()
 );
 
@@ -149,3 +184,327 @@ CREATE TABLE consent_files (
 
 CREATE INDEX idx_consent_files_user_id ON consent_files(user_id);
 CREATE INDEX idx_consent_files_type ON consent_files(consent_type);
+
+CREATE TABLE login_attempts (
+  email text PRIMARY KEY, -- lower-cased; also tracks addresses without an account
+  failed_count int NOT NULL DEFAULT 0,
+  last_failed_at timestamptz NULL,
+  locked_until timestamptz NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_devices (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  fingerprint text NOT NULL,
+  user_agent text NOT NULL DEFAULT '',
+  last_ip text NOT NULL DEFAULT '',
+  first_seen_at timestamptz NOT NULL DEFAULT now(),
+  last_seen_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (user_id, fingerprint)
+);
+
+CREATE TABLE user_identities (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider text NOT NULL,
+  subject text NOT NULL,
+  email text NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT now(),
+  last_login_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+CREATE TABLE oidc_login_states (
+  state_hash text PRIMARY KEY,
+  provider text NOT NULL,
+  nonce text NOT NULL,
+  code_verifier text NOT NULL,
+  redirect_to text NOT NULL DEFAULT '',
+  expires_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE api_tokens (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name text NOT NULL,
+  prefix text NOT NULL,
+  token_hash text NOT NULL UNIQUE,
+  scopes text[] NOT NULL DEFAULT '{}',
+  expires_at timestamptz NOT NULL,
+  last_used_at timestamptz,
+  last_used_ip text,
+  revoked_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+CREATE TABLE email_change_requests (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  old_email text NOT NULL,
+  new_email text NOT NULL,
+  confirm_token_hash text NOT NULL UNIQUE,
+  cancel_token_hash text NOT NULL UNIQUE,
+  expires_at timestamptz NOT NULL,
+  cancel_expires_at timestamptz NOT NULL,
+  confirmed_at timestamptz,
+  cancelled_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_email_change_requests_user_id ON email_change_requests(user_id);
+
+CREATE TABLE registration_fields (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  key text NOT NULL UNIQUE,
+  type text NOT NULL CHECK (type IN ('text','select','checkbox','date','file')),
+  label_ru text NOT NULL,
+  label_en text NOT NULL,
+  help_ru text,
+  help_en text,
+  options jsonb NOT NULL DEFAULT '[]'::jsonb,
+  required boolean NOT NULL DEFAULT false,
+  validation jsonb NOT NULL DEFAULT '{}'::jsonb,
+  sort_order int NOT NULL DEFAULT 0,
+  active boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE registration_answers (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  answers jsonb NOT NULL DEFAULT '{}'::jsonb,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- category '*' is the overall limit; other rows limit one participation category
+CREATE TABLE capacity_limits (
+  category text PRIMARY KEY,
+  capacity int NOT NULL CHECK (capacity >= 0)
+);
+
+CREATE TABLE waitlist_entries (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  position bigint NOT NULL,
+  queued_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_waitlist_entries_position ON waitlist_entries(position);
+
+-- one row per idempotency key; results are replayed when a bulk request is retried
+CREATE TABLE bulk_moderation_runs (
+  key text PRIMARY KEY,
+  actor_user_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  entity text NOT NULL CHECK (entity IN ('user','talk')),
+  status text NOT NULL,
+  results jsonb NOT NULL DEFAULT '[]'::jsonb,
+  -- hash of actor, entity, status, ids and filter; a reused key must carry the same payload
+  payload_hash text,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- notifications of committed bulk moderation calls, sent by the deliver_bulk_mail job
+CREATE TABLE bulk_mail_queue (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  batch_id uuid NOT NULL,
+  to_email text NOT NULL,
+  subject text NOT NULL,
+  html_body text NOT NULL,
+  text_body text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  sent_at timestamptz,
+  failed_at timestamptz,
+  last_error text
+);
+
+CREATE INDEX idx_bulk_mail_queue_pending ON bulk_mail_queue(created_at) WHERE sent_at IS NULL AND failed_at IS NULL;
+
+-- Search document of a talk: title (A), authors and affiliations (B), section
+-- titles (C) and abstract (D), indexed with both the Russian and the English
+-- configuration. TalkRepo recomputes it on every insert and update.
+CREATE FUNCTION talk_search_vector(title text, affiliation text, abstract text, authors jsonb, section_id uuid)
+RETURNS tsvector
+LANGUAGE sql STABLE AS $$
+  WITH doc AS (
+    SELECT
+      title,
+      concat_ws(' ', affiliation,
+        (SELECT string_agg(concat_ws(' ', a->>'fullName', a->>'affiliation'), ' ')
+         FROM jsonb_array_elements(CASE WHEN jsonb_typeof(authors) = 'array' THEN authors ELSE '[]'::jsonb END) a)) AS people,
+      (SELECT concat_ws(' ', s.title_ru, s.title_en) FROM sections s WHERE s.id = section_id) AS section,
+      abstract
+  )
+  SELECT
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(people, '')), 'B') ||
+    setweight(to_tsvector('russian', coalesce(section, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(section, '')), 'C') ||
+    setweight(to_tsvector('russian', coalesce(abstract, '')), 'D') ||
+    setweight(to_tsvector('english', coalesce(abstract, '')), 'D')
+  FROM doc
+$$;
+
+CREATE INDEX idx_talks_search_vector ON talks USING gin (search_vector);
+
+-- suspected duplicate talks, one row per pair (talk_a < talk_b)
+CREATE TABLE talk_duplicate_flags (
+  talk_a uuid NOT NULL REFERENCES talks(id) ON DELETE CASCADE,
+  talk_b uuid NOT NULL REFERENCES talks(id) ON DELETE CASCADE,
+  title_similarity real NOT NULL,
+  abstract_similarity real NOT NULL,
+  dismissed_at timestamptz,
+  dismissed_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (talk_a, talk_b),
+  CHECK (talk_a < talk_b)
+);
+
+CREATE INDEX idx_talk_duplicate_flags_talk_b ON talk_duplicate_flags(talk_b);
+
+-- trigram indexes for the duplicate-talk check (pg_trgm's % operator)
+CREATE INDEX idx_talks_title_trgm ON talks USING gin (lower(title) gin_trgm_ops);
+CREATE INDEX idx_talks_abstract_trgm ON talks USING gin (lower(abstract) gin_trgm_ops);
+
+-- every saved revision of an email template; versions count up per (name, lang)
+CREATE TABLE email_template_versions (
+  name text NOT NULL,
+  lang text NOT NULL CHECK (lang IN ('ru','en')),
+  version int NOT NULL,
+  subject text NOT NULL,
+  html text NOT NULL,
+  text text NOT NULL,
+  created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (name, lang, version)
+);
+
+-- the active revision; templates without a row are read from templates/email
+CREATE TABLE email_templates (
+  name text NOT NULL,
+  lang text NOT NULL,
+  version int NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (name, lang),
+  FOREIGN KEY (name, lang, version) REFERENCES email_template_versions(name, lang, version)
+);
+
+-- users who opted out of campaigns; transactional mail is still sent
+CREATE TABLE mail_unsubscribes (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE campaigns (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name text NOT NULL,
+  segment jsonb NOT NULL DEFAULT '{}'::jsonb,
+  subject_ru text NOT NULL,
+  body_ru text NOT NULL,
+  subject_en text NOT NULL,
+  body_en text NOT NULL,
+  status text NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT','SENDING','SENT','CANCELLED')),
+  created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  started_at timestamptz,
+  finished_at timestamptz
+);
+
+-- recipients are fixed when sending starts
+CREATE TABLE campaign_recipients (
+  campaign_id uuid NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  email text NOT NULL,
+  lang text NOT NULL,
+  status text NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING','SENT','FAILED','SKIPPED')),
+  error text,
+  unsubscribe_token text NOT NULL UNIQUE,
+  sent_at timestamptz,
+  PRIMARY KEY (campaign_id, user_id)
+);
+
+CREATE INDEX idx_campaign_recipients_pending ON campaign_recipients(campaign_id) WHERE status = 'PENDING';
+
+-- reminder emails about missing consents, license agreements and talk files;
+-- one row per user and kind each time a reminder is sent
+CREATE TABLE reminders_sent (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind text NOT NULL CHECK (kind IN ('CONSENTS','LICENSE_AGREEMENT','TALK_FILE')),
+  email text NOT NULL,
+  talks text[] NOT NULL DEFAULT '{}',
+  sent_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reminders_sent_user_kind ON reminders_sent(user_id, kind, sent_at DESC);
+CREATE INDEX idx_reminders_sent_sent_at ON reminders_sent(sent_at);
+
+-- how a section responsible address is notified about talk uploads
+CREATE TABLE responsible_preferences (
+  email text PRIMARY KEY CHECK (email = lower(email)),
+  mode text NOT NULL DEFAULT 'IMMEDIATE' CHECK (mode IN ('IMMEDIATE','DIGEST')),
+  last_digest_at timestamptz,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- bounce and complaint notifications received from the provider webhook or
+-- the bounce mailbox
+CREATE TABLE mail_bounces (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  email text NOT NULL,
+  kind text NOT NULL CHECK (kind IN ('HARD','SOFT','COMPLAINT')),
+  reason text NOT NULL DEFAULT '',
+  source text NOT NULL,
+  user_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  received_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_mail_bounces_email ON mail_bounces(lower(email));
+
+-- content in locales beyond ru/en, which stay in the entities' own columns;
+-- body is the page/news body or the material description, unused for sections
+CREATE TABLE page_translations (
+  page_id uuid NOT NULL REFERENCES page_contents(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (page_id, locale)
+);
+
+CREATE TABLE news_translations (
+  news_id uuid NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (news_id, locale)
+);
+
+CREATE TABLE section_translations (
+  section_id uuid NOT NULL REFERENCES sections(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (section_id, locale)
+);
+
+CREATE TABLE material_translations (
+  material_id uuid NOT NULL REFERENCES materials(id) ON DELETE CASCADE,
+  locale text NOT NULL CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})?$' AND locale NOT IN ('ru','en')),
+  title text NOT NULL,
+  body text NOT NULL DEFAULT '',
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (material_id, locale)
+);
+
+ALTER TABLE news ADD CONSTRAINT news_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE SET NULL;
+CREATE INDEX idx_news_status_publish_at ON news(status, publish_at);
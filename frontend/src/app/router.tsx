@@ -12,6 +12,8 @@ import NewsDetailsPage from "../features/public/pages/NewsDetails";
 import Register from "../features/registration/pages/Register";
 import Login from "../features/auth/pages/Login";
 import VerifyEmail from "../features/auth/pages/VerifyEmail";
+import EmailChange from "../features/auth/pages/EmailChange";
+import Unsubscribe from "../features/auth/pages/Unsubscribe";
 import Dashboard from "../features/participant/pages/Dashboard";
 import Profile from "../features/participant/pages/Profile";
 import Talks from "../features/participant/pages/Talks";
@@ -20,6 +22,7 @@ import AdminLayout from "../features/admin/pages/AdminLayout";
 import Users from "../features/admin/pages/Users";
 import Sections from "../features/admin/pages/Sections";
 import Responsibles from "../features/admin/pages/Responsibles";
+import Translations from "../features/admin/pages/Translations";
 import NewsAdmin from "../features/admin/pages/News";
 import PagesAdmin from "../features/admin/pages/Pages";
 import AdminTalks from "../features/admin/pages/Talks";
@@ -65,6 +68,9 @@ export const router = createBrowserRouter([
       { path: "register", element: <RegistrationGuard><Register /></RegistrationGuard> },
       { path: "login", element: <Login /> },
       { path: "verify-email", element: <VerifyEmail /> },
+      { path: "email-change/confirm", element: <EmailChange mode="confirm" /> },
+      { path: "email-change/cancel", element: <EmailChange mode="cancel" /> },
+      { path: "unsubscribe", element: <Unsubscribe /> },
       {
         path: "cabinet",
         element: (
@@ -96,6 +102,7 @@ export const router = createBrowserRouter([
           { path: "responsibles", element: <Responsibles /> },
           { path: "news", element: <NewsAdmin /> },
           { path: "pages", element: <PagesAdmin /> },
+          { path: "translations", element: <Translations /> },
           { path: "talks", element: <AdminTalks /> },
           { path: "materials", element: <AdminMaterials /> },
           { path: "documents", element: <Documents /> },
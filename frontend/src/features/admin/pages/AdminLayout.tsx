@@ -7,6 +7,7 @@ const NAV = [
   { to: "/admin/responsibles", key: "admin.responsibles" },
   { to: "/admin/news", key: "admin.news" },
   { to: "/admin/pages", key: "admin.pages" },
+  { to: "/admin/translations", key: "admin.translations" },
   { to: "/admin/talks", key: "admin.talks" },
   { to: "/admin/materials", key: "admin.materials" },
   { to: "/admin/documents", key: "admin.documents" },
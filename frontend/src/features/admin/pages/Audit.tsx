@@ -1,14 +1,21 @@
-import { useQuery } from "@tanstack/react-query";
+import { keepPreviousData, useQuery } from "@tanstack/react-query";
+import { useState } from "react";
 import { useTranslation } from "react-i18next";
 import { adminListAudit } from "../../../shared/api";
 import { DataTable } from "../../../shared/ui/DataTable";
+import { Pager } from "../../../shared/ui/Pager";
 import { AuditLogEntry } from "../../../shared/types";
 
+const PAGE_SIZE = 50;
+
 export default function Audit() {
   const { t, i18n } = useTranslation();
+  const [offset, setOffset] = useState(0);
+  const [search, setSearch] = useState("");
   const auditQuery = useQuery({
-    queryKey: ["admin-audit", i18n.language],
-    queryFn: adminListAudit,
+    queryKey: ["admin-audit", i18n.language, offset, search],
+    queryFn: () => adminListAudit({ limit: PAGE_SIZE, offset, q: search }),
+    placeholderData: keepPreviousData,
   });
 
   return (
@@ -18,13 +25,24 @@ export default function Audit() {
         <h1 className="text-2xl font-bold text-slate-900 dark:text-white">{t("admin.auditTitle")}</h1>
       </div>
 
+      <input
+        type="search"
+        value={search}
+        onChange={(e) => {
+          setSearch(e.target.value);
+          setOffset(0);
+        }}
+        placeholder={t("admin.search")}
+        className="rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+      />
+
       {auditQuery.isLoading ? (
         <div className="animate-pulse rounded-xl border border-dashed border-slate-300 p-6 text-slate-400 dark:border-slate-700">
           {t("actions.loading")}
         </div>
       ) : (
         <DataTable<AuditLogEntry>
-          rows={auditQuery.data || []}
+          rows={auditQuery.data?.items || []}
           empty={t("admin.empty")}
           columns={[
             { header: "ID", render: (r) => r.id.slice(0, 6) },
@@ -38,6 +56,8 @@ export default function Audit() {
           ]}
         />
       )}
+
+      <Pager total={auditQuery.data?.total ?? 0} limit={PAGE_SIZE} offset={offset} onChange={setOffset} />
     </div>
   );
 }
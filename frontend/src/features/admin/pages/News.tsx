@@ -2,9 +2,10 @@ import { useMutation, useQuery } from "@tanstack/react-query";
 import { useEffect, useState } from "react";
 import { useForm } from "react-hook-form";
 import { useTranslation } from "react-i18next";
-import { adminCreateNews, adminDeleteNews, adminListNews, adminUpdateNews } from "../../../shared/api";
+import { adminCreateNews, adminDeleteNews, adminListNews, adminPreviewNews, adminUpdateNews } from "../../../shared/api";
 import { DataTable } from "../../../shared/ui/DataTable";
-import { NewsDto } from "../../../shared/types";
+import { MarkdownView } from "../../../shared/ui/MarkdownView";
+import { NewsDto, NewsInput, NewsStatus } from "../../../shared/types";
 
 type NewsForm = {
   titleRu: string;
@@ -12,18 +13,43 @@ type NewsForm = {
   titleEn: string;
   bodyEn: string;
   pinned: boolean;
+  status: NewsStatus;
+  publishAt: string;
+  expiresAt: string;
+  notifySubscribers: boolean;
 };
 
+const statuses: NewsStatus[] = ["DRAFT", "SCHEDULED", "PUBLISHED", "ARCHIVED"];
+
+// value for a datetime-local input in the browser's time zone
+function toLocalInput(value?: string | null) {
+  if (!value) return "";
+  const d = new Date(value);
+  return new Date(d.getTime() - d.getTimezoneOffset() * 60000).toISOString().slice(0, 16);
+}
+
+function toInput(data: NewsForm): NewsInput {
+  return { ...data, publishAt: data.publishAt || null, expiresAt: data.expiresAt || null };
+}
+
 export default function NewsAdmin() {
-  const { t } = useTranslation();
+  const { t, i18n } = useTranslation();
   const [editing, setEditing] = useState<NewsDto | null>(null);
+  const [previewId, setPreviewId] = useState<string | null>(null);
 
   const newsQuery = useQuery({
     queryKey: ["admin-news"],
     queryFn: adminListNews,
   });
 
-  const { register, handleSubmit, reset } = useForm<NewsForm>();
+  const previewQuery = useQuery({
+    queryKey: ["admin-news-preview", previewId, i18n.language],
+    queryFn: () => adminPreviewNews(previewId!),
+    enabled: !!previewId,
+  });
+
+  const { register, handleSubmit, reset, watch } = useForm<NewsForm>();
+  const status = watch("status");
 
   useEffect(() => {
     if (editing) {
@@ -33,6 +59,10 @@ export default function NewsAdmin() {
         titleEn: editing.titleEn,
         bodyEn: editing.bodyEn,
         pinned: editing.pinned,
+        status: editing.status,
+        publishAt: toLocalInput(editing.publishAt),
+        expiresAt: toLocalInput(editing.expiresAt),
+        notifySubscribers: editing.notifySubscribers,
       });
     } else {
       reset({
@@ -41,6 +71,10 @@ export default function NewsAdmin() {
         titleEn: "",
         bodyEn: "",
         pinned: false,
+        status: "DRAFT",
+        publishAt: "",
+        expiresAt: "",
+        notifySubscribers: false,
       });
     }
   }, [editing, reset]);
@@ -53,7 +87,7 @@ export default function NewsAdmin() {
     },
   });
   const updateMutation = useMutation({
-    mutationFn: ({ id, data }: { id: string; data: NewsForm }) => adminUpdateNews(id, data),
+    mutationFn: ({ id, data }: { id: string; data: NewsInput }) => adminUpdateNews(id, data),
     onSuccess: () => {
       newsQuery.refetch();
       setEditing(null);
@@ -66,9 +100,9 @@ export default function NewsAdmin() {
 
   const onSubmit = handleSubmit(async (data) => {
     if (editing) {
-      await updateMutation.mutateAsync({ id: editing.id, data });
+      await updateMutation.mutateAsync({ id: editing.id, data: toInput(data) });
     } else {
-      await createMutation.mutateAsync(data);
+      await createMutation.mutateAsync(toInput(data));
     }
   });
 
@@ -104,10 +138,47 @@ export default function NewsAdmin() {
           className="w-full rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
           {...register("bodyEn", { required: true })}
         />
-        <label className="flex items-center gap-2 text-sm text-slate-700 dark:text-slate-200">
-          <input type="checkbox" className="h-4 w-4" {...register("pinned")} />
-          {t("news.pinned")}
-        </label>
+        <div className="grid gap-3 md:grid-cols-3">
+          <label className="space-y-1 text-sm text-slate-700 dark:text-slate-200">
+            <span>{t("admin.newsStatus")}</span>
+            <select
+              className="w-full rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm dark:border-slate-700 dark:bg-slate-900"
+              {...register("status")}
+            >
+              {statuses.map((s) => (
+                <option key={s} value={s}>
+                  {t(`admin.newsStatus${s}`)}
+                </option>
+              ))}
+            </select>
+          </label>
+          <label className="space-y-1 text-sm text-slate-700 dark:text-slate-200">
+            <span>{t("admin.newsPublishAt")}</span>
+            <input
+              type="datetime-local"
+              className="w-full rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm dark:border-slate-700 dark:bg-slate-900"
+              {...register("publishAt", { required: status === "SCHEDULED" })}
+            />
+          </label>
+          <label className="space-y-1 text-sm text-slate-700 dark:text-slate-200">
+            <span>{t("admin.newsExpiresAt")}</span>
+            <input
+              type="datetime-local"
+              className="w-full rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm dark:border-slate-700 dark:bg-slate-900"
+              {...register("expiresAt")}
+            />
+          </label>
+        </div>
+        <div className="flex flex-wrap gap-4">
+          <label className="flex items-center gap-2 text-sm text-slate-700 dark:text-slate-200">
+            <input type="checkbox" className="h-4 w-4" {...register("pinned")} />
+            {t("news.pinned")}
+          </label>
+          <label className="flex items-center gap-2 text-sm text-slate-700 dark:text-slate-200">
+            <input type="checkbox" className="h-4 w-4" disabled={!!editing?.notifiedAt} {...register("notifySubscribers")} />
+            {editing?.notifiedAt ? t("admin.newsNotified") : t("admin.newsNotifySubscribers")}
+          </label>
+        </div>
         <div className="flex gap-3">
           <button type="submit" className="rounded-full bg-brand-700 px-4 py-2 text-sm font-semibold text-white shadow">
             {editing ? t("actions.save") : t("actions.create")}
@@ -124,6 +195,32 @@ export default function NewsAdmin() {
         </div>
       </form>
 
+      {previewId && (
+        <div className="card space-y-3 p-4">
+          <div className="flex items-center justify-between gap-3">
+            <p className="text-xs uppercase tracking-[0.3em] text-slate-500 dark:text-slate-300">
+              {t("admin.newsPreview")}
+              {previewQuery.data ? ` · ${t(`admin.newsStatus${previewQuery.data.status}`)} · ${previewQuery.data.locale}` : ""}
+            </p>
+            <button
+              type="button"
+              onClick={() => setPreviewId(null)}
+              className="rounded-full border border-slate-200 px-3 py-1 text-xs font-semibold text-slate-700 hover:bg-slate-100 dark:border-slate-700 dark:text-slate-100 dark:hover:bg-slate-800"
+            >
+              {t("actions.close")}
+            </button>
+          </div>
+          {previewQuery.data ? (
+            <>
+              <h2 className="text-xl font-bold text-slate-900 dark:text-white">{previewQuery.data.title}</h2>
+              <MarkdownView content={previewQuery.data.body} />
+            </>
+          ) : (
+            <div className="text-sm text-slate-400">{t("actions.loading")}</div>
+          )}
+        </div>
+      )}
+
       {newsQuery.isLoading ? (
         <div className="animate-pulse rounded-xl border border-dashed border-slate-300 p-6 text-slate-400 dark:border-slate-700">
           {t("actions.loading")}
@@ -137,6 +234,22 @@ export default function NewsAdmin() {
             { header: t("admin.newsTitleRu"), render: (n) => n.titleRu },
             { header: t("admin.newsTitleEn"), render: (n) => n.titleEn },
             { header: t("news.pinned"), render: (n) => (n.pinned ? "✓" : "-") },
+            {
+              header: t("admin.newsStatus"),
+              render: (n) => (
+                <div>
+                  <div>{t(`admin.newsStatus${n.status}`)}</div>
+                  {n.status === "SCHEDULED" && n.publishAt && (
+                    <div className="text-xs text-slate-500">{new Date(n.publishAt).toLocaleString(i18n.language)}</div>
+                  )}
+                  {n.expiresAt && n.status !== "ARCHIVED" && (
+                    <div className="text-xs text-slate-500">
+                      {t("admin.newsExpiresAt")}: {new Date(n.expiresAt).toLocaleString(i18n.language)}
+                    </div>
+                  )}
+                </div>
+              ),
+            },
             {
               header: t("actions.actions"),
               render: (n) => (
@@ -147,6 +260,12 @@ export default function NewsAdmin() {
                   >
                     {t("actions.edit")}
                   </button>
+                  <button
+                    className="rounded-full bg-slate-100 px-3 py-1 text-xs font-semibold text-slate-700 hover:bg-slate-200 dark:bg-slate-800 dark:text-slate-100"
+                    onClick={() => setPreviewId(n.id)}
+                  >
+                    {t("admin.newsPreview")}
+                  </button>
                   <button
                     className="rounded-full bg-red-100 px-3 py-1 text-xs font-semibold text-red-700 hover:bg-red-200 dark:bg-red-900/40 dark:text-red-100"
                     onClick={() => deleteMutation.mutate(n.id)}
@@ -1,7 +1,8 @@
 import { useMutation, useQuery } from "@tanstack/react-query";
 import { useEffect, useMemo, useState } from "react";
 import { useTranslation } from "react-i18next";
-import { adminListSectionResponsibles, adminSetSectionResponsibles } from "../../../shared/api";
+import { adminListSectionResponsibles, adminSetResponsibleMode, adminSetSectionResponsibles } from "../../../shared/api";
+import { ResponsibleMode } from "../../../shared/types";
 
 export default function Responsibles() {
   const { t, i18n } = useTranslation();
@@ -16,6 +17,11 @@ export default function Responsibles() {
     onSuccess: () => query.refetch(),
   });
 
+  const modeMutation = useMutation({
+    mutationFn: ({ email, mode }: { email: string; mode: ResponsibleMode }) => adminSetResponsibleMode(email, mode),
+    onSuccess: () => query.refetch(),
+  });
+
   const rows = useMemo(() => query.data || [], [query.data]);
 
   return (
@@ -37,6 +43,8 @@ export default function Responsibles() {
               key={row.sectionId}
               sectionTitle={i18n.language === "en" ? row.sectionTitleEn : row.sectionTitleRu}
               emails={row.emails}
+              modes={row.modes}
+              onModeChange={(email, mode) => modeMutation.mutate({ email, mode })}
               onSave={(emails) => saveMutation.mutate({ sectionId: row.sectionId, emails })}
               saving={saveMutation.isPending && saveMutation.variables?.sectionId === row.sectionId}
               t={t}
@@ -51,12 +59,14 @@ export default function Responsibles() {
 type CardProps = {
   sectionTitle: string;
   emails: string[];
+  modes: Record<string, ResponsibleMode>;
   onSave: (emails: string[]) => void;
+  onModeChange: (email: string, mode: ResponsibleMode) => void;
   saving: boolean;
   t: (key: string) => string;
 };
 
-function ResponsibleCard({ sectionTitle, emails, onSave, saving, t }: CardProps) {
+function ResponsibleCard({ sectionTitle, emails, modes, onSave, onModeChange, saving, t }: CardProps) {
   const [values, setValues] = useState<string[]>(["", "", ""]);
 
   useEffect(() => {
@@ -80,6 +90,23 @@ function ResponsibleCard({ sectionTitle, emails, onSave, saving, t }: CardProps)
           />
         ))}
       </div>
+      {emails.length > 0 && (
+        <div className="space-y-2">
+          {emails.map((email) => (
+            <label key={email} className="flex items-center gap-3 text-sm text-slate-700 dark:text-slate-200">
+              <span className="min-w-0 flex-1 truncate">{email}</span>
+              <select
+                value={modes[email] || "IMMEDIATE"}
+                onChange={(e) => onModeChange(email, e.target.value as ResponsibleMode)}
+                className="rounded-lg border border-slate-200 bg-white px-2 py-1 text-sm dark:border-slate-700 dark:bg-slate-900"
+              >
+                <option value="IMMEDIATE">{t("admin.notifyImmediate")}</option>
+                <option value="DIGEST">{t("admin.notifyDigest")}</option>
+              </select>
+            </label>
+          ))}
+        </div>
+      )}
       <button
         type="button"
         disabled={saving}
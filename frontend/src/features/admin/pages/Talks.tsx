@@ -1,11 +1,15 @@
-import { useMutation, useQuery } from "@tanstack/react-query";
+import { keepPreviousData, useMutation, useQuery } from "@tanstack/react-query";
 import { useEffect, useMemo, useState } from "react";
 import { useForm } from "react-hook-form";
 import { useTranslation } from "react-i18next";
+import { useSearchParams } from "react-router-dom";
 import { adminListTalks, adminUpdateTalk, adminListSections, adminSetTalkStatus } from "../../../shared/api";
 import { DataTable } from "../../../shared/ui/DataTable";
+import { Pager } from "../../../shared/ui/Pager";
 import { AdminTalkRow, TalkAuthor, UserStatus } from "../../../shared/types";
 
+const PAGE_SIZE = 50;
+
 type UpdateTalkForm = {
   sectionId?: string;
   scheduleTime?: string;
@@ -15,10 +19,15 @@ export default function AdminTalks() {
   const { t, i18n } = useTranslation();
   const [editing, setEditing] = useState<AdminTalkRow | null>(null);
   const [abstractModal, setAbstractModal] = useState<{ title: string; abstract: string } | null>(null);
+  const [searchParams, setSearchParams] = useSearchParams();
+  const sectionFilter = searchParams.get("section") || "";
+  const [offset, setOffset] = useState(0);
+  const [search, setSearch] = useState("");
 
   const talksQuery = useQuery({
-    queryKey: ["admin-talks", i18n.language],
-    queryFn: adminListTalks,
+    queryKey: ["admin-talks", i18n.language, sectionFilter, search, offset],
+    queryFn: () => adminListTalks({ limit: PAGE_SIZE, offset, sectionId: sectionFilter, q: search }),
+    placeholderData: keepPreviousData,
   });
 
   const sectionsQuery = useQuery({
@@ -71,7 +80,7 @@ export default function AdminTalks() {
   });
 
   const rows = useMemo(() => {
-    return (talksQuery.data || []).map((t) => ({
+    return (talksQuery.data?.items || []).map((t) => ({
       ...t,
       authors: parseAuthors(t.authorsJSON),
     }));
@@ -84,6 +93,34 @@ export default function AdminTalks() {
         <h1 className="text-2xl font-bold text-slate-900 dark:text-white">{t("admin.talksTitle")}</h1>
       </div>
 
+      <div className="flex flex-wrap gap-3">
+        <select
+          value={sectionFilter}
+          onChange={(e) => {
+            setSearchParams(e.target.value ? { section: e.target.value } : {});
+            setOffset(0);
+          }}
+          className="rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+        >
+          <option value="">{t("admin.allSections")}</option>
+          {(sectionsQuery.data || []).map((s) => (
+            <option key={s.id} value={s.id}>
+              {i18n.language === "en" ? s.titleEn : s.titleRu}
+            </option>
+          ))}
+        </select>
+        <input
+          type="search"
+          value={search}
+          onChange={(e) => {
+            setSearch(e.target.value);
+            setOffset(0);
+          }}
+          placeholder={t("admin.search")}
+          className="rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+        />
+      </div>
+
       {editing && (
         <form onSubmit={onSubmit} className="card space-y-3 p-4">
           <div className="mb-3 border-b border-slate-200 pb-3 dark:border-slate-700">
@@ -231,6 +268,8 @@ export default function AdminTalks() {
         />
       )}
 
+      <Pager total={talksQuery.data?.total ?? 0} limit={PAGE_SIZE} offset={offset} onChange={setOffset} />
+
       {abstractModal ? (
         <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/50 p-4" onClick={() => setAbstractModal(null)}>
           <div
@@ -0,0 +1,172 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+import { useEffect, useState } from "react";
+import { useTranslation } from "react-i18next";
+import { adminDeleteTranslation, adminListTranslations, adminPutTranslation, adminTranslationLocales } from "../../../shared/api";
+import { ContentEntity, ContentTranslation } from "../../../shared/types";
+
+const entities: { value: ContentEntity; key: string }[] = [
+  { value: "page", key: "admin.entityPage" },
+  { value: "news", key: "admin.entityNews" },
+  { value: "section", key: "admin.entitySection" },
+  { value: "material", key: "admin.entityMaterial" },
+];
+
+const baseLocales = ["ru", "en"];
+
+export default function Translations() {
+  const { t } = useTranslation();
+  const [entity, setEntity] = useState<ContentEntity>("page");
+  const [keyInput, setKeyInput] = useState("");
+  const [target, setTarget] = useState<{ entity: ContentEntity; key: string } | null>(null);
+
+  const localesQuery = useQuery({ queryKey: ["admin-translation-locales"], queryFn: adminTranslationLocales });
+
+  const query = useQuery({
+    queryKey: ["admin-translations", target?.entity, target?.key],
+    queryFn: () => adminListTranslations(target!.entity, target!.key),
+    enabled: !!target,
+  });
+
+  const saveMutation = useMutation({
+    mutationFn: (input: { locale: string; title: string; body: string }) =>
+      adminPutTranslation(target!.entity, target!.key, input.locale, { title: input.title, body: input.body }),
+    onSuccess: () => query.refetch(),
+  });
+
+  const deleteMutation = useMutation({
+    mutationFn: (locale: string) => adminDeleteTranslation(target!.entity, target!.key, locale),
+    onSuccess: () => query.refetch(),
+  });
+
+  const locales = localesQuery.data?.locales || baseLocales;
+  const byLocale = new Map((query.data || []).map((tr) => [tr.locale, tr]));
+
+  return (
+    <div className="space-y-4">
+      <div>
+        <p className="text-xs uppercase tracking-[0.3em] text-slate-500 dark:text-slate-300">{t("admin.translations")}</p>
+        <h1 className="text-2xl font-bold text-slate-900 dark:text-white">{t("admin.translationsTitle")}</h1>
+        <p className="text-sm text-slate-600 dark:text-slate-300">
+          {t("admin.translationsHint", { fallback: (localesQuery.data?.fallback || []).join(" → ") })}
+        </p>
+      </div>
+
+      <form
+        className="card flex flex-wrap items-end gap-3 p-4"
+        onSubmit={(e) => {
+          e.preventDefault();
+          if (keyInput.trim()) setTarget({ entity, key: keyInput.trim() });
+        }}
+      >
+        <select
+          value={entity}
+          onChange={(e) => setEntity(e.target.value as ContentEntity)}
+          className="rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm dark:border-slate-700 dark:bg-slate-900"
+        >
+          {entities.map((item) => (
+            <option key={item.value} value={item.value}>
+              {t(item.key)}
+            </option>
+          ))}
+        </select>
+        <input
+          value={keyInput}
+          onChange={(e) => setKeyInput(e.target.value)}
+          placeholder={t("admin.translationKey")}
+          className="min-w-[16rem] flex-1 rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+        />
+        <button type="submit" className="rounded-full bg-brand-700 px-4 py-2 text-sm font-semibold text-white shadow">
+          {t("actions.edit")}
+        </button>
+      </form>
+
+      {target && query.isLoading && (
+        <div className="animate-pulse rounded-xl border border-dashed border-slate-300 p-6 text-slate-400 dark:border-slate-700">
+          {t("actions.loading")}
+        </div>
+      )}
+      {target && query.isError && <div className="text-sm text-red-600">{t("admin.empty")}</div>}
+      {target && query.data && (
+        <div className="space-y-3">
+          {locales.map((locale) => (
+            <TranslationCard
+              key={`${target.entity}-${target.key}-${locale}`}
+              locale={locale}
+              value={byLocale.get(locale)}
+              withBody={target.entity !== "section"}
+              removable={!baseLocales.includes(locale)}
+              saving={saveMutation.isPending && saveMutation.variables?.locale === locale}
+              onSave={(title, body) => saveMutation.mutate({ locale, title, body })}
+              onDelete={() => deleteMutation.mutate(locale)}
+              t={t}
+            />
+          ))}
+        </div>
+      )}
+    </div>
+  );
+}
+
+type CardProps = {
+  locale: string;
+  value?: ContentTranslation;
+  withBody: boolean;
+  removable: boolean;
+  saving: boolean;
+  onSave: (title: string, body: string) => void;
+  onDelete: () => void;
+  t: (key: string) => string;
+};
+
+function TranslationCard({ locale, value, withBody, removable, saving, onSave, onDelete, t }: CardProps) {
+  const [title, setTitle] = useState("");
+  const [body, setBody] = useState("");
+
+  useEffect(() => {
+    setTitle(value?.title || "");
+    setBody(value?.body || "");
+  }, [value]);
+
+  return (
+    <div className="card space-y-3 p-4">
+      <div className="flex items-center justify-between text-sm">
+        <span className="font-semibold uppercase text-slate-900 dark:text-white">{locale}</span>
+        {!value && <span className="text-slate-500 dark:text-slate-400">{t("admin.translationMissing")}</span>}
+      </div>
+      <input
+        value={title}
+        onChange={(e) => setTitle(e.target.value)}
+        placeholder={t("admin.translationTitle")}
+        className="w-full rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+      />
+      {withBody && (
+        <textarea
+          value={body}
+          onChange={(e) => setBody(e.target.value)}
+          placeholder={t("admin.translationBody")}
+          rows={6}
+          className="w-full rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+        />
+      )}
+      <div className="flex gap-2">
+        <button
+          type="button"
+          disabled={saving || title.trim().length === 0}
+          onClick={() => onSave(title.trim(), body)}
+          className="rounded-full bg-brand-700 px-4 py-2 text-sm font-semibold text-white shadow disabled:opacity-60"
+        >
+          {saving ? t("actions.loading") : t("actions.save")}
+        </button>
+        {removable && value && (
+          <button
+            type="button"
+            onClick={onDelete}
+            className="rounded-full border border-slate-300 px-4 py-2 text-sm font-semibold text-slate-700 dark:border-slate-700 dark:text-slate-200"
+          >
+            {t("actions.delete")}
+          </button>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -1,39 +1,52 @@
-import { useMutation, useQuery } from "@tanstack/react-query";
+import { keepPreviousData, useMutation, useQuery } from "@tanstack/react-query";
 import { useState } from "react";
 import { useTranslation } from "react-i18next";
-import { adminListUsers, adminSetUserStatus, adminGetUserConsents } from "../../../shared/api";
+import { adminListUsers, adminSetUserStatus, adminGetUserConsents, ListPage } from "../../../shared/api";
 import { apiPost } from "../../../shared/api/client";
 import { queryClient } from "../../../app/queryClient";
 import { AdminUserDto, UserStatus } from "../../../shared/types";
+import { Pager } from "../../../shared/ui/Pager";
+
+const PAGE_SIZE = 50;
+const STATUSES: UserStatus[] = ["WAITING", "APPROVED", "WAITLISTED", "REJECTED"];
+
+type UsersPage = ListPage<AdminUserDto>;
+
+function withStatus(page: UsersPage | undefined, id: string, status: UserStatus) {
+  if (!page) return page;
+  return { ...page, items: page.items.map((user) => (user.id === id ? { ...user, status } : user)) };
+}
 
 export default function Users() {
   const { t } = useTranslation();
   const [expandedUser, setExpandedUser] = useState<string | null>(null);
   const [generatedPassword, setGeneratedPassword] = useState<string | null>(null);
+  const [offset, setOffset] = useState(0);
+  const [search, setSearch] = useState("");
+  const [statusFilter, setStatusFilter] = useState("");
 
   const usersQuery = useQuery({
-    queryKey: ["admin-users"],
-    queryFn: adminListUsers,
+    queryKey: ["admin-users", statusFilter, search, offset],
+    queryFn: () => adminListUsers({ limit: PAGE_SIZE, offset, status: statusFilter, q: search }),
+    placeholderData: keepPreviousData,
   });
 
   const statusMutation = useMutation({
     mutationFn: ({ id, status }: { id: string; status: UserStatus }) => adminSetUserStatus(id, status),
     onMutate: async ({ id, status }) => {
       await queryClient.cancelQueries({ queryKey: ["admin-users"] });
-      const previousUsers = queryClient.getQueryData<AdminUserDto[]>(["admin-users"]);
-      queryClient.setQueryData<AdminUserDto[]>(["admin-users"], (current = []) =>
-        current.map((user) => (user.id === id ? { ...user, status } : user)),
-      );
-      return { previousUsers };
+      const previousPages = queryClient.getQueriesData<UsersPage>({ queryKey: ["admin-users"] });
+      queryClient.setQueriesData<UsersPage>({ queryKey: ["admin-users"] }, (page) => withStatus(page, id, status));
+      return { previousPages };
     },
     onError: (_error, _variables, context) => {
-      if (context?.previousUsers) {
-        queryClient.setQueryData(["admin-users"], context.previousUsers);
+      for (const [key, page] of context?.previousPages || []) {
+        queryClient.setQueryData(key, page);
       }
     },
     onSuccess: (data, variables) => {
-      queryClient.setQueryData<AdminUserDto[]>(["admin-users"], (current = []) =>
-        current.map((user) => (user.id === variables.id ? { ...user, status: data.status } : user)),
+      queryClient.setQueriesData<UsersPage>({ queryKey: ["admin-users"] }, (page) =>
+        withStatus(page, variables.id, data.status),
       );
     },
   });
@@ -59,18 +72,54 @@ export default function Users() {
         <h1 className="text-2xl font-bold text-slate-900 dark:text-white">{t("admin.usersTitle")}</h1>
       </div>
 
+      <div className="flex flex-wrap gap-3">
+        <select
+          value={statusFilter}
+          onChange={(e) => {
+            setStatusFilter(e.target.value);
+            setOffset(0);
+          }}
+          className="rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+        >
+          <option value="">{t("admin.allStatuses")}</option>
+          {STATUSES.map((st) => (
+            <option key={st} value={st}>
+              {t(`status.${st.toLowerCase()}`)}
+            </option>
+          ))}
+        </select>
+        <input
+          type="search"
+          value={search}
+          onChange={(e) => {
+            setSearch(e.target.value);
+            setOffset(0);
+          }}
+          placeholder={t("admin.search")}
+          className="rounded-lg border border-slate-200 bg-white px-3 py-2 text-sm shadow-inner outline-none transition focus:border-brand-500 dark:border-slate-700 dark:bg-slate-900"
+        />
+      </div>
+
       {usersQuery.isLoading ? (
         <div className="animate-pulse rounded-xl border border-dashed border-slate-300 p-6 text-slate-400 dark:border-slate-700">
           {t("actions.loading")}
         </div>
       ) : (
         <div className="space-y-3">
-          {(usersQuery.data || []).map((user) => (
+          {(usersQuery.data?.items || []).map((user) => (
             <div key={user.id} className="card p-6">
               <div className="flex items-start justify-between gap-4 border-b border-slate-200 pb-4 dark:border-slate-700">
                 <div>
                   <div className="font-semibold text-slate-900 dark:text-white">{user.email}</div>
                   <div className="text-xs text-slate-500 dark:text-slate-400">{user.roles.join(", ")}</div>
+                  {user.emailUndeliverableAt && (
+                    <div
+                      className="mt-1 inline-block rounded bg-amber-100 px-2 py-0.5 text-xs font-semibold text-amber-800 dark:bg-amber-900 dark:text-amber-100"
+                      title={user.emailBounceReason || ""}
+                    >
+                      {t("admin.emailUndeliverable")}
+                    </div>
+                  )}
                 </div>
                 <button
                   onClick={() => setExpandedUser(expandedUser === user.id ? null : user.id)}
@@ -103,7 +152,7 @@ export default function Users() {
                       {t("admin.status")}
                     </div>
                     <div className="flex gap-2">
-                      {(["WAITING", "APPROVED", "REJECTED"] as UserStatus[]).map((st) => (
+                      {STATUSES.map((st) => (
                         <button
                           key={st}
                           onClick={() => statusMutation.mutate({ id: user.id, status: st })}
@@ -198,6 +247,8 @@ export default function Users() {
           ))}
         </div>
       )}
+
+      <Pager total={usersQuery.data?.total ?? 0} limit={PAGE_SIZE} offset={offset} onChange={setOffset} />
     </div>
   );
 }
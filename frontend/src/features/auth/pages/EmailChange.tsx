@@ -0,0 +1,44 @@
+import { useQuery } from "@tanstack/react-query";
+import { useTranslation } from "react-i18next";
+import { Link, useLocation } from "react-router-dom";
+import { cancelEmailChange, confirmEmailChange } from "../../../shared/api";
+
+export default function EmailChange({ mode }: { mode: "confirm" | "cancel" }) {
+  const { t } = useTranslation();
+  const location = useLocation();
+  const params = new URLSearchParams(location.search);
+  const token = params.get("token") || "";
+
+  const changeQuery = useQuery({
+    queryKey: ["email-change", mode, token],
+    queryFn: () => (mode === "confirm" ? confirmEmailChange(token) : cancelEmailChange(token)),
+    enabled: Boolean(token),
+    retry: false,
+    staleTime: Infinity,
+  });
+
+  return (
+    <div className="card space-y-4 p-6">
+      <h1 className="text-2xl font-bold text-slate-900 dark:text-white">{t("auth.emailChangeTitle")}</h1>
+      {changeQuery.isLoading ? (
+        <div className="text-slate-500 dark:text-slate-300">{t("actions.loading")}</div>
+      ) : changeQuery.isError || !token ? (
+        <div className="rounded-lg border border-red-200 bg-red-50/60 p-4 text-red-700 dark:border-red-800 dark:bg-red-900/40 dark:text-red-100">
+          {t("auth.emailChangeError")}
+        </div>
+      ) : (
+        <div className="rounded-lg border border-emerald-200 bg-emerald-50 p-4 text-emerald-800 dark:border-emerald-800 dark:bg-emerald-900/40 dark:text-emerald-100">
+          {mode === "confirm" ? t("auth.emailChangeConfirmed") : t("auth.emailChangeCancelled")}
+        </div>
+      )}
+      <div className="flex gap-3">
+        <Link to="/login" className="rounded-full bg-brand-700 px-4 py-2 text-sm font-semibold text-white shadow">
+          {t("actions.login")}
+        </Link>
+        <Link to="/" className="rounded-full border border-slate-200 px-4 py-2 text-sm font-semibold text-slate-700 dark:border-slate-700 dark:text-slate-100">
+          {t("actions.back")}
+        </Link>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,41 @@
+import { useQuery } from "@tanstack/react-query";
+import { useTranslation } from "react-i18next";
+import { Link, useLocation } from "react-router-dom";
+import { unsubscribe } from "../../../shared/api";
+
+export default function Unsubscribe() {
+  const { t } = useTranslation();
+  const location = useLocation();
+  const params = new URLSearchParams(location.search);
+  const token = params.get("token") || "";
+
+  const unsubscribeQuery = useQuery({
+    queryKey: ["unsubscribe", token],
+    queryFn: () => unsubscribe(token),
+    enabled: Boolean(token),
+    retry: false,
+    staleTime: Infinity,
+  });
+
+  return (
+    <div className="card space-y-4 p-6">
+      <h1 className="text-2xl font-bold text-slate-900 dark:text-white">{t("auth.unsubscribeTitle")}</h1>
+      {unsubscribeQuery.isLoading ? (
+        <div className="text-slate-500 dark:text-slate-300">{t("actions.loading")}</div>
+      ) : unsubscribeQuery.isError || !token ? (
+        <div className="rounded-lg border border-red-200 bg-red-50/60 p-4 text-red-700 dark:border-red-800 dark:bg-red-900/40 dark:text-red-100">
+          {t("auth.unsubscribeError")}
+        </div>
+      ) : (
+        <div className="rounded-lg border border-emerald-200 bg-emerald-50 p-4 text-emerald-800 dark:border-emerald-800 dark:bg-emerald-900/40 dark:text-emerald-100">
+          {t("auth.unsubscribed")}
+        </div>
+      )}
+      <div className="flex gap-3">
+        <Link to="/" className="rounded-full border border-slate-200 px-4 py-2 text-sm font-semibold text-slate-700 dark:border-slate-700 dark:text-slate-100">
+          {t("actions.back")}
+        </Link>
+      </div>
+    </div>
+  );
+}
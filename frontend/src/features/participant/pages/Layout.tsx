@@ -1,8 +1,10 @@
 import { NavLink, Outlet } from "react-router-dom";
 import { useTranslation } from "react-i18next";
+import { useAuth } from "../../../app/providers/AuthProvider";
 
 export default function ParticipantLayout() {
   const { t } = useTranslation();
+  const { user } = useAuth();
   const nav = [
     { to: "/cabinet", label: t("cabinet.dashboard") },
     { to: "/cabinet/profile", label: t("cabinet.profile") },
@@ -33,6 +35,11 @@ export default function ParticipantLayout() {
         </nav>
       </aside>
       <div className="space-y-4">
+        {user?.emailUndeliverable && (
+          <div className="rounded-xl border border-amber-300 bg-amber-50 p-4 text-sm text-amber-900 dark:border-amber-700 dark:bg-amber-950 dark:text-amber-100">
+            {t("cabinet.emailUndeliverable", { email: user.email })}
+          </div>
+        )}
         <Outlet />
       </div>
     </div>
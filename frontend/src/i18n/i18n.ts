@@ -3,7 +3,12 @@ import { initReactI18next } from "react-i18next";
 import en from "./en.json";
 import ru from "./ru.json";
 
+// ?lang=ru|en (used by the sitemap and feed links) wins over the saved choice
+const fromQuery =
+  typeof window !== "undefined" ? new URLSearchParams(window.location.search).get("lang") : null;
+
 const saved =
+  (fromQuery === "ru" || fromQuery === "en" ? fromQuery : null) ||
   (typeof window !== "undefined" && window.localStorage.getItem("i18nextLng")) ||
   (typeof document !== "undefined" ? document.documentElement.lang : null);
 
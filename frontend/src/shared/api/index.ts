@@ -4,9 +4,13 @@ import {
   AdminUserDto,
   AuditLogEntry,
   ConsentFile,
+  ContentEntity,
+  ContentTranslation,
   Material,
   MeResponse,
   NewsDto,
+  NewsInput,
+  NewsPreview,
   PageDto,
   ProfileDto,
   PublicNewsItem,
@@ -14,8 +18,10 @@ import {
   PublicParticipant,
   PublicSection,
   SectionDto,
+  ResponsibleMode,
   SectionResponsiblesRow,
   TalkDto,
+  TranslationLocales,
   UserStatus,
 } from "../types";
 
@@ -31,6 +37,10 @@ export function fetchMe() {
   return apiGet<MeResponse>("/api/me");
 }
 
+export function setPreferredLang(lang: "ru" | "en") {
+  return apiPut<{ ok: boolean }>("/api/me/language", { lang });
+}
+
 export function login(email: string, password: string) {
   return apiPost<LoginResponse>("/api/auth/login", { email, password });
 }
@@ -47,6 +57,22 @@ export function verifyEmail(token: string) {
   return apiGet<{ ok: boolean }>(`/api/auth/verify-email?token=${encodeURIComponent(token)}`);
 }
 
+export function requestEmailChange(newEmail: string, password: string) {
+  return apiPost<{ ok: boolean }>("/api/me/email", { newEmail, password });
+}
+
+export function confirmEmailChange(token: string) {
+  return apiPost<{ ok: boolean }>("/api/auth/email-change/confirm", { token });
+}
+
+export function cancelEmailChange(token: string) {
+  return apiPost<{ ok: boolean }>("/api/auth/email-change/cancel", { token });
+}
+
+export function unsubscribe(token: string) {
+  return apiPost<{ ok: boolean }>("/api/public/unsubscribe", { token });
+}
+
 export function fetchPublicPage(slug: string) {
   return apiGet<PublicPage>(`/api/public/pages/${slug}`);
 }
@@ -213,9 +239,24 @@ export function submitRegistration(payload: {
   return apiPost<{ ok: boolean }>("/api/registration/submit", payload);
 }
 
-export async function adminListUsers(): Promise<AdminUserDto[]> {
-  const rows = await apiGet<any[]>("/api/admin/users");
-  return rows.map((u) => ({
+// Admin listings are paged server-side ({items, total, limit, offset}).
+export type ListPage<T> = { items: T[]; total: number; limit: number; offset: number };
+
+// ListParams selects one page of an admin listing; extra keys are passed as
+// filters and empty values are left out.
+export type ListParams = { limit: number; offset: number } & Record<string, string | number | undefined>;
+
+async function listPage<T>(path: string, params: ListParams, map: (row: any) => T): Promise<ListPage<T>> {
+  const qs = new URLSearchParams();
+  for (const [key, value] of Object.entries(params)) {
+    if (value !== undefined && value !== "") qs.set(key, String(value));
+  }
+  const page = await apiGet<ListPage<any>>(`${path}?${qs.toString()}`);
+  return { ...page, items: page.items.map(map) };
+}
+
+export function adminListUsers(params: ListParams): Promise<ListPage<AdminUserDto>> {
+  return listPage("/api/admin/users", params, (u) => ({
     id: u.id ?? u.ID,
     email: u.email ?? u.Email,
     status: u.status ?? u.Status,
@@ -231,6 +272,8 @@ export async function adminListUsers(): Promise<AdminUserDto[]> {
     phone: u.phone ?? u.Phone ?? "",
     postalAddress: u.postalAddress ?? u.PostalAddress ?? "",
     consentAccepted: u.consentAccepted ?? u.ConsentAccepted ?? false,
+    emailUndeliverableAt: u.emailUndeliverableAt ?? null,
+    emailBounceReason: u.emailBounceReason ?? null,
   }));
 }
 
@@ -274,16 +317,33 @@ export async function adminListNews(): Promise<NewsDto[]> {
     titleEn: n.titleEn ?? n.TitleEn,
     bodyEn: n.bodyEn ?? n.BodyEn,
     pinned: n.pinned ?? n.Pinned ?? false,
+    status: n.status ?? "PUBLISHED",
+    publishAt: n.publishAt ?? null,
+    expiresAt: n.expiresAt ?? null,
     publishedAt: n.publishedAt ?? n.PublishedAt,
+    notifySubscribers: n.notifySubscribers ?? false,
+    notifiedAt: n.notifiedAt ?? null,
   }));
 }
 
-export function adminCreateNews(input: Omit<NewsDto, "id" | "publishedAt">) {
-  return apiPost<{ ok: boolean }>("/api/admin/news", input);
+function newsPayload(input: NewsInput) {
+  return {
+    ...input,
+    publishAt: input.publishAt ? new Date(input.publishAt).toISOString() : null,
+    expiresAt: input.expiresAt ? new Date(input.expiresAt).toISOString() : null,
+  };
+}
+
+export function adminCreateNews(input: NewsInput) {
+  return apiPost<{ ok: boolean; id: string }>("/api/admin/news", newsPayload(input));
+}
+
+export function adminUpdateNews(id: string, input: NewsInput) {
+  return apiPut<{ ok: boolean }>(`/api/admin/news/${id}`, newsPayload(input));
 }
 
-export function adminUpdateNews(id: string, input: Omit<NewsDto, "id" | "publishedAt">) {
-  return apiPut<{ ok: boolean }>(`/api/admin/news/${id}`, input);
+export function adminPreviewNews(id: string) {
+  return apiGet<NewsPreview>(`/api/admin/news/${id}/preview`);
 }
 
 export function adminDeleteNews(id: string) {
@@ -305,9 +365,8 @@ export function adminUpsertPage(slug: string, input: Omit<PageDto, "slug">) {
   return apiPut<{ ok: boolean }>(`/api/admin/pages/${slug}`, input);
 }
 
-export async function adminListTalks(): Promise<AdminTalkRow[]> {
-  const rows = await apiGet<any[]>("/api/admin/talks");
-  return rows.map((t) => ({
+export function adminListTalks(params: ListParams): Promise<ListPage<AdminTalkRow>> {
+  return listPage("/api/admin/talks", params, (t) => ({
     id: t.id ?? t.ID,
     title: t.title ?? t.Title,
     kind: t.kind ?? t.Kind,
@@ -336,13 +395,34 @@ export async function adminListSectionResponsibles(): Promise<SectionResponsible
     sectionTitleRu: r.sectionTitleRu ?? r.sectionTitleRU ?? r.SectionTitleRu ?? "",
     sectionTitleEn: r.sectionTitleEn ?? r.sectionTitleEN ?? r.SectionTitleEn ?? "",
     emails: Array.isArray(r.emails) ? r.emails : [],
+    modes: r.modes ?? {},
   }));
 }
 
+export function adminSetResponsibleMode(email: string, mode: ResponsibleMode) {
+  return apiPut<{ ok: boolean }>("/api/admin/section-responsibles/preferences", { email, mode });
+}
+
 export function adminSetSectionResponsibles(sectionId: string, emails: string[]) {
   return apiPut<{ ok: boolean }>(`/api/admin/sections/${sectionId}/responsibles`, { emails });
 }
 
+export function adminTranslationLocales() {
+  return apiGet<TranslationLocales>("/api/admin/translations/locales");
+}
+
+export function adminListTranslations(entity: ContentEntity, key: string) {
+  return apiGet<ContentTranslation[]>(`/api/admin/translations/${entity}/${encodeURIComponent(key)}`);
+}
+
+export function adminPutTranslation(entity: ContentEntity, key: string, locale: string, input: { title: string; body: string }) {
+  return apiPut<{ ok: boolean }>(`/api/admin/translations/${entity}/${encodeURIComponent(key)}/${locale}`, input);
+}
+
+export function adminDeleteTranslation(entity: ContentEntity, key: string, locale: string) {
+  return apiDelete<{ ok: boolean }>(`/api/admin/translations/${entity}/${encodeURIComponent(key)}/${locale}`);
+}
+
 export function adminUpdateTalk(id: string, input: { sectionId: string | null; scheduleTime: string | null }) {
   return apiPut<{ ok: boolean }>(`/api/admin/talks/${id}`, {
     sectionId: input.sectionId,
@@ -350,9 +430,8 @@ export function adminUpdateTalk(id: string, input: { sectionId: string | null; s
   });
 }
 
-export async function adminListAudit(): Promise<AuditLogEntry[]> {
-  const rows = await apiGet<any[]>("/api/admin/audit");
-  return rows.map((a) => ({
+export function adminListAudit(params: ListParams): Promise<ListPage<AuditLogEntry>> {
+  return listPage("/api/admin/audit", params, (a) => ({
     id: a.id ?? a.ID,
     actorUserID: a.actorUserID ?? a.ActorUserID ?? null,
     action: a.action ?? a.Action,
@@ -1,9 +1,13 @@
 import { useTranslation } from "react-i18next";
+import { useAuth } from "../../app/providers/AuthProvider";
+import { queryClient } from "../../app/queryClient";
+import { setPreferredLang } from "../api";
 
 const LANGS: Array<"ru" | "en"> = ["ru", "en"];
 
 export function LanguageSwitch() {
   const { i18n } = useTranslation();
+  const { user } = useAuth();
   const current = (i18n.language as "ru" | "en") || "ru";
 
   const setLang = (lng: "ru" | "en") => {
@@ -14,6 +18,12 @@ export function LanguageSwitch() {
     if (typeof window !== "undefined") {
       window.localStorage.setItem("i18nextLng", lng);
     }
+    // emails follow the language last chosen in the interface
+    if (user && user.preferredLang !== lng) {
+      setPreferredLang(lng)
+        .then(() => queryClient.invalidateQueries({ queryKey: ["me"] }))
+        .catch(() => undefined);
+    }
   };
 
   return (
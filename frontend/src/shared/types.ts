@@ -1,4 +1,4 @@
-export type UserStatus = "WAITING" | "APPROVED" | "REJECTED";
+export type UserStatus = "WAITING" | "APPROVED" | "REJECTED" | "WAITLISTED" | "CANCELLED";
 export type UserRole = "USER" | "PARTICIPANT" | "ADMIN" | "SECTION_ADMIN";
 
 export interface MeResponse {
@@ -6,6 +6,8 @@ export interface MeResponse {
   email: string;
   status: UserStatus;
   roles: UserRole[];
+  emailUndeliverable?: boolean;
+  preferredLang?: "ru" | "en";
 }
 
 export interface PublicPage {
@@ -91,6 +93,8 @@ export interface SectionDto {
   sortOrder: number;
 }
 
+export type NewsStatus = "DRAFT" | "SCHEDULED" | "PUBLISHED" | "ARCHIVED";
+
 export interface NewsDto {
   id: string;
   titleRu: string;
@@ -98,7 +102,23 @@ export interface NewsDto {
   bodyRu: string;
   bodyEn: string;
   pinned: boolean;
-  publishedAt: string;
+  status: NewsStatus;
+  publishAt?: string | null;
+  expiresAt?: string | null;
+  publishedAt?: string | null;
+  notifySubscribers: boolean;
+  notifiedAt?: string | null;
+}
+
+export type NewsInput = Pick<NewsDto, "titleRu" | "titleEn" | "bodyRu" | "bodyEn" | "pinned" | "status" | "notifySubscribers"> & {
+  publishAt: string | null;
+  expiresAt: string | null;
+};
+
+export interface NewsPreview extends PublicNewsItem {
+  status: NewsStatus;
+  publishAt?: string | null;
+  expiresAt?: string | null;
 }
 
 export interface PageDto {
@@ -127,6 +147,8 @@ export interface AdminUserDto {
   consentAccepted?: boolean;
   consentDataProcessingFile?: string;
   consentDataTransferFile?: string;
+  emailUndeliverableAt?: string | null;
+  emailBounceReason?: string | null;
 }
 
 export interface ConsentFile {
@@ -156,11 +178,30 @@ export interface AdminTalkRow {
   scheduleTime?: string | null;
 }
 
+export type ResponsibleMode = "IMMEDIATE" | "DIGEST";
+
 export interface SectionResponsiblesRow {
   sectionId: string;
   sectionTitleRu: string;
   sectionTitleEn: string;
   emails: string[];
+  modes: Record<string, ResponsibleMode>;
+}
+
+export type ContentEntity = "page" | "news" | "section" | "material";
+
+export interface ContentTranslation {
+  entity: ContentEntity;
+  key: string;
+  locale: string;
+  title: string;
+  body: string;
+  updatedAt?: string;
+}
+
+export interface TranslationLocales {
+  locales: string[];
+  fallback: string[];
 }
 
 export interface AuditLogEntry {
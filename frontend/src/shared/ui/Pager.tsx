@@ -0,0 +1,28 @@
+import { useTranslation } from "react-i18next";
+
+type Props = {
+  total: number;
+  limit: number;
+  offset: number;
+  onChange: (offset: number) => void;
+};
+
+export function Pager({ total, limit, offset, onChange }: Props) {
+  const { t } = useTranslation();
+  if (total <= limit && offset === 0) return null;
+  const from = total === 0 ? 0 : offset + 1;
+  const to = Math.min(offset + limit, total);
+  const button =
+    "rounded-full border border-slate-200 px-3 py-1 text-xs font-semibold text-slate-700 hover:bg-slate-100 disabled:opacity-40 dark:border-slate-700 dark:text-slate-100 dark:hover:bg-slate-800";
+  return (
+    <div className="flex items-center justify-end gap-3 text-sm text-slate-600 dark:text-slate-300">
+      <span>{t("pager.range", { from, to, total })}</span>
+      <button type="button" className={button} disabled={offset === 0} onClick={() => onChange(Math.max(0, offset - limit))}>
+        {t("pager.prev")}
+      </button>
+      <button type="button" className={button} disabled={to >= total} onClick={() => onChange(offset + limit)}>
+        {t("pager.next")}
+      </button>
+    </div>
+  );
+}
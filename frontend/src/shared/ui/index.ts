@@ -6,6 +6,7 @@ export { Badge } from './Badge';
 export { Input } from './Input';
 export { FormField } from './FormField';
 export { DataTable } from './DataTable';
+export { Pager } from './Pager';
 export { MarkdownView } from './MarkdownView';
 export { Container } from './Container';
 export { Section, SectionHeader } from './Section';